@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"expvar"
 	"fmt"
 	"net/http"
 	"os"
@@ -12,11 +13,19 @@ import (
 	"crypto-price-tracker-app/internal/application/services"
 	handlers "crypto-price-tracker-app/internal/delivery/http"
 	"crypto-price-tracker-app/internal/delivery/middleware"
+	"crypto-price-tracker-app/internal/domain/models"
+	"crypto-price-tracker-app/internal/domain/repository"
+	"crypto-price-tracker-app/internal/infrastructure/archive"
+	"crypto-price-tracker-app/internal/infrastructure/cache"
 	"crypto-price-tracker-app/internal/infrastructure/coingecko"
 	"crypto-price-tracker-app/internal/infrastructure/config"
-	"crypto-price-tracker-app/internal/infrastructure/postgres"
+	"crypto-price-tracker-app/internal/infrastructure/database"
+	"crypto-price-tracker-app/internal/infrastructure/memory"
+	"crypto-price-tracker-app/internal/infrastructure/migrate"
+	"crypto-price-tracker-app/migrations"
 
 	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
 )
 
@@ -34,16 +43,32 @@ func main() {
 	if err != nil {
 		logger.Warn("Failed to load config file, using defaults", zap.Error(err))
 		cfg = &config.Config{
+			Storage: config.StorageDatabase,
 			Database: config.DatabaseConfig{
+				Driver:   config.DriverPostgres,
 				Host:     "postgres",
 				Port:     5432,
 				User:     "postgres",
 				Password: "password",
 				DBName:   "crypto_tracker",
 				SSLMode:  "disable",
+
+				MaxOpenConns:       25,
+				MaxIdleConns:       10,
+				ConnMaxLifetime:    30 * time.Minute,
+				ConnMaxIdleTime:    5 * time.Minute,
+				ConnectRetries:     10,
+				ConnectBackoff:     time.Second,
+				SlowQueryThreshold: 200 * time.Millisecond,
+				StatsInterval:      time.Minute,
+
+				ReplicaMaxLag:        30 * time.Second,
+				ReplicaCheckInterval: 10 * time.Second,
 			},
 			API: config.APIConfig{
-				Port: "8080",
+				Port:             "8080",
+				StaleMultiplier:  3,
+				DeprecatedSunset: "2027-04-18",
 			},
 			Worker: config.WorkerConfig{
 				Interval: 60,
@@ -51,31 +76,152 @@ func main() {
 			Logging: config.LoggingConfig{
 				Level: "info",
 			},
+			Validation: config.ValidationConfig{
+				Enabled: true,
+				Default: config.PriceRulesConfig{
+					MaxChangePercent: 50,
+					MaxQuoteAge:      time.Hour,
+				},
+			},
+			Partitioning: config.PartitioningConfig{
+				Enabled:       true,
+				MonthsAhead:   3,
+				CheckInterval: time.Hour,
+			},
+			Rollups: config.RollupsConfig{
+				Enabled:         true,
+				RefreshInterval: time.Minute,
+				Lookback:        time.Hour,
+				Retention: config.RollupRetentionConfig{
+					Minute: 30 * 24 * time.Hour,
+					Hour:   365 * 24 * time.Hour,
+				},
+			},
+			Cache: config.CacheConfig{
+				Enabled:       true,
+				CurrencyTTL:   time.Minute,
+				PriceTTL:      30 * time.Second,
+				StatsInterval: 5 * time.Minute,
+			},
+			Archive: config.ArchiveConfig{
+				Dir:       "archive",
+				Format:    "parquet",
+				OlderThan: 365 * 24 * time.Hour,
+				Interval:  24 * time.Hour,
+			},
 		}
 	}
 
-	db, err := postgres.NewConnection(&postgres.Config{
-		Host:     cfg.Database.Host,
-		Port:     fmt.Sprintf("%d", cfg.Database.Port),
-		User:     cfg.Database.User,
-		Password: cfg.Database.Password,
-		DBName:   cfg.Database.DBName,
-		SSLMode:  cfg.Database.SSLMode,
-	})
-	if err != nil {
-		logger.Fatal("Failed to connect to database", zap.Error(err))
-	}
-	defer postgres.CloseConnection(db)
+	var (
+		currencyRepo   repository.CurrencyRepository
+		priceRepo      repository.PriceRepository
+		quarantineRepo repository.QuarantineRepository
+		auditRepo      repository.AuditRepository
+		unitOfWork     repository.UnitOfWork
+		priceEvents    repository.PriceEventSource
+		priceCache     *cache.PriceRepository
+		archiveService *services.ArchiveService
+	)
+
+	backgroundCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+
+	switch cfg.Storage {
+	case config.StorageMemory:
+		if len(os.Args) > 1 && os.Args[1] == "migrate" {
+			logger.Fatal("Migrations require database storage")
+		}
+
+		logger.Warn("Using in-memory storage, data is lost on restart")
+		store := memory.NewStore()
+		currencyRepo = memory.NewCurrencyRepository(store)
+		priceRepo = memory.NewPriceRepository(store)
+		quarantineRepo = memory.NewQuarantineRepository(store)
+		auditRepo = memory.NewAuditRepository(store)
+		unitOfWork = memory.NewUnitOfWork(store)
+	case config.StorageDatabase:
+		db, err := database.Open(cfg.Database, logger)
+		if err != nil {
+			logger.Fatal("Failed to connect to database", zap.Error(err))
+		}
+		defer database.Close(db)
+		go database.ReportPoolStats(backgroundCtx, cfg.Database.StatsInterval, logger)
+
+		migrator, err := migrate.New(db, migrations.FS)
+		if err != nil {
+			logger.Fatal("Failed to load migrations", zap.Error(err))
+		}
+
+		if len(os.Args) > 1 && os.Args[1] == "migrate" {
+			if err := migrate.RunCommand(context.Background(), migrator, os.Args[2:], os.Stdout); err != nil {
+				logger.Fatal("Migration command failed", zap.Error(err))
+			}
+			return
+		}
 
-	currencyRepo := postgres.NewCurrencyRepository(db)
-	priceRepo := postgres.NewPriceRepository(db)
+		if err := migrator.Check(context.Background()); err != nil {
+			logger.Fatal("Database schema is not up to date, run the migrate up command", zap.Error(err))
+		}
+
+		replicas, err := database.OpenReplicas(cfg.Database, logger)
+		if err != nil {
+			logger.Fatal("Failed to connect to read replica", zap.Error(err))
+		}
+		defer replicas.Close()
+		go replicas.Monitor(backgroundCtx, cfg.Database.ReplicaCheckInterval)
+
+		repos := database.NewRepositories(db, replicas)
+		currencyRepo = repos.Currencies
+		priceRepo = repos.Prices
+		quarantineRepo = repos.Quarantine
+		auditRepo = repos.Audit
+		unitOfWork = repos.UnitOfWork
+
+		// Memory storage needs no cache, so only database repositories are wrapped.
+		if cfg.Cache.Enabled {
+			currencyCache := cache.NewCurrencyRepository(currencyRepo, cfg.Cache.CurrencyTTL)
+			currencyRepo = currencyCache
+			priceCache = cache.NewPriceRepository(priceRepo, cfg.Cache.PriceTTL)
+			priceRepo = priceCache
+			unitOfWork = cache.NewUnitOfWork(unitOfWork, currencyCache, priceCache)
+			go cache.ReportStats(backgroundCtx, cfg.Cache.StatsInterval, logger)
+		}
+
+		priceEvents = database.NewPriceListener(cfg.Database, logger)
+
+		// Deleting a currency with prices=archive writes to the same
+		// directory as the worker's archive job; the store locks the manifest
+		// so the two do not overwrite each other's entries.
+		archiveStore, err := archive.NewFileStore(cfg.Archive.Dir, models.ArchiveFormat(cfg.Archive.Format))
+		if err != nil {
+			logger.Fatal("Invalid archive configuration", zap.Error(err))
+		}
+		archiveService = services.NewArchiveService(repos.Archive, priceRepo, archiveStore, logger)
+	default:
+		logger.Fatal("Unknown storage", zap.String("storage", cfg.Storage))
+	}
 
 	coingeckoClient := coingecko.NewClient("https://api.coingecko.com/api/v3")
 
-	currencyService := services.NewCurrencyService(currencyRepo, priceRepo, logger)
-	priceService := services.NewPriceService(priceRepo, currencyRepo, coingeckoClient, logger)
+	currencyService := services.NewCurrencyService(currencyRepo, priceRepo, unitOfWork, archiveService, cfg.API.StaleMultiplier, logger)
+	priceService := services.NewPriceService(priceRepo, currencyRepo, quarantineRepo, coingeckoClient, newPriceValidator(cfg.Validation), cfg.API.StaleMultiplier, logger)
+	quarantineService := services.NewQuarantineService(quarantineRepo, unitOfWork, logger)
 
-	handlers := handlers.NewHandlers(currencyService, priceService)
+	priceStreamService := services.NewPriceStreamService(currencyRepo, logger)
+
+	auditService := services.NewAuditService(auditRepo, logger)
+
+	handlers := handlers.NewHandlers(currencyService, priceService, quarantineService, priceStreamService, auditService)
+
+	if priceEvents != nil {
+		go listenPriceEvents(backgroundCtx, priceEvents, priceCache, priceStreamService, logger)
+	}
+
+	// Memory storage is private to this process, so there is no worker that
+	// could fill it; the API fetches prices itself instead.
+	if cfg.Storage == config.StorageMemory {
+		go runUpdater(backgroundCtx, priceService, cfg.Worker.Interval, logger)
+	}
 
 	gin.SetMode(gin.ReleaseMode)
 	router := gin.New()
@@ -83,8 +229,13 @@ func main() {
 	router.Use(middleware.Logger(logger))
 	router.Use(middleware.Recovery(logger))
 	router.Use(middleware.CORSMiddleware())
+	router.Use(middleware.RequestContext())
 
-	setupRoutes(router, handlers)
+	sunset, err := time.Parse(time.DateOnly, cfg.API.DeprecatedSunset)
+	if err != nil {
+		logger.Fatal("Invalid sunset date of deprecated routes", zap.String("deprecated_sunset", cfg.API.DeprecatedSunset), zap.Error(err))
+	}
+	setupRoutes(router, handlers, sunset)
 
 	server := &http.Server{
 		Addr:    ":" + cfg.API.Port,
@@ -98,6 +249,26 @@ func main() {
 		}
 	}()
 
+	// /debug/vars exposes the command line, memory and pool statistics, so it
+	// is served only on its own listener, which should not be reachable from
+	// outside.
+	var debugServer *http.Server
+	if cfg.API.DebugAddr != "" {
+		debugMux := http.NewServeMux()
+		debugMux.Handle("/debug/vars", expvar.Handler())
+		debugServer = &http.Server{
+			Addr:    cfg.API.DebugAddr,
+			Handler: debugMux,
+		}
+
+		go func() {
+			logger.Info("Starting debug server", zap.String("address", debugServer.Addr))
+			if err := debugServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logger.Fatal("Failed to start debug server", zap.Error(err))
+			}
+		}()
+	}
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
@@ -107,6 +278,12 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
+	if debugServer != nil {
+		if err := debugServer.Shutdown(ctx); err != nil {
+			logger.Error("Debug server forced to shutdown", zap.Error(err))
+		}
+	}
+
 	if err := server.Shutdown(ctx); err != nil {
 		logger.Fatal("Server forced to shutdown", zap.Error(err))
 	}
@@ -121,15 +298,122 @@ func initLogger() (*zap.Logger, error) {
 	return config.Build()
 }
 
-func setupRoutes(router *gin.Engine, handlers *handlers.Handlers) {
+// listenPriceEvents keeps the latest price cache in sync with prices stored
+// by the worker and pushes them to stream subscribers. priceCache may be nil.
+func listenPriceEvents(ctx context.Context, events repository.PriceEventSource, priceCache *cache.PriceRepository, stream *services.PriceStreamService, logger *zap.Logger) {
+	handle := func(event models.PriceEvent) {
+		if priceCache != nil {
+			priceCache.Invalidate(event.CurrencyID)
+		}
+		stream.Publish(ctx, event)
+	}
+	resync := func() {
+		if priceCache != nil {
+			priceCache.InvalidateAll()
+		}
+	}
+
+	if err := events.Listen(ctx, handle, resync); err != nil {
+		logger.Error("Price event listener stopped", zap.Error(err))
+	}
+}
+
+// newPriceValidator applies the same rules as the worker to prices fetched by
+// the in-process updater.
+func newPriceValidator(cfg config.ValidationConfig) *services.PriceValidator {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	overrides := make(map[string]services.PriceRules, len(cfg.Currencies))
+	for symbol, rules := range cfg.Currencies {
+		overrides[symbol] = toPriceRules(rules)
+	}
+	return services.NewPriceValidator(toPriceRules(cfg.Default), overrides)
+}
+
+func toPriceRules(cfg config.PriceRulesConfig) services.PriceRules {
+	return services.PriceRules{
+		MinPrice:         decimal.NewFromFloat(cfg.MinPrice),
+		MaxPrice:         decimal.NewFromFloat(cfg.MaxPrice),
+		MaxChangePercent: cfg.MaxChangePercent,
+		MaxQuoteAge:      cfg.MaxQuoteAge,
+	}
+}
+
+func runUpdater(ctx context.Context, priceService *services.PriceService, interval int, logger *zap.Logger) {
+	ticker := time.NewTicker(time.Duration(interval) * time.Second)
+	defer ticker.Stop()
+
+	logger.Info("In-process price updater started", zap.Int("interval_seconds", interval))
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := priceService.UpdatePrices(ctx); err != nil {
+				logger.Error("Failed to update prices", zap.Error(err))
+			}
+		}
+	}
+}
+
+// currencyRoutesDeprecatedAt is when the /currency routes gave way to
+// /currencies.
+var currencyRoutesDeprecatedAt = time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC)
+
+// setupRoutes registers the API. The deprecated /currency routes announce
+// sunset as the date after which they may be removed.
+func setupRoutes(router *gin.Engine, handlers *handlers.Handlers, sunset time.Time) {
 	v1 := router.Group("/api/v1")
 	{
+		currencies := v1.Group("/currencies")
+		{
+			currencies.GET("", handlers.GetAllCurrencies)
+			currencies.POST("", handlers.AddCurrency)
+			currencies.GET("/:symbol", handlers.GetCurrency)
+			currencies.PATCH("/:symbol", handlers.UpdateCurrency)
+			currencies.DELETE("/:symbol", handlers.DeleteCurrency)
+			currencies.GET("/:symbol/candles", handlers.GetCandles)
+			currencies.GET("/:symbol/history", handlers.GetPriceHistory)
+			currencies.POST("/:symbol/status", handlers.ChangeCurrencyStatus)
+			currencies.POST("/:symbol/restore", handlers.RestoreCurrency)
+			currencies.GET("/:symbol/transitions", handlers.ListCurrencyTransitions)
+		}
+
+		// Routes with a /currencies equivalent are kept for existing clients
+		// and point them to it.
+		deprecated := func(successor string) gin.HandlerFunc {
+			return middleware.Deprecated(currencyRoutesDeprecatedAt, sunset, "/api/v1/currencies"+successor)
+		}
 		currency := v1.Group("/currency")
 		{
-			currency.POST("/add", handlers.AddCurrency)
-			currency.POST("/remove", handlers.RemoveCurrency)
+			currency.POST("/add", deprecated(""), handlers.AddCurrency)
+			// The symbol of /remove comes in the body, so its successor, a
+			// PATCH with {"active": false}, is given as a URI template.
+			currency.POST("/remove", deprecated("/{symbol}"), handlers.RemoveCurrency)
 			currency.GET("/price", handlers.GetPrice)
-			currency.GET("/list", handlers.GetAllCurrencies)
+			currency.GET("/list", deprecated(""), handlers.GetAllCurrencies)
+			currency.GET("/stale", handlers.GetStaleCurrencies)
+			currency.GET("/:symbol/candles", deprecated("/:symbol/candles"), handlers.GetCandles)
+			currency.GET("/:symbol/history", deprecated("/:symbol/history"), handlers.GetPriceHistory)
+			currency.POST("/:symbol/status", deprecated("/:symbol/status"), handlers.ChangeCurrencyStatus)
+			currency.DELETE("/:symbol", deprecated("/:symbol"), handlers.DeleteCurrency)
+			currency.POST("/:symbol/restore", deprecated("/:symbol/restore"), handlers.RestoreCurrency)
+			currency.GET("/:symbol/transitions", deprecated("/:symbol/transitions"), handlers.ListCurrencyTransitions)
+		}
+
+		v1.GET("/prices/latest", handlers.GetLatestPrices)
+		v1.GET("/prices/stream", handlers.StreamPrices)
+		v1.POST("/prices/lookup", handlers.LookupPrices)
+		v1.GET("/audit", handlers.ListAudit)
+
+		quarantine := v1.Group("/quarantine")
+		{
+			quarantine.GET("", handlers.ListQuarantine)
+			quarantine.POST("/:id/approve", handlers.ApproveQuarantined)
+			quarantine.POST("/:id/reject", handlers.RejectQuarantined)
 		}
 	}
 
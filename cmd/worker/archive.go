@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"crypto-price-tracker-app/internal/application/services"
+	"crypto-price-tracker-app/internal/domain/models"
+	"crypto-price-tracker-app/internal/domain/repository"
+	"crypto-price-tracker-app/internal/infrastructure/archive"
+	"crypto-price-tracker-app/internal/infrastructure/config"
+
+	"go.uber.org/zap"
+)
+
+const archiveUsage = `usage: worker archive <command>
+
+commands:
+  run [YYYY-MM]            archive every month before YYYY-MM (default: older than archive.older_than)
+  list                     list archived months
+  restore SYMBOL YYYY-MM   insert an archived month back into the prices table`
+
+func newArchiveService(cfg config.ArchiveConfig, archiveRepo repository.PriceArchiveRepository, priceRepo repository.PriceRepository, logger *zap.Logger) (*services.ArchiveService, error) {
+	store, err := archive.NewFileStore(cfg.Dir, models.ArchiveFormat(cfg.Format))
+	if err != nil {
+		return nil, err
+	}
+	return services.NewArchiveService(archiveRepo, priceRepo, store, logger), nil
+}
+
+func runArchiveCommand(ctx context.Context, service *services.ArchiveService, olderThan time.Duration, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf(archiveUsage)
+	}
+
+	switch args[0] {
+	case "run":
+		cutoff := time.Now().Add(-olderThan)
+		if len(args) > 1 {
+			month, err := time.Parse(models.ArchiveMonthLayout, args[1])
+			if err != nil {
+				return fmt.Errorf("invalid month %q, expected YYYY-MM", args[1])
+			}
+			cutoff = month
+		}
+
+		archived, err := service.Archive(ctx, cutoff)
+		for _, entry := range archived {
+			fmt.Fprintf(out, "archived %s %s: %d rows to %s\n", entry.Symbol, entry.Month, entry.Rows, entry.File)
+		}
+		if err != nil {
+			return err
+		}
+		if len(archived) == 0 {
+			fmt.Fprintf(out, "nothing to archive before %s\n", models.MonthStart(cutoff).Format(models.ArchiveMonthLayout))
+		}
+		return nil
+
+	case "list":
+		entries, err := service.List(ctx)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			state := "archived " + entry.ArchivedAt.Format(time.RFC3339)
+			if entry.RestoredAt != nil {
+				state = "restored " + entry.RestoredAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(out, "%-10s %s %8d rows  %s  sha256:%s  %s\n", entry.Symbol, entry.Month, entry.Rows, entry.File, entry.SHA256, state)
+		}
+		return nil
+
+	case "restore":
+		if len(args) != 3 {
+			return fmt.Errorf(archiveUsage)
+		}
+		entry, result, err := service.Restore(ctx, args[1], args[2])
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "restored %s %s: %d inserted, %d already present\n", entry.Symbol, entry.Month, result.Inserted, result.Skipped)
+		return nil
+
+	default:
+		return fmt.Errorf("unknown archive command %q\n\n%s", args[0], archiveUsage)
+	}
+}
@@ -9,10 +9,18 @@ import (
 	"time"
 
 	"crypto-price-tracker-app/internal/application/services"
+	"crypto-price-tracker-app/internal/domain/models"
+	"crypto-price-tracker-app/internal/domain/repository"
+	"crypto-price-tracker-app/internal/infrastructure/cache"
 	"crypto-price-tracker-app/internal/infrastructure/coingecko"
 	"crypto-price-tracker-app/internal/infrastructure/config"
+	"crypto-price-tracker-app/internal/infrastructure/database"
+	"crypto-price-tracker-app/internal/infrastructure/memory"
+	"crypto-price-tracker-app/internal/infrastructure/migrate"
 	"crypto-price-tracker-app/internal/infrastructure/postgres"
+	"crypto-price-tracker-app/migrations"
 
+	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
 )
 
@@ -30,16 +38,31 @@ func main() {
 	if err != nil {
 		logger.Warn("Failed to load config file, using defaults", zap.Error(err))
 		cfg = &config.Config{
+			Storage: config.StorageDatabase,
 			Database: config.DatabaseConfig{
+				Driver:   config.DriverPostgres,
 				Host:     "postgres",
 				Port:     5432,
 				User:     "postgres",
 				Password: "password",
 				DBName:   "crypto_tracker",
 				SSLMode:  "disable",
+
+				MaxOpenConns:       25,
+				MaxIdleConns:       10,
+				ConnMaxLifetime:    30 * time.Minute,
+				ConnMaxIdleTime:    5 * time.Minute,
+				ConnectRetries:     10,
+				ConnectBackoff:     time.Second,
+				SlowQueryThreshold: 200 * time.Millisecond,
+				StatsInterval:      time.Minute,
+
+				ReplicaMaxLag:        30 * time.Second,
+				ReplicaCheckInterval: 10 * time.Second,
 			},
 			API: config.APIConfig{
-				Port: "8080",
+				Port:            "8080",
+				StaleMultiplier: 3,
 			},
 			Worker: config.WorkerConfig{
 				Interval: 60,
@@ -47,31 +70,134 @@ func main() {
 			Logging: config.LoggingConfig{
 				Level: "info",
 			},
+			Validation: config.ValidationConfig{
+				Enabled: true,
+				Default: config.PriceRulesConfig{
+					MaxChangePercent: 50,
+					MaxQuoteAge:      time.Hour,
+				},
+			},
+			Partitioning: config.PartitioningConfig{
+				Enabled:       true,
+				MonthsAhead:   3,
+				CheckInterval: time.Hour,
+			},
+			Rollups: config.RollupsConfig{
+				Enabled:         true,
+				RefreshInterval: time.Minute,
+				Lookback:        time.Hour,
+				Retention: config.RollupRetentionConfig{
+					Minute: 30 * 24 * time.Hour,
+					Hour:   365 * 24 * time.Hour,
+				},
+			},
+			Cache: config.CacheConfig{
+				Enabled:       true,
+				CurrencyTTL:   time.Minute,
+				PriceTTL:      30 * time.Second,
+				StatsInterval: 5 * time.Minute,
+			},
+			Archive: config.ArchiveConfig{
+				Dir:       "archive",
+				Format:    "parquet",
+				OlderThan: 365 * 24 * time.Hour,
+				Interval:  24 * time.Hour,
+			},
 		}
 	}
 
-	db, err := postgres.NewConnection(&postgres.Config{
-		Host:     cfg.Database.Host,
-		Port:     fmt.Sprintf("%d", cfg.Database.Port),
-		User:     cfg.Database.User,
-		Password: cfg.Database.Password,
-		DBName:   cfg.Database.DBName,
-		SSLMode:  cfg.Database.SSLMode,
-	})
-	if err != nil {
-		logger.Fatal("Failed to connect to database", zap.Error(err))
-	}
-	defer postgres.CloseConnection(db)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-	currencyRepo := postgres.NewCurrencyRepository(db)
-	priceRepo := postgres.NewPriceRepository(db)
+	var (
+		currencyRepo   repository.CurrencyRepository
+		priceRepo      repository.PriceRepository
+		quarantineRepo repository.QuarantineRepository
+	)
 
-	coingeckoClient := coingecko.NewClient("https://api.coingecko.com/api/v3")
+	switch cfg.Storage {
+	case config.StorageMemory:
+		if len(os.Args) > 1 && (os.Args[1] == "migrate" || os.Args[1] == "archive") {
+			logger.Fatal("This command requires database storage", zap.String("command", os.Args[1]))
+		}
 
-	priceService := services.NewPriceService(priceRepo, currencyRepo, coingeckoClient, logger)
+		logger.Warn("Using in-memory storage, data is lost on restart and partition and rollup maintenance is disabled")
+		store := memory.NewStore()
+		currencyRepo = memory.NewCurrencyRepository(store)
+		priceRepo = memory.NewPriceRepository(store)
+		quarantineRepo = memory.NewQuarantineRepository(store)
+	case config.StorageDatabase:
+		db, err := database.Open(cfg.Database, logger)
+		if err != nil {
+			logger.Fatal("Failed to connect to database", zap.Error(err))
+		}
+		defer database.Close(db)
+		go database.ReportPoolStats(ctx, cfg.Database.StatsInterval, logger)
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+		migrator, err := migrate.New(db, migrations.FS)
+		if err != nil {
+			logger.Fatal("Failed to load migrations", zap.Error(err))
+		}
+
+		if len(os.Args) > 1 && os.Args[1] == "migrate" {
+			if err := migrate.RunCommand(context.Background(), migrator, os.Args[2:], os.Stdout); err != nil {
+				logger.Fatal("Migration command failed", zap.Error(err))
+			}
+			return
+		}
+
+		if err := migrator.Check(context.Background()); err != nil {
+			logger.Fatal("Database schema is not up to date, run the migrate up command", zap.Error(err))
+		}
+
+		// Replicas are left to the API: the worker validates new prices
+		// against the latest stored ones and must not read stale data.
+		repos := database.NewRepositories(db, nil)
+		currencyRepo = repos.Currencies
+		priceRepo = repos.Prices
+		quarantineRepo = repos.Quarantine
+
+		archiveService, err := newArchiveService(cfg.Archive, repos.Archive, repos.Prices, logger)
+		if err != nil {
+			logger.Fatal("Failed to set up price archive", zap.Error(err))
+		}
+
+		if len(os.Args) > 1 && os.Args[1] == "archive" {
+			if err := runArchiveCommand(context.Background(), archiveService, cfg.Archive.OlderThan, os.Args[2:], os.Stdout); err != nil {
+				logger.Fatal("Archive command failed", zap.Error(err))
+			}
+			return
+		}
+
+		if cfg.Archive.Enabled {
+			go runMaintenance(ctx, "archive", cfg.Archive.Interval, func(ctx context.Context, now time.Time) error {
+				_, err := archiveService.Archive(ctx, now.Add(-cfg.Archive.OlderThan))
+				return err
+			}, logger)
+		}
+
+		// Memory storage needs no cache, so only database repositories are
+		// wrapped. Currencies are not cached: they change through the API,
+		// whose invalidation does not reach this process, and a paused or
+		// deleted currency must stop being fetched on the next cycle.
+		if cfg.Cache.Enabled {
+			priceRepo = cache.NewPriceRepository(priceRepo, cfg.Cache.PriceTTL)
+			go cache.ReportStats(ctx, cfg.Cache.StatsInterval, logger)
+		}
+
+		// Partitions and rollups rely on Postgres-only features.
+		if db.Dialector.Name() == config.DriverPostgres {
+			startMaintenance(ctx, cfg, postgres.NewPartitionManager(db), postgres.NewRollupRepository(db), logger)
+		} else {
+			logger.Info("Partition and rollup maintenance is disabled for this database driver", zap.String("driver", db.Dialector.Name()))
+		}
+	default:
+		logger.Fatal("Unknown storage", zap.String("storage", cfg.Storage))
+	}
+
+	coingeckoClient := coingecko.NewClient("https://api.coingecko.com/api/v3")
+
+	priceService := services.NewPriceService(priceRepo, currencyRepo, quarantineRepo, coingeckoClient, newPriceValidator(cfg.Validation), cfg.API.StaleMultiplier, logger)
 
 	go runWorker(ctx, priceService, cfg.Worker.Interval, logger)
 
@@ -86,6 +212,34 @@ func main() {
 	logger.Info("Worker exited")
 }
 
+func startMaintenance(ctx context.Context, cfg *config.Config, partitions repository.PartitionManager, rollups repository.RollupRepository, logger *zap.Logger) {
+	maintenanceService := services.NewMaintenanceService(
+		partitions,
+		rollups,
+		services.PartitionPolicy{
+			MonthsAhead:     cfg.Partitioning.MonthsAhead,
+			RetentionMonths: cfg.Partitioning.RetentionMonths,
+			DropExpired:     cfg.Partitioning.DropExpired,
+		},
+		services.RollupPolicy{
+			Lookback: cfg.Rollups.Lookback,
+			Retention: map[models.Resolution]time.Duration{
+				models.ResolutionMinute: cfg.Rollups.Retention.Minute,
+				models.ResolutionHour:   cfg.Rollups.Retention.Hour,
+				models.ResolutionDay:    cfg.Rollups.Retention.Day,
+			},
+		},
+		logger,
+	)
+
+	if cfg.Partitioning.Enabled {
+		go runMaintenance(ctx, "partitions", cfg.Partitioning.CheckInterval, maintenanceService.MaintainPartitions, logger)
+	}
+	if cfg.Rollups.Enabled {
+		go runMaintenance(ctx, "rollups", cfg.Rollups.RefreshInterval, maintenanceService.MaintainRollups, logger)
+	}
+}
+
 func initLogger() (*zap.Logger, error) {
 	config := zap.NewProductionConfig()
 	config.OutputPaths = []string{"stdout"}
@@ -93,6 +247,49 @@ func initLogger() (*zap.Logger, error) {
 	return config.Build()
 }
 
+func runMaintenance(ctx context.Context, name string, interval time.Duration, task func(context.Context, time.Time) error, logger *zap.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	logger.Info("Maintenance started", zap.String("task", name), zap.Duration("interval", interval))
+
+	if err := task(ctx, time.Now()); err != nil {
+		logger.Error("Maintenance task failed", zap.String("task", name), zap.Error(err))
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := task(ctx, time.Now()); err != nil {
+				logger.Error("Maintenance task failed", zap.String("task", name), zap.Error(err))
+			}
+		}
+	}
+}
+
+func newPriceValidator(cfg config.ValidationConfig) *services.PriceValidator {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	overrides := make(map[string]services.PriceRules, len(cfg.Currencies))
+	for symbol, rules := range cfg.Currencies {
+		overrides[symbol] = toPriceRules(rules)
+	}
+	return services.NewPriceValidator(toPriceRules(cfg.Default), overrides)
+}
+
+func toPriceRules(cfg config.PriceRulesConfig) services.PriceRules {
+	return services.PriceRules{
+		MinPrice:         decimal.NewFromFloat(cfg.MinPrice),
+		MaxPrice:         decimal.NewFromFloat(cfg.MaxPrice),
+		MaxChangePercent: cfg.MaxChangePercent,
+		MaxQuoteAge:      cfg.MaxQuoteAge,
+	}
+}
+
 func runWorker(ctx context.Context, priceService *services.PriceService, interval int, logger *zap.Logger) {
 	ticker := time.NewTicker(time.Duration(interval) * time.Second)
 	defer ticker.Stop()
@@ -1,38 +1,145 @@
 package dto
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
 
 type AddCurrencyRequest struct {
-	Symbol   string `json:"symbol" binding:"required" example:"BTC"`
-	ApiID    string `json:"api_id" binding:"required" example:"bitcoin"`
-	Interval int    `json:"interval" binding:"required,min=30" example:"60"`
+	Symbol     string `json:"symbol" binding:"required" example:"BTC"`
+	ApiID      string `json:"api_id" binding:"required" example:"bitcoin"`
+	Interval   int    `json:"interval" binding:"required,min=30" example:"60"`
+	StaleAfter int    `json:"stale_after" binding:"omitempty,min=0" example:"300"`
+	Precision  int    `json:"precision" binding:"omitempty,min=1,max=18" example:"8"`
 }
 
 type RemoveCurrencyRequest struct {
 	Symbol string `json:"symbol" binding:"required" example:"bitcoin"`
 }
 
+// UpdateCurrencyRequest changes only the fields that are set. Active pauses
+// an active currency or reactivates a paused or archived one; Reason is
+// recorded with that state change.
+type UpdateCurrencyRequest struct {
+	Symbol   string  `json:"-"`
+	ApiID    *string `json:"api_id" binding:"omitempty,min=1" example:"bitcoin"`
+	Interval *int    `json:"interval" binding:"omitempty,min=30" example:"120"`
+	Active   *bool   `json:"active" example:"false"`
+	Reason   string  `json:"reason" example:"exchange maintenance"`
+}
+
+type ChangeCurrencyStatusRequest struct {
+	Symbol string `json:"-"`
+	Status string `json:"status" binding:"required" example:"paused"`
+	Reason string `json:"reason" example:"exchange maintenance"`
+}
+
+type DeleteCurrencyRequest struct {
+	Symbol string
+	Prices string
+	Reason string
+}
+
+type RestoreCurrencyRequest struct {
+	Symbol string `json:"-"`
+	Reason string `json:"reason" example:"deleted by mistake"`
+}
+
 type GetPriceRequest struct {
 	Coin      string `form:"coin" binding:"required" example:"bitcoin"`
 	Timestamp int64  `form:"timestamp" binding:"required" example:"1640995200"`
+	Mode      string `form:"mode" example:"linear"`
+	MaxGap    string `form:"max_gap" example:"1h"`
+}
+
+// LookupPricesRequest applies Mode and MaxGap, which mean the same as in
+// GetPriceRequest, to every item.
+type LookupPricesRequest struct {
+	Items  []PriceLookupItem `json:"items" binding:"required,min=1,max=1000,dive"`
+	Mode   string            `json:"mode" example:"linear"`
+	MaxGap string            `json:"max_gap" example:"1h"`
+}
+
+type PriceLookupItem struct {
+	Coin      string `json:"coin" binding:"required" example:"BTC"`
+	Timestamp int64  `json:"timestamp" binding:"required" example:"1640995200"`
 }
 
 type CurrencyResponse struct {
-	ID        uint      `json:"id"`
-	Symbol    string    `json:"symbol"`
-	ApiID     string    `json:"api_id"`
-	Interval  int       `json:"interval"`
-	IsActive  bool      `json:"is_active"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID         uint      `json:"id"`
+	Symbol     string    `json:"symbol"`
+	ApiID      string    `json:"api_id"`
+	Interval   int       `json:"interval"`
+	StaleAfter int       `json:"stale_after"`
+	Precision  int       `json:"precision"`
+	Status     string    `json:"status"`
+	IsActive   bool      `json:"is_active"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type DeleteCurrencyResponse struct {
+	Currency       CurrencyResponse `json:"currency"`
+	Prices         string           `json:"prices"`
+	DeletedPrices  int64            `json:"deleted_prices"`
+	ArchivedMonths []string         `json:"archived_months,omitempty"`
+	// ArchiveError tells why some prices could not be archived; they are
+	// still in the database.
+	ArchiveError string `json:"archive_error,omitempty"`
+}
+
+type CurrencyTransitionResponse struct {
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	Actor      string    `json:"actor"`
+	Reason     string    `json:"reason"`
+	CreatedAt  time.Time `json:"created_at"`
 }
 
 type PriceResponse struct {
-	ID        uint      `json:"id"`
-	Symbol    string    `json:"symbol"`
-	Price     float64   `json:"price"`
-	Timestamp time.Time `json:"timestamp"`
-	CreatedAt time.Time `json:"created_at"`
+	ID               uint            `json:"id,omitempty"`
+	Symbol           string          `json:"symbol"`
+	Price            decimal.Decimal `json:"price" swaggertype:"string" example:"42000.12345678"`
+	Timestamp        time.Time       `json:"timestamp"`
+	CreatedAt        *time.Time      `json:"created_at,omitempty"`
+	Mode             string          `json:"mode" example:"previous"`
+	SourceTimestamps []time.Time     `json:"source_timestamps"`
+	AgeSeconds       int64           `json:"age_seconds"`
+	IsStale          bool            `json:"is_stale"`
+}
+
+// PriceLookupResult holds either Price or Error.
+type PriceLookupResult struct {
+	Coin      string         `json:"coin"`
+	Timestamp int64          `json:"timestamp"`
+	Price     *PriceResponse `json:"price,omitempty"`
+	Error     string         `json:"error,omitempty"`
+}
+
+type LookupPricesResponse struct {
+	Results []PriceLookupResult `json:"results"`
+}
+
+// LatestPriceResponse leaves the price fields null for a currency without
+// prices, and the change fields when there is no price to compare with.
+type LatestPriceResponse struct {
+	Symbol           string           `json:"symbol"`
+	Price            *decimal.Decimal `json:"price" swaggertype:"string" example:"42000.12345678"`
+	Timestamp        *time.Time       `json:"timestamp"`
+	AgeSeconds       *int64           `json:"age_seconds"`
+	Change24h        *decimal.Decimal `json:"change_24h" swaggertype:"string" example:"-120.5"`
+	ChangePercent24h *decimal.Decimal `json:"change_percent_24h" swaggertype:"string" example:"-0.29"`
+}
+
+type StaleCurrencyResponse struct {
+	Symbol            string     `json:"symbol"`
+	ApiID             string     `json:"api_id"`
+	Interval          int        `json:"interval"`
+	StaleAfterSeconds int64      `json:"stale_after_seconds"`
+	LatestPriceAt     *time.Time `json:"latest_price_at"`
+	AgeSeconds        *int64     `json:"age_seconds"`
 }
 
 type ErrorResponse struct {
@@ -45,3 +152,98 @@ type SuccessResponse struct {
 	Message string      `json:"message"`
 	Data    interface{} `json:"data,omitempty"`
 }
+
+type QuarantinedPriceResponse struct {
+	ID             uint             `json:"id"`
+	Symbol         string           `json:"symbol"`
+	Price          decimal.Decimal  `json:"price" swaggertype:"string"`
+	Timestamp      time.Time        `json:"timestamp"`
+	ReferencePrice *decimal.Decimal `json:"reference_price,omitempty" swaggertype:"string"`
+	Rule           string           `json:"rule"`
+	Reason         string           `json:"reason"`
+	Status         string           `json:"status"`
+	ReviewedAt     *time.Time       `json:"reviewed_at,omitempty"`
+	CreatedAt      time.Time        `json:"created_at"`
+}
+
+type GetCandlesRequest struct {
+	Symbol   string
+	Interval string
+	From     int64
+	To       int64
+	Fill     bool
+}
+
+type CandleResponse struct {
+	Time        time.Time       `json:"time"`
+	Open        decimal.Decimal `json:"open" swaggertype:"string"`
+	High        decimal.Decimal `json:"high" swaggertype:"string"`
+	Low         decimal.Decimal `json:"low" swaggertype:"string"`
+	Close       decimal.Decimal `json:"close" swaggertype:"string"`
+	SampleCount int64           `json:"sample_count"`
+	Filled      bool            `json:"filled,omitempty"`
+}
+
+type CandlesResponse struct {
+	Symbol   string           `json:"symbol"`
+	Interval string           `json:"interval"`
+	From     time.Time        `json:"from"`
+	To       time.Time        `json:"to"`
+	Candles  []CandleResponse `json:"candles"`
+}
+
+type GetPriceHistoryRequest struct {
+	Symbol string
+	From   int64
+	To     int64
+	Limit  int
+	Cursor string
+	Order  string
+	Step   string
+}
+
+type HistoryPriceResponse struct {
+	ID        uint            `json:"id"`
+	Price     decimal.Decimal `json:"price" swaggertype:"string"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+type PriceHistoryResponse struct {
+	Symbol     string                 `json:"symbol"`
+	From       time.Time              `json:"from"`
+	To         time.Time              `json:"to"`
+	Order      string                 `json:"order"`
+	Step       string                 `json:"step,omitempty"`
+	Prices     []HistoryPriceResponse `json:"prices"`
+	NextCursor *string                `json:"next_cursor"`
+	PrevCursor *string                `json:"prev_cursor"`
+}
+
+type PriceEventResponse struct {
+	Symbol    string          `json:"symbol"`
+	Price     decimal.Decimal `json:"price"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+type ListAuditRequest struct {
+	Actor      string `form:"actor" example:"alice"`
+	Action     string `form:"action" example:"currency.add"`
+	TargetType string `form:"target_type" example:"currency"`
+	TargetID   string `form:"target_id" example:"BTC"`
+	From       int64  `form:"from" example:"1704067200"`
+	To         int64  `form:"to" example:"1706745600"`
+	Limit      int    `form:"limit" binding:"omitempty,min=1,max=1000" example:"100"`
+}
+
+type AuditEventResponse struct {
+	ID         uint            `json:"id"`
+	Actor      string          `json:"actor"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"target_type"`
+	TargetID   string          `json:"target_id"`
+	Before     json.RawMessage `json:"before" swaggertype:"object"`
+	After      json.RawMessage `json:"after" swaggertype:"object"`
+	RequestID  string          `json:"request_id"`
+	ClientIP   string          `json:"client_ip"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
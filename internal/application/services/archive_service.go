@@ -0,0 +1,279 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"crypto-price-tracker-app/internal/domain/models"
+	"crypto-price-tracker-app/internal/domain/repository"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrArchiveNotFound = errors.New("archive not found")
+	// ErrArchiveConflict means a month that is already archived has gained
+	// prices that are not in its file; it must be restored before it can be
+	// archived again.
+	ErrArchiveConflict = errors.New("month is already archived with different prices")
+)
+
+type ArchiveService struct {
+	archiveRepo repository.PriceArchiveRepository
+	priceRepo   repository.PriceRepository
+	store       repository.ArchiveStore
+	logger      *zap.Logger
+}
+
+func NewArchiveService(archiveRepo repository.PriceArchiveRepository, priceRepo repository.PriceRepository, store repository.ArchiveStore, logger *zap.Logger) *ArchiveService {
+	return &ArchiveService{
+		archiveRepo: archiveRepo,
+		priceRepo:   priceRepo,
+		store:       store,
+		logger:      logger,
+	}
+}
+
+// Archive moves every whole month before cutoff out of the database. cutoff is
+// rounded down to the start of its month, so a month is never split between
+// files. Rows are deleted only after the written file has been read back and
+// matched, and after the manifest lists it. The manifest stays locked for the
+// whole run, since the API archives into the same store. A failing month does not stop the
+// others; their errors are returned together.
+func (s *ArchiveService) Archive(ctx context.Context, cutoff time.Time) ([]models.ArchiveEntry, error) {
+	before := models.MonthStart(cutoff)
+	groups, err := s.archiveRepo.ListArchivable(ctx, before)
+	if err != nil {
+		s.logger.Error("Failed to list archivable prices", zap.Time("before", before), zap.Error(err))
+		return nil, err
+	}
+	return s.archiveGroups(ctx, groups)
+}
+
+// ArchiveCurrency archives every month of one currency, including the
+// current one. It is meant for currencies that no longer receive prices.
+func (s *ArchiveService) ArchiveCurrency(ctx context.Context, currencyID uint) ([]models.ArchiveEntry, error) {
+	before := models.MonthStart(time.Now()).AddDate(0, 1, 0)
+	all, err := s.archiveRepo.ListArchivable(ctx, before)
+	if err != nil {
+		s.logger.Error("Failed to list archivable prices", zap.Uint("currency_id", currencyID), zap.Error(err))
+		return nil, err
+	}
+
+	var groups []models.ArchiveGroup
+	for _, group := range all {
+		if group.CurrencyID == currencyID {
+			groups = append(groups, group)
+		}
+	}
+	return s.archiveGroups(ctx, groups)
+}
+
+func (s *ArchiveService) archiveGroups(ctx context.Context, groups []models.ArchiveGroup) ([]models.ArchiveEntry, error) {
+	unlock, err := s.store.Lock(ctx)
+	if err != nil {
+		s.logger.Error("Failed to lock archive manifest", zap.Error(err))
+		return nil, err
+	}
+	defer unlock()
+
+	manifest, err := s.store.LoadManifest(ctx)
+	if err != nil {
+		s.logger.Error("Failed to load archive manifest", zap.Error(err))
+		return nil, err
+	}
+
+	var archived []models.ArchiveEntry
+	var errs []error
+	for _, group := range groups {
+		entry, err := s.archiveGroup(ctx, group, &manifest)
+		if err != nil {
+			s.logger.Error("Failed to archive prices",
+				zap.String("symbol", group.Symbol),
+				zap.Time("month", group.Month),
+				zap.Error(err),
+			)
+			errs = append(errs, fmt.Errorf("%s %s: %w", group.Symbol, group.Month.Format(models.ArchiveMonthLayout), err))
+			continue
+		}
+		if entry != nil {
+			archived = append(archived, *entry)
+		}
+	}
+	return archived, errors.Join(errs...)
+}
+
+func (s *ArchiveService) archiveGroup(ctx context.Context, group models.ArchiveGroup, manifest *[]models.ArchiveEntry) (*models.ArchiveEntry, error) {
+	month := group.Month.Format(models.ArchiveMonthLayout)
+	prices, err := s.archiveRepo.GetMonth(ctx, group.CurrencyID, group.Month)
+	if err != nil {
+		return nil, err
+	}
+	if len(prices) == 0 {
+		return nil, nil
+	}
+
+	// A previous run may have written the file but failed to delete the
+	// rows. Those rows can still go, as long as the file holds all of them.
+	index := findArchiveEntry(*manifest, group.CurrencyID, month)
+	if index >= 0 && (*manifest)[index].RestoredAt == nil {
+		entry := (*manifest)[index]
+		stored, err := s.store.Read(ctx, entry)
+		if err != nil {
+			return nil, err
+		}
+		if !containsPrices(stored, prices) {
+			return nil, ErrArchiveConflict
+		}
+		if err := s.deleteArchived(ctx, group, prices); err != nil {
+			return nil, err
+		}
+		return &entry, nil
+	}
+
+	entry := models.ArchiveEntry{
+		CurrencyID: group.CurrencyID,
+		Symbol:     group.Symbol,
+		Month:      month,
+		From:       prices[0].Timestamp.UTC(),
+		To:         prices[len(prices)-1].Timestamp.UTC(),
+		ArchivedAt: time.Now().UTC(),
+	}
+	if err := s.store.Write(ctx, &entry, prices); err != nil {
+		return nil, err
+	}
+
+	stored, err := s.store.Read(ctx, entry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify archive: %w", err)
+	}
+	if len(stored) != len(prices) || !containsPrices(stored, prices) {
+		return nil, fmt.Errorf("archive %s does not match the database", entry.File)
+	}
+
+	if index >= 0 {
+		(*manifest)[index] = entry
+	} else {
+		*manifest = append(*manifest, entry)
+	}
+	if err := s.store.SaveManifest(ctx, *manifest); err != nil {
+		return nil, err
+	}
+
+	if err := s.deleteArchived(ctx, group, prices); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Archived prices",
+		zap.String("symbol", entry.Symbol),
+		zap.String("month", entry.Month),
+		zap.String("file", entry.File),
+		zap.Int("rows", entry.Rows),
+	)
+	return &entry, nil
+}
+
+func (s *ArchiveService) deleteArchived(ctx context.Context, group models.ArchiveGroup, prices []models.Price) error {
+	ids := make([]uint, len(prices))
+	for i, price := range prices {
+		ids[i] = price.ID
+	}
+
+	deleted, err := s.archiveRepo.DeleteArchived(ctx, group.CurrencyID, group.Month, ids)
+	if err != nil {
+		return fmt.Errorf("failed to delete archived prices: %w", err)
+	}
+	if deleted != int64(len(ids)) {
+		s.logger.Warn("Deleted fewer archived prices than expected",
+			zap.String("symbol", group.Symbol),
+			zap.Time("month", group.Month),
+			zap.Int64("deleted", deleted),
+			zap.Int("expected", len(ids)),
+		)
+	}
+	return nil
+}
+
+// Restore inserts the prices of an archived month back into the database.
+// Prices already present are left alone, so restoring twice is harmless. The
+// file is kept; the manifest records when it was restored.
+func (s *ArchiveService) Restore(ctx context.Context, symbol, month string) (*models.ArchiveEntry, *models.BatchResult, error) {
+	unlock, err := s.store.Lock(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer unlock()
+
+	manifest, err := s.store.LoadManifest(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	index := -1
+	for i, entry := range manifest {
+		if strings.EqualFold(entry.Symbol, symbol) && entry.Month == month {
+			index = i
+		}
+	}
+	if index < 0 {
+		return nil, nil, ErrArchiveNotFound
+	}
+
+	entry := &manifest[index]
+	prices, err := s.store.Read(ctx, *entry)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result, err := s.priceRepo.CreateBatch(ctx, prices, models.ConflictSkip)
+	if err != nil {
+		s.logger.Error("Failed to restore archived prices", zap.String("file", entry.File), zap.Error(err))
+		return nil, nil, err
+	}
+
+	restoredAt := time.Now().UTC()
+	entry.RestoredAt = &restoredAt
+	if err := s.store.SaveManifest(ctx, manifest); err != nil {
+		return nil, nil, err
+	}
+
+	s.logger.Info("Restored archived prices",
+		zap.String("symbol", entry.Symbol),
+		zap.String("month", entry.Month),
+		zap.Int("inserted", result.Inserted),
+		zap.Int("skipped", result.Skipped),
+	)
+	return entry, result, nil
+}
+
+func (s *ArchiveService) List(ctx context.Context) ([]models.ArchiveEntry, error) {
+	return s.store.LoadManifest(ctx)
+}
+
+func findArchiveEntry(manifest []models.ArchiveEntry, currencyID uint, month string) int {
+	for i, entry := range manifest {
+		if entry.CurrencyID == currencyID && entry.Month == month {
+			return i
+		}
+	}
+	return -1
+}
+
+// containsPrices reports whether every price of want is in have with the same
+// timestamp and value.
+func containsPrices(have, want []models.Price) bool {
+	stored := make(map[int64]models.Price, len(have))
+	for _, price := range have {
+		stored[price.Timestamp.UnixNano()] = price
+	}
+	for _, price := range want {
+		got, ok := stored[price.Timestamp.UnixNano()]
+		if !ok || got.CurrencyID != price.CurrencyID || !got.Price.Equal(price.Price) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"crypto-price-tracker-app/internal/domain/models"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type MockPriceArchiveRepository struct {
+	mock.Mock
+}
+
+func (m *MockPriceArchiveRepository) ListArchivable(ctx context.Context, before time.Time) ([]models.ArchiveGroup, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).([]models.ArchiveGroup), args.Error(1)
+}
+
+func (m *MockPriceArchiveRepository) GetMonth(ctx context.Context, currencyID uint, month time.Time) ([]models.Price, error) {
+	args := m.Called(ctx, currencyID, month)
+	return args.Get(0).([]models.Price), args.Error(1)
+}
+
+func (m *MockPriceArchiveRepository) DeleteArchived(ctx context.Context, currencyID uint, month time.Time, ids []uint) (int64, error) {
+	args := m.Called(ctx, currencyID, month, ids)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// fakeArchiveStore keeps archives in memory.
+type fakeArchiveStore struct {
+	files    map[string][]models.Price
+	manifest []models.ArchiveEntry
+	writes   int
+	locked   bool
+}
+
+func newFakeArchiveStore() *fakeArchiveStore {
+	return &fakeArchiveStore{files: map[string][]models.Price{}}
+}
+
+func (s *fakeArchiveStore) Write(ctx context.Context, entry *models.ArchiveEntry, prices []models.Price) error {
+	s.writes++
+	entry.File = entry.Symbol + "/" + entry.Month + ".csv.gz"
+	entry.Format = models.ArchiveCSV
+	entry.Rows = len(prices)
+	entry.SHA256 = "checksum"
+	s.files[entry.File] = append([]models.Price(nil), prices...)
+	return nil
+}
+
+func (s *fakeArchiveStore) Read(ctx context.Context, entry models.ArchiveEntry) ([]models.Price, error) {
+	prices, ok := s.files[entry.File]
+	if !ok {
+		return nil, errors.New("missing file")
+	}
+	return prices, nil
+}
+
+func (s *fakeArchiveStore) LoadManifest(ctx context.Context) ([]models.ArchiveEntry, error) {
+	return append([]models.ArchiveEntry(nil), s.manifest...), nil
+}
+
+func (s *fakeArchiveStore) SaveManifest(ctx context.Context, entries []models.ArchiveEntry) error {
+	s.manifest = append([]models.ArchiveEntry(nil), entries...)
+	return nil
+}
+
+// Lock fails the test if the manifest is taken twice without release.
+func (s *fakeArchiveStore) Lock(ctx context.Context) (func(), error) {
+	if s.locked {
+		return nil, errors.New("manifest is already locked")
+	}
+	s.locked = true
+	return func() { s.locked = false }, nil
+}
+
+func TestArchiveService_Archive(t *testing.T) {
+	march := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	btc := []models.Price{
+		{ID: 10, CurrencyID: 1, Price: decimal.NewFromInt(100), Timestamp: march},
+		{ID: 11, CurrencyID: 1, Price: decimal.NewFromInt(101), Timestamp: march.Add(time.Hour)},
+	}
+	eth := []models.Price{{ID: 20, CurrencyID: 2, Price: decimal.NewFromInt(3), Timestamp: march}}
+
+	archiveRepo := new(MockPriceArchiveRepository)
+	archiveRepo.On("ListArchivable", mock.Anything, time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)).Return([]models.ArchiveGroup{
+		{CurrencyID: 1, Symbol: "BTC", Month: march, Rows: 2},
+		{CurrencyID: 2, Symbol: "ETH", Month: march, Rows: 1},
+	}, nil)
+	archiveRepo.On("GetMonth", mock.Anything, uint(1), march).Return(btc, nil)
+	archiveRepo.On("GetMonth", mock.Anything, uint(2), march).Return(eth, nil)
+	archiveRepo.On("DeleteArchived", mock.Anything, uint(1), march, []uint{10, 11}).Return(int64(2), nil)
+
+	store := newFakeArchiveStore()
+	// ETH was archived before, then gained a price the file does not have.
+	store.manifest = []models.ArchiveEntry{{CurrencyID: 2, Symbol: "ETH", Month: "2024-03", File: "ETH/old.csv.gz"}}
+	store.files["ETH/old.csv.gz"] = []models.Price{{CurrencyID: 2, Price: decimal.NewFromInt(2), Timestamp: march.Add(-time.Hour)}}
+
+	service := NewArchiveService(archiveRepo, new(MockPriceRepository), store, zap.NewNop())
+	archived, err := service.Archive(context.Background(), time.Date(2024, 4, 20, 0, 0, 0, 0, time.UTC))
+
+	assert.ErrorIs(t, err, ErrArchiveConflict)
+	require.Len(t, archived, 1)
+	assert.Equal(t, "BTC", archived[0].Symbol)
+	assert.Equal(t, 2, archived[0].Rows)
+	assert.Equal(t, march.Add(time.Hour), archived[0].To)
+	require.Len(t, store.manifest, 2)
+	assert.Equal(t, "BTC/2024-03.csv.gz", store.manifest[1].File)
+	assert.False(t, store.locked, "manifest lock is released")
+	archiveRepo.AssertExpectations(t)
+	archiveRepo.AssertNotCalled(t, "DeleteArchived", mock.Anything, uint(2), mock.Anything, mock.Anything)
+}
+
+func TestArchiveService_ArchiveResumesDelete(t *testing.T) {
+	march := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	prices := []models.Price{{ID: 10, CurrencyID: 1, Price: decimal.NewFromInt(100), Timestamp: march}}
+
+	archiveRepo := new(MockPriceArchiveRepository)
+	archiveRepo.On("ListArchivable", mock.Anything, mock.Anything).Return([]models.ArchiveGroup{{CurrencyID: 1, Symbol: "BTC", Month: march, Rows: 1}}, nil)
+	archiveRepo.On("GetMonth", mock.Anything, uint(1), march).Return(prices, nil)
+	archiveRepo.On("DeleteArchived", mock.Anything, uint(1), march, []uint{10}).Return(int64(1), nil)
+
+	store := newFakeArchiveStore()
+	store.manifest = []models.ArchiveEntry{{CurrencyID: 1, Symbol: "BTC", Month: "2024-03", File: "BTC/2024-03.csv.gz", Rows: 1}}
+	store.files["BTC/2024-03.csv.gz"] = prices
+
+	service := NewArchiveService(archiveRepo, new(MockPriceRepository), store, zap.NewNop())
+	archived, err := service.Archive(context.Background(), time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC))
+
+	require.NoError(t, err)
+	assert.Len(t, archived, 1)
+	assert.Zero(t, store.writes)
+	archiveRepo.AssertExpectations(t)
+}
+
+func TestArchiveService_Restore(t *testing.T) {
+	march := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	prices := []models.Price{{CurrencyID: 1, Price: decimal.NewFromInt(100), Timestamp: march}}
+
+	store := newFakeArchiveStore()
+	store.manifest = []models.ArchiveEntry{{CurrencyID: 1, Symbol: "BTC", Month: "2024-03", File: "BTC/2024-03.csv.gz", Rows: 1}}
+	store.files["BTC/2024-03.csv.gz"] = prices
+
+	priceRepo := new(MockPriceRepository)
+	priceRepo.On("CreateBatch", mock.Anything, prices, models.ConflictSkip).Return(&models.BatchResult{Inserted: 1}, nil)
+
+	service := NewArchiveService(new(MockPriceArchiveRepository), priceRepo, store, zap.NewNop())
+	entry, result, err := service.Restore(context.Background(), "btc", "2024-03")
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Inserted)
+	assert.NotNil(t, entry.RestoredAt)
+	assert.NotNil(t, store.manifest[0].RestoredAt)
+
+	_, _, err = service.Restore(context.Background(), "BTC", "2024-04")
+	assert.ErrorIs(t, err, ErrArchiveNotFound)
+	priceRepo.AssertExpectations(t)
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"crypto-price-tracker-app/internal/application/dto"
+	"crypto-price-tracker-app/internal/domain/models"
+	"crypto-price-tracker-app/internal/domain/repository"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// RequestInfo describes the API request on whose behalf a change is made.
+type RequestInfo struct {
+	Actor     string
+	RequestID string
+	ClientIP  string
+}
+
+type requestInfoKey struct{}
+
+func WithRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// requestInfoFrom returns the request info attached to ctx. Changes made
+// without a known actor are attributed to models.AnonymousActor.
+func requestInfoFrom(ctx context.Context) RequestInfo {
+	info, _ := ctx.Value(requestInfoKey{}).(RequestInfo)
+	if info.Actor == "" {
+		info.Actor = models.AnonymousActor
+	}
+	return info
+}
+
+// recordAudit stores the audit event of a change through auditRepo, which
+// must belong to the unit of work making the change. before is nil for
+// creations.
+func recordAudit(ctx context.Context, auditRepo repository.AuditRepository, action, targetType, targetID string, before, after interface{}) error {
+	info := requestInfoFrom(ctx)
+	event := &models.AuditEvent{
+		Actor:      info.Actor,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Before:     auditSnapshot(before),
+		After:      auditSnapshot(after),
+		RequestID:  info.RequestID,
+		ClientIP:   info.ClientIP,
+	}
+	if err := auditRepo.Create(ctx, event); err != nil {
+		return fmt.Errorf("failed to record audit event: %w", err)
+	}
+	return nil
+}
+
+// auditSnapshot encodes one of the *AuditState structs below, which cannot
+// fail to marshal.
+func auditSnapshot(state interface{}) *string {
+	if state == nil {
+		return nil
+	}
+	data, _ := json.Marshal(state)
+	snapshot := string(data)
+	return &snapshot
+}
+
+// currencyAuditState is what the audit log keeps of a currency. It leaves
+// out generated columns, which a creation does not know yet.
+type currencyAuditState struct {
+	Symbol     string `json:"symbol"`
+	ApiID      string `json:"api_id"`
+	Interval   int    `json:"interval"`
+	StaleAfter int    `json:"stale_after"`
+	Precision  int    `json:"precision"`
+	Status     string `json:"status"`
+}
+
+func newCurrencyAuditState(currency *models.Currency) *currencyAuditState {
+	return &currencyAuditState{
+		Symbol:     currency.Symbol,
+		ApiID:      currency.ApiID,
+		Interval:   currency.Interval,
+		StaleAfter: currency.StaleAfter,
+		Precision:  currency.Precision,
+		Status:     currency.Status,
+	}
+}
+
+type quarantineAuditState struct {
+	Symbol    string          `json:"symbol"`
+	Price     decimal.Decimal `json:"price"`
+	Timestamp time.Time       `json:"timestamp"`
+	Status    string          `json:"status"`
+}
+
+func newQuarantineAuditState(sample *models.QuarantinedPrice, status string) *quarantineAuditState {
+	return &quarantineAuditState{
+		Symbol:    sample.Currency.Symbol,
+		Price:     sample.Price,
+		Timestamp: sample.Timestamp,
+		Status:    status,
+	}
+}
+
+const defaultAuditLimit = 100
+
+type AuditService struct {
+	auditRepo repository.AuditRepository
+	logger    *zap.Logger
+}
+
+func NewAuditService(auditRepo repository.AuditRepository, logger *zap.Logger) *AuditService {
+	return &AuditService{
+		auditRepo: auditRepo,
+		logger:    logger,
+	}
+}
+
+// List returns audit events newest first. From and To are unix seconds and
+// bound the creation time as [from, to).
+func (s *AuditService) List(ctx context.Context, req *dto.ListAuditRequest) ([]dto.AuditEventResponse, error) {
+	filter := models.AuditFilter{
+		Actor:      req.Actor,
+		Action:     req.Action,
+		TargetType: req.TargetType,
+		TargetID:   req.TargetID,
+		Limit:      req.Limit,
+	}
+	if req.From > 0 {
+		filter.From = time.Unix(req.From, 0)
+	}
+	if req.To > 0 {
+		filter.To = time.Unix(req.To, 0)
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultAuditLimit
+	}
+
+	events, err := s.auditRepo.List(ctx, filter)
+	if err != nil {
+		s.logger.Error("Failed to list audit events", zap.Error(err))
+		return nil, err
+	}
+
+	responses := make([]dto.AuditEventResponse, len(events))
+	for i, event := range events {
+		responses[i] = dto.AuditEventResponse{
+			ID:         event.ID,
+			Actor:      event.Actor,
+			Action:     event.Action,
+			TargetType: event.TargetType,
+			TargetID:   event.TargetID,
+			RequestID:  event.RequestID,
+			ClientIP:   event.ClientIP,
+			CreatedAt:  event.CreatedAt,
+		}
+		if event.Before != nil {
+			responses[i].Before = json.RawMessage(*event.Before)
+		}
+		if event.After != nil {
+			responses[i].After = json.RawMessage(*event.After)
+		}
+	}
+	return responses, nil
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"crypto-price-tracker-app/internal/application/dto"
+	"crypto-price-tracker-app/internal/domain/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditRepository) Create(ctx context.Context, event *models.AuditEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+// acceptAudit returns an audit repository storing any event.
+func acceptAudit() *MockAuditRepository {
+	repo := new(MockAuditRepository)
+	repo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	return repo
+}
+
+// matchAudit matches an event recorded by the given action for the given
+// target.
+func matchAudit(action, targetID string) interface{} {
+	return mock.MatchedBy(func(event *models.AuditEvent) bool {
+		return event.Action == action && event.TargetID == targetID
+	})
+}
+
+func (m *MockAuditRepository) List(ctx context.Context, filter models.AuditFilter) ([]models.AuditEvent, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]models.AuditEvent), args.Error(1)
+}
+
+func TestAuditService_List(t *testing.T) {
+	after := `{"symbol":"BTC","status":"active"}`
+	repo := new(MockAuditRepository)
+	repo.On("List", mock.Anything, models.AuditFilter{
+		Actor: "alice",
+		From:  time.Unix(1704067200, 0),
+		Limit: defaultAuditLimit,
+	}).Return([]models.AuditEvent{{
+		ID:         1,
+		Actor:      "alice",
+		Action:     models.AuditActionCurrencyAdd,
+		TargetType: models.AuditTargetCurrency,
+		TargetID:   "BTC",
+		After:      &after,
+	}}, nil)
+
+	service := NewAuditService(repo, zap.NewNop())
+	events, err := service.List(context.Background(), &dto.ListAuditRequest{Actor: "alice", From: 1704067200})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Nil(t, events[0].Before)
+	assert.JSONEq(t, after, string(events[0].After))
+	repo.AssertExpectations(t)
+}
+
+func TestRequestInfoDefaultsToAnonymous(t *testing.T) {
+	assert.Equal(t, models.AnonymousActor, requestInfoFrom(context.Background()).Actor)
+}
@@ -0,0 +1,242 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"crypto-price-tracker-app/internal/application/dto"
+	"crypto-price-tracker-app/internal/domain/models"
+	"crypto-price-tracker-app/internal/domain/repository"
+
+	"go.uber.org/zap"
+)
+
+// What DeleteCurrency does with the prices of the deleted currency.
+const (
+	DeletePricesKeep    = "keep"
+	DeletePricesPurge   = "purge"
+	DeletePricesArchive = "archive"
+)
+
+var (
+	ErrCurrencyNotFound = errors.New("currency not found")
+	// ErrInvalidTransition means the requested state cannot be reached from
+	// the current one, or the currency changed state concurrently.
+	ErrInvalidTransition      = errors.New("invalid currency transition")
+	ErrInvalidCurrencyRequest = errors.New("invalid currency request")
+)
+
+// ChangeStatus moves a currency between active, paused and archived. Asking
+// for the state it is already in changes nothing and records nothing.
+func (s *CurrencyService) ChangeStatus(ctx context.Context, req *dto.ChangeCurrencyStatusRequest) (*dto.CurrencyResponse, error) {
+	switch req.Status {
+	case models.CurrencyStatusActive, models.CurrencyStatusPaused, models.CurrencyStatusArchived:
+	default:
+		return nil, fmt.Errorf("%w: status must be active, paused or archived", ErrInvalidCurrencyRequest)
+	}
+
+	currency, err := s.getCurrency(ctx, req.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	if currency.Status != req.Status {
+		err := s.unitOfWork.WithTx(ctx, func(repos repository.Repositories) error {
+			return s.transition(ctx, repos, currency, req.Status, models.AuditActionCurrencyStatus, req.Reason)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	response := toCurrencyResponse(currency)
+	return &response, nil
+}
+
+// DeleteCurrency soft-deletes a currency and deals with its prices: they are
+// kept for a later restore, purged, or archived to files and removed.
+// Purging and the deletion happen in one transaction; archiving only starts
+// once the deletion has been committed.
+func (s *CurrencyService) DeleteCurrency(ctx context.Context, req *dto.DeleteCurrencyRequest) (*dto.DeleteCurrencyResponse, error) {
+	prices := req.Prices
+	if prices == "" {
+		prices = DeletePricesKeep
+	}
+	switch prices {
+	case DeletePricesKeep, DeletePricesPurge:
+	case DeletePricesArchive:
+		if s.archiveService == nil {
+			return nil, fmt.Errorf("%w: archiving is not available with this storage", ErrInvalidCurrencyRequest)
+		}
+	default:
+		return nil, fmt.Errorf("%w: prices must be keep, purge or archive", ErrInvalidCurrencyRequest)
+	}
+
+	currency, err := s.getCurrency(ctx, req.Symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.DeleteCurrencyResponse{Prices: prices}
+	err = s.unitOfWork.WithTx(ctx, func(repos repository.Repositories) error {
+		if prices == DeletePricesPurge {
+			deleted, err := repos.Prices.DeleteByCurrency(ctx, currency.ID)
+			if err != nil {
+				s.logger.Error("Failed to purge prices", zap.String("symbol", req.Symbol), zap.Error(err))
+				return err
+			}
+			response.DeletedPrices = deleted
+		}
+		return s.transition(ctx, repos, currency, models.CurrencyStatusDeleted, models.AuditActionCurrencyDelete, req.Reason)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Archive files cannot take part in a transaction, so archiving follows
+	// the deletion. Rows are removed only once their month is safely
+	// archived; a failure keeps the rest in the database and is reported
+	// with the response, as the currency is deleted either way.
+	if prices == DeletePricesArchive {
+		entries, err := s.archiveService.ArchiveCurrency(ctx, currency.ID)
+		for _, entry := range entries {
+			response.ArchivedMonths = append(response.ArchivedMonths, entry.Month)
+			response.DeletedPrices += int64(entry.Rows)
+		}
+		if err != nil {
+			s.logger.Error("Failed to archive prices of deleted currency", zap.String("symbol", req.Symbol), zap.Error(err))
+			response.ArchiveError = err.Error()
+		}
+	}
+
+	s.logger.Info("Currency deleted",
+		zap.String("symbol", req.Symbol),
+		zap.String("prices", prices),
+		zap.Int64("deleted_prices", response.DeletedPrices),
+	)
+	response.Currency = toCurrencyResponse(currency)
+	return response, nil
+}
+
+// RestoreCurrency brings a deleted currency back as paused, so fetching only
+// resumes once it is explicitly activated. Purged prices are not restored;
+// archived ones can be restored from the archive.
+func (s *CurrencyService) RestoreCurrency(ctx context.Context, req *dto.RestoreCurrencyRequest) (*dto.CurrencyResponse, error) {
+	currency, err := s.currencyRepo.GetDeletedBySymbol(ctx, req.Symbol)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, fmt.Errorf("%w: no deleted currency %s", ErrCurrencyNotFound, req.Symbol)
+		}
+		s.logger.Error("Failed to get deleted currency", zap.String("symbol", req.Symbol), zap.Error(err))
+		return nil, err
+	}
+
+	err = s.unitOfWork.WithTx(ctx, func(repos repository.Repositories) error {
+		return s.transition(ctx, repos, currency, models.CurrencyStatusPaused, models.AuditActionCurrencyRestore, req.Reason)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Currency restored", zap.String("symbol", req.Symbol))
+	response := toCurrencyResponse(currency)
+	return &response, nil
+}
+
+// ListTransitions returns the lifecycle history of a currency, oldest first.
+// Deleted currencies keep their history.
+func (s *CurrencyService) ListTransitions(ctx context.Context, symbol string) ([]dto.CurrencyTransitionResponse, error) {
+	currency, err := s.currencyRepo.GetBySymbol(ctx, symbol)
+	if errors.Is(err, repository.ErrNotFound) {
+		currency, err = s.currencyRepo.GetDeletedBySymbol(ctx, symbol)
+	}
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrCurrencyNotFound
+		}
+		s.logger.Error("Failed to get currency", zap.String("symbol", symbol), zap.Error(err))
+		return nil, err
+	}
+
+	transitions, err := s.currencyRepo.ListTransitions(ctx, currency.ID)
+	if err != nil {
+		s.logger.Error("Failed to list currency transitions", zap.String("symbol", symbol), zap.Error(err))
+		return nil, err
+	}
+
+	responses := make([]dto.CurrencyTransitionResponse, len(transitions))
+	for i, transition := range transitions {
+		responses[i] = dto.CurrencyTransitionResponse{
+			FromStatus: transition.FromStatus,
+			ToStatus:   transition.ToStatus,
+			Actor:      transition.Actor,
+			Reason:     transition.Reason,
+			CreatedAt:  transition.CreatedAt,
+		}
+	}
+	return responses, nil
+}
+
+func (s *CurrencyService) getCurrency(ctx context.Context, symbol string) (*models.Currency, error) {
+	return s.lookupCurrency(ctx, s.currencyRepo, symbol)
+}
+
+// lookupCurrency is getCurrency reading through currencyRepo, such as the
+// repository of a unit of work.
+func (s *CurrencyService) lookupCurrency(ctx context.Context, currencyRepo repository.CurrencyRepository, symbol string) (*models.Currency, error) {
+	currency, err := currencyRepo.GetBySymbol(ctx, symbol)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			s.logger.Warn("Currency not found", zap.String("symbol", symbol))
+			return nil, ErrCurrencyNotFound
+		}
+		s.logger.Error("Failed to get currency", zap.String("symbol", symbol), zap.Error(err))
+		return nil, err
+	}
+	return currency, nil
+}
+
+// transition validates and stores a state change through the repositories of
+// a unit of work, audited as action, and updates currency to match.
+func (s *CurrencyService) transition(ctx context.Context, repos repository.Repositories, currency *models.Currency, to, action, reason string) error {
+	if !models.CanTransition(currency.Status, to) {
+		return fmt.Errorf("%w: %s cannot go from %s to %s", ErrInvalidTransition, currency.Symbol, currency.Status, to)
+	}
+	actor := requestInfoFrom(ctx).Actor
+
+	transition := &models.CurrencyTransition{
+		CurrencyID: currency.ID,
+		FromStatus: currency.Status,
+		ToStatus:   to,
+		Actor:      actor,
+		Reason:     reason,
+	}
+	if err := repos.Currencies.Transition(ctx, transition); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return fmt.Errorf("%w: %s changed state concurrently", ErrInvalidTransition, currency.Symbol)
+		}
+		s.logger.Error("Failed to change currency status",
+			zap.String("symbol", currency.Symbol),
+			zap.String("from", transition.FromStatus),
+			zap.String("to", to),
+			zap.Error(err),
+		)
+		return err
+	}
+	after := newCurrencyAuditState(currency)
+	after.Status = to
+	if err := recordAudit(ctx, repos.Audit, action, models.AuditTargetCurrency, currency.Symbol, newCurrencyAuditState(currency), after); err != nil {
+		return err
+	}
+
+	s.logger.Info("Currency status changed",
+		zap.String("symbol", currency.Symbol),
+		zap.String("from", transition.FromStatus),
+		zap.String("to", to),
+		zap.String("actor", actor),
+		zap.String("reason", reason),
+	)
+	currency.Status = to
+	currency.UpdatedAt = transition.CreatedAt
+	return nil
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"crypto-price-tracker-app/internal/application/dto"
+	"crypto-price-tracker-app/internal/domain/models"
+	"crypto-price-tracker-app/internal/domain/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func matchTransition(from, to string) interface{} {
+	return mock.MatchedBy(func(transition *models.CurrencyTransition) bool {
+		return transition.CurrencyID == 1 && transition.FromStatus == from && transition.ToStatus == to
+	})
+}
+
+func TestCurrencyService_ChangeStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		from       string
+		to         string
+		transition error
+		wantErr    error
+		recorded   bool
+	}{
+		{name: "pause", from: models.CurrencyStatusActive, to: models.CurrencyStatusPaused, recorded: true},
+		{name: "archive", from: models.CurrencyStatusPaused, to: models.CurrencyStatusArchived, recorded: true},
+		{name: "unchanged", from: models.CurrencyStatusPaused, to: models.CurrencyStatusPaused},
+		{name: "delete is a separate call", from: models.CurrencyStatusActive, to: models.CurrencyStatusDeleted, wantErr: ErrInvalidCurrencyRequest},
+		{name: "concurrent change", from: models.CurrencyStatusActive, to: models.CurrencyStatusArchived, transition: repository.ErrConflict, wantErr: ErrInvalidTransition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			currencyRepo := new(MockCurrencyRepository)
+			auditRepo := new(MockAuditRepository)
+			currencyRepo.On("GetBySymbol", mock.Anything, "BTC").Return(&models.Currency{ID: 1, Symbol: "BTC", Status: tt.from}, nil).Maybe()
+			if tt.recorded || tt.transition != nil {
+				currencyRepo.On("Transition", mock.Anything, mock.MatchedBy(func(transition *models.CurrencyTransition) bool {
+					return transition.CurrencyID == 1 && transition.FromStatus == tt.from && transition.ToStatus == tt.to && transition.Actor == "alice"
+				})).Return(tt.transition)
+			}
+			if tt.recorded {
+				auditRepo.On("Create", mock.Anything, mock.MatchedBy(func(event *models.AuditEvent) bool {
+					return event.Actor == "alice" && event.Action == models.AuditActionCurrencyStatus &&
+						event.TargetID == "BTC" && event.RequestID == "req-1" &&
+						strings.Contains(*event.Before, `"status":"`+tt.from+`"`) && strings.Contains(*event.After, `"status":"`+tt.to+`"`)
+				})).Return(nil)
+			}
+
+			service := NewCurrencyService(currencyRepo, new(MockPriceRepository), fakeUnitOfWork{Currencies: currencyRepo, Audit: auditRepo}, nil, 3, zap.NewNop())
+			ctx := WithRequestInfo(context.Background(), RequestInfo{Actor: "alice", RequestID: "req-1", ClientIP: "127.0.0.1"})
+			currency, err := service.ChangeStatus(ctx, &dto.ChangeCurrencyStatusRequest{Symbol: "BTC", Status: tt.to})
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.to, currency.Status)
+			}
+			currencyRepo.AssertExpectations(t)
+			auditRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCurrencyService_DeleteCurrency(t *testing.T) {
+	active := &models.Currency{ID: 1, Symbol: "BTC", Status: models.CurrencyStatusActive}
+
+	t.Run("purges prices before deleting", func(t *testing.T) {
+		currencyRepo := new(MockCurrencyRepository)
+		priceRepo := new(MockPriceRepository)
+		currencyRepo.On("GetBySymbol", mock.Anything, "BTC").Return(active, nil)
+		priceRepo.On("DeleteByCurrency", mock.Anything, uint(1)).Return(int64(42), nil)
+		currencyRepo.On("Transition", mock.Anything, matchTransition(models.CurrencyStatusActive, models.CurrencyStatusDeleted)).Return(nil)
+
+		service := NewCurrencyService(currencyRepo, priceRepo, fakeUnitOfWork{Currencies: currencyRepo, Prices: priceRepo, Audit: acceptAudit()}, nil, 3, zap.NewNop())
+		result, err := service.DeleteCurrency(context.Background(), &dto.DeleteCurrencyRequest{Symbol: "BTC", Prices: DeletePricesPurge})
+		require.NoError(t, err)
+		assert.Equal(t, int64(42), result.DeletedPrices)
+		assert.Equal(t, models.CurrencyStatusDeleted, result.Currency.Status)
+		currencyRepo.AssertExpectations(t)
+		priceRepo.AssertExpectations(t)
+	})
+
+	t.Run("keeps prices by default", func(t *testing.T) {
+		currencyRepo := new(MockCurrencyRepository)
+		currencyRepo.On("GetBySymbol", mock.Anything, "BTC").Return(&models.Currency{ID: 1, Symbol: "BTC", Status: models.CurrencyStatusPaused}, nil)
+		currencyRepo.On("Transition", mock.Anything, matchTransition(models.CurrencyStatusPaused, models.CurrencyStatusDeleted)).Return(nil)
+
+		service := NewCurrencyService(currencyRepo, new(MockPriceRepository), fakeUnitOfWork{Currencies: currencyRepo, Audit: acceptAudit()}, nil, 3, zap.NewNop())
+		result, err := service.DeleteCurrency(context.Background(), &dto.DeleteCurrencyRequest{Symbol: "BTC"})
+		require.NoError(t, err)
+		assert.Equal(t, DeletePricesKeep, result.Prices)
+		currencyRepo.AssertExpectations(t)
+	})
+
+	t.Run("archives only after deleting", func(t *testing.T) {
+		currencyRepo := new(MockCurrencyRepository)
+		currencyRepo.On("GetBySymbol", mock.Anything, "BTC").Return(active, nil)
+		currencyRepo.On("Transition", mock.Anything, mock.Anything).Return(repository.ErrConflict)
+		archiveRepo := new(MockPriceArchiveRepository)
+		archiveService := NewArchiveService(archiveRepo, new(MockPriceRepository), newFakeArchiveStore(), zap.NewNop())
+
+		service := NewCurrencyService(currencyRepo, new(MockPriceRepository), fakeUnitOfWork{Currencies: currencyRepo, Audit: acceptAudit()}, archiveService, 3, zap.NewNop())
+		_, err := service.DeleteCurrency(context.Background(), &dto.DeleteCurrencyRequest{Symbol: "BTC", Prices: DeletePricesArchive})
+		assert.ErrorIs(t, err, ErrInvalidTransition)
+		archiveRepo.AssertNotCalled(t, "ListArchivable", mock.Anything, mock.Anything)
+	})
+
+	t.Run("keeps prices that could not be archived", func(t *testing.T) {
+		month := models.MonthStart(time.Now())
+		currencyRepo := new(MockCurrencyRepository)
+		currencyRepo.On("GetBySymbol", mock.Anything, "BTC").Return(&models.Currency{ID: 1, Symbol: "BTC", Status: models.CurrencyStatusActive}, nil)
+		currencyRepo.On("Transition", mock.Anything, matchTransition(models.CurrencyStatusActive, models.CurrencyStatusDeleted)).Return(nil)
+		archiveRepo := new(MockPriceArchiveRepository)
+		archiveRepo.On("ListArchivable", mock.Anything, mock.Anything).Return([]models.ArchiveGroup{{CurrencyID: 1, Symbol: "BTC", Month: month, Rows: 2}}, nil)
+		archiveRepo.On("GetMonth", mock.Anything, uint(1), month).Return([]models.Price(nil), errors.New("read timeout"))
+		archiveService := NewArchiveService(archiveRepo, new(MockPriceRepository), newFakeArchiveStore(), zap.NewNop())
+
+		service := NewCurrencyService(currencyRepo, new(MockPriceRepository), fakeUnitOfWork{Currencies: currencyRepo, Audit: acceptAudit()}, archiveService, 3, zap.NewNop())
+		result, err := service.DeleteCurrency(context.Background(), &dto.DeleteCurrencyRequest{Symbol: "BTC", Prices: DeletePricesArchive})
+		require.NoError(t, err)
+		assert.Equal(t, models.CurrencyStatusDeleted, result.Currency.Status)
+		assert.Contains(t, result.ArchiveError, "read timeout")
+		assert.Empty(t, result.ArchivedMonths)
+		assert.Zero(t, result.DeletedPrices)
+		archiveRepo.AssertNotCalled(t, "DeleteArchived", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects archiving without an archive", func(t *testing.T) {
+		service := NewCurrencyService(new(MockCurrencyRepository), new(MockPriceRepository), fakeUnitOfWork{}, nil, 3, zap.NewNop())
+		_, err := service.DeleteCurrency(context.Background(), &dto.DeleteCurrencyRequest{Symbol: "BTC", Prices: DeletePricesArchive})
+		assert.ErrorIs(t, err, ErrInvalidCurrencyRequest)
+
+		_, err = service.DeleteCurrency(context.Background(), &dto.DeleteCurrencyRequest{Symbol: "BTC", Prices: "shred"})
+		assert.ErrorIs(t, err, ErrInvalidCurrencyRequest)
+	})
+}
+
+func TestCurrencyService_RestoreCurrency(t *testing.T) {
+	currencyRepo := new(MockCurrencyRepository)
+	currencyRepo.On("GetDeletedBySymbol", mock.Anything, "BTC").Return(&models.Currency{ID: 1, Symbol: "BTC", Status: models.CurrencyStatusDeleted}, nil)
+	currencyRepo.On("GetDeletedBySymbol", mock.Anything, "ETH").Return(nil, repository.ErrNotFound)
+	currencyRepo.On("Transition", mock.Anything, matchTransition(models.CurrencyStatusDeleted, models.CurrencyStatusPaused)).Return(nil)
+
+	service := NewCurrencyService(currencyRepo, new(MockPriceRepository), fakeUnitOfWork{Currencies: currencyRepo, Audit: acceptAudit()}, nil, 3, zap.NewNop())
+	currency, err := service.RestoreCurrency(context.Background(), &dto.RestoreCurrencyRequest{Symbol: "BTC", Reason: "deleted by mistake"})
+	require.NoError(t, err)
+	assert.Equal(t, models.CurrencyStatusPaused, currency.Status)
+
+	_, err = service.RestoreCurrency(context.Background(), &dto.RestoreCurrencyRequest{Symbol: "ETH"})
+	assert.ErrorIs(t, err, ErrCurrencyNotFound)
+	currencyRepo.AssertExpectations(t)
+}
@@ -3,69 +3,114 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
 	"time"
 
 	"crypto-price-tracker-app/internal/application/dto"
 	"crypto-price-tracker-app/internal/domain/models"
 	"crypto-price-tracker-app/internal/domain/repository"
 
+	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
 )
 
 type CurrencyService struct {
-	currencyRepo repository.CurrencyRepository
-	priceRepo    repository.PriceRepository
-	logger       *zap.Logger
+	currencyRepo    repository.CurrencyRepository
+	priceRepo       repository.PriceRepository
+	unitOfWork      repository.UnitOfWork
+	archiveService  *ArchiveService
+	staleMultiplier int
+	logger          *zap.Logger
 }
 
-func NewCurrencyService(currencyRepo repository.CurrencyRepository, priceRepo repository.PriceRepository, logger *zap.Logger) *CurrencyService {
+// NewCurrencyService returns a service whose deletions can archive prices
+// only when archiveService is non-nil.
+func NewCurrencyService(currencyRepo repository.CurrencyRepository, priceRepo repository.PriceRepository, unitOfWork repository.UnitOfWork, archiveService *ArchiveService, staleMultiplier int, logger *zap.Logger) *CurrencyService {
 	return &CurrencyService{
-		currencyRepo: currencyRepo,
-		priceRepo:    priceRepo,
-		logger:       logger,
+		currencyRepo:    currencyRepo,
+		priceRepo:       priceRepo,
+		unitOfWork:      unitOfWork,
+		archiveService:  archiveService,
+		staleMultiplier: staleMultiplier,
+		logger:          logger,
 	}
 }
 
+// ErrCurrencyExists means the symbol is taken by a currency, possibly a
+// deleted one.
+var ErrCurrencyExists = errors.New("currency already exists")
+
 func (s *CurrencyService) AddCurrency(ctx context.Context, req *dto.AddCurrencyRequest) (*dto.CurrencyResponse, error) {
-	existing, err := s.currencyRepo.GetBySymbol(ctx, req.Symbol)
-	if err == nil && existing != nil {
+	_, err := s.currencyRepo.GetBySymbol(ctx, req.Symbol)
+	if err == nil {
 		s.logger.Warn("Currency already exists", zap.String("symbol", req.Symbol))
-		return nil, errors.New("currency already exists")
+		return nil, ErrCurrencyExists
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		s.logger.Error("Failed to check existing currency", zap.String("symbol", req.Symbol), zap.Error(err))
+		return nil, err
+	}
+	// A deleted currency still holds its symbol.
+	_, err = s.currencyRepo.GetDeletedBySymbol(ctx, req.Symbol)
+	if err == nil {
+		s.logger.Warn("Currency was deleted", zap.String("symbol", req.Symbol))
+		return nil, fmt.Errorf("%w: it was deleted, restore it instead", ErrCurrencyExists)
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		s.logger.Error("Failed to check deleted currency", zap.String("symbol", req.Symbol), zap.Error(err))
+		return nil, err
 	}
 
 	currency := &models.Currency{
-		Symbol:   req.Symbol,
-		ApiID:    req.ApiID,
-		Interval: req.Interval,
-		IsActive: true,
+		Symbol:     req.Symbol,
+		ApiID:      req.ApiID,
+		Interval:   req.Interval,
+		StaleAfter: req.StaleAfter,
+		Precision:  req.Precision,
+		Status:     models.CurrencyStatusActive,
+	}
+	if currency.Precision == 0 {
+		currency.Precision = models.DefaultPrecision
 	}
 
-	if err := s.currencyRepo.Create(ctx, currency); err != nil {
-		s.logger.Error("Failed to create currency", zap.String("symbol", req.Symbol), zap.Error(err))
+	err = s.unitOfWork.WithTx(ctx, func(repos repository.Repositories) error {
+		if err := repos.Currencies.Create(ctx, currency); err != nil {
+			// Another request took the symbol after the checks above.
+			if errors.Is(err, repository.ErrDuplicate) || errors.Is(err, repository.ErrConflict) {
+				s.logger.Warn("Currency already exists", zap.String("symbol", req.Symbol))
+				return ErrCurrencyExists
+			}
+			s.logger.Error("Failed to create currency", zap.String("symbol", req.Symbol), zap.Error(err))
+			return err
+		}
+		return recordAudit(ctx, repos.Audit, models.AuditActionCurrencyAdd, models.AuditTargetCurrency, currency.Symbol, nil, newCurrencyAuditState(currency))
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	s.logger.Info("Currency added successfully", zap.String("symbol", req.Symbol), zap.Uint("id", currency.ID))
-	return &dto.CurrencyResponse{
-		ID:        currency.ID,
-		Symbol:    currency.Symbol,
-		ApiID:     currency.ApiID,
-		Interval:  currency.Interval,
-		IsActive:  currency.IsActive,
-		CreatedAt: currency.CreatedAt,
-		UpdatedAt: currency.UpdatedAt,
-	}, nil
+	response := toCurrencyResponse(currency)
+	return &response, nil
 }
 
+// RemoveCurrency pauses a currency; it predates the lifecycle states and is
+// kept for existing clients. Removing a currency that is not active is a no-op.
 func (s *CurrencyService) RemoveCurrency(ctx context.Context, req *dto.RemoveCurrencyRequest) error {
-	_, err := s.currencyRepo.GetBySymbol(ctx, req.Symbol)
+	currency, err := s.getCurrency(ctx, req.Symbol)
 	if err != nil {
-		s.logger.Warn("Currency not found for removal", zap.String("symbol", req.Symbol))
-		return errors.New("currency not found")
+		return err
+	}
+	if !currency.IsActive() {
+		return nil
 	}
 
-	if err := s.currencyRepo.Deactivate(ctx, req.Symbol); err != nil {
-		s.logger.Error("Failed to deactivate currency", zap.String("symbol", req.Symbol), zap.Error(err))
+	err = s.unitOfWork.WithTx(ctx, func(repos repository.Repositories) error {
+		return s.transition(ctx, repos, currency, models.CurrencyStatusPaused, models.AuditActionCurrencyRemove, "removed")
+	})
+	if err != nil {
 		return err
 	}
 
@@ -73,74 +118,495 @@ func (s *CurrencyService) RemoveCurrency(ctx context.Context, req *dto.RemoveCur
 	return nil
 }
 
-func (s *CurrencyService) GetPrice(ctx context.Context, req *dto.GetPriceRequest) (*dto.PriceResponse, error) {
-	currencyInterface, err := s.currencyRepo.GetBySymbol(ctx, req.Coin)
+func (s *CurrencyService) GetCurrency(ctx context.Context, symbol string) (*dto.CurrencyResponse, error) {
+	currency, err := s.getCurrency(ctx, symbol)
 	if err != nil {
-		s.logger.Error("Failed to get currency", zap.String("symbol", req.Coin), zap.Error(err))
-		return nil, errors.New("currency not found")
+		return nil, err
 	}
+	response := toCurrencyResponse(currency)
+	return &response, nil
+}
 
-	if currencyInterface == nil {
-		s.logger.Warn("Currency not found", zap.String("symbol", req.Coin))
-		return nil, errors.New("currency not found")
+// UpdateCurrency changes the state first, then the settings, in one
+// transaction; each is audited on its own. The currency is read inside the
+// transaction, bypassing caches and replicas, and a concurrent transition
+// fails the request with ErrInvalidTransition instead of being overwritten.
+// Fields that already hold the requested value are left alone, so a request
+// changing nothing records nothing.
+func (s *CurrencyService) UpdateCurrency(ctx context.Context, req *dto.UpdateCurrencyRequest) (*dto.CurrencyResponse, error) {
+	var currency *models.Currency
+	err := s.unitOfWork.WithTx(ctx, func(repos repository.Repositories) error {
+		var err error
+		currency, err = s.lookupCurrency(ctx, repos.Currencies, req.Symbol)
+		if err != nil {
+			return err
+		}
+
+		var status string
+		if req.Active != nil {
+			switch {
+			case *req.Active && !currency.IsActive():
+				status = models.CurrencyStatusActive
+			case !*req.Active && currency.IsActive():
+				status = models.CurrencyStatusPaused
+			}
+		}
+		apiID, interval := currency.ApiID, currency.Interval
+		if req.ApiID != nil {
+			apiID = *req.ApiID
+		}
+		if req.Interval != nil {
+			interval = *req.Interval
+		}
+
+		if status != "" {
+			if err := s.transition(ctx, repos, currency, status, models.AuditActionCurrencyStatus, req.Reason); err != nil {
+				return err
+			}
+		}
+		if apiID == currency.ApiID && interval == currency.Interval {
+			return nil
+		}
+
+		before := newCurrencyAuditState(currency)
+		currency.ApiID, currency.Interval = apiID, interval
+		if err := repos.Currencies.Update(ctx, currency); err != nil {
+			if errors.Is(err, repository.ErrConflict) {
+				return fmt.Errorf("%w: %s changed state concurrently", ErrInvalidTransition, currency.Symbol)
+			}
+			s.logger.Error("Failed to update currency", zap.String("symbol", currency.Symbol), zap.Error(err))
+			return err
+		}
+		if err := recordAudit(ctx, repos.Audit, models.AuditActionCurrencyUpdate, models.AuditTargetCurrency, currency.Symbol, before, newCurrencyAuditState(currency)); err != nil {
+			return err
+		}
+		s.logger.Info("Currency updated", zap.String("symbol", currency.Symbol), zap.String("api_id", apiID), zap.Int("interval", interval))
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	currency := currencyInterface.(*models.Currency)
+	response := toCurrencyResponse(currency)
+	return &response, nil
+}
+
+var ErrInvalidPriceRequest = errors.New("invalid price request")
+
+// GetPrice answers a point-in-time lookup. Without a mode an exact match wins
+// and otherwise the nearest price is used, preferring the one before the
+// moment. With max_gap a result whose sources are too far from the moment
+// (for linear: from each other) is treated as missing.
+func (s *CurrencyService) GetPrice(ctx context.Context, req *dto.GetPriceRequest) (*dto.PriceResponse, error) {
+	mode, maxGap, err := parseLookupOptions(req.Mode, req.MaxGap)
+	if err != nil {
+		return nil, err
+	}
+
+	currency, err := s.currencyRepo.GetBySymbol(ctx, req.Coin)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			s.logger.Warn("Currency not found", zap.String("symbol", req.Coin))
+		} else {
+			s.logger.Error("Failed to get currency", zap.String("symbol", req.Coin), zap.Error(err))
+		}
+		return nil, ErrCurrencyNotFound
+	}
 
 	timestamp := time.Unix(req.Timestamp, 0)
 
-	priceInterface, err := s.priceRepo.GetByCurrencyAndTime(ctx, currency.ID, timestamp)
+	var result *priceLookup
+	if mode == "" {
+		result, err = s.lookupDefaultPrice(ctx, currency, timestamp)
+	} else {
+		result, err = s.lookupPrice(ctx, currency, timestamp, mode)
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		s.logger.Warn("No price found for currency", zap.String("symbol", req.Coin), zap.Time("timestamp", timestamp), zap.String("mode", req.Mode))
+		return nil, errors.New("price not found")
+	}
 	if err != nil {
-		s.logger.Error("Failed to get price by time", zap.String("symbol", req.Coin), zap.Time("timestamp", timestamp), zap.Error(err))
+		s.logger.Error("Failed to get price", zap.String("symbol", req.Coin), zap.Time("timestamp", timestamp), zap.Error(err))
 		return nil, errors.New("price not found")
 	}
+	if maxGap > 0 && result.gap > maxGap {
+		s.logger.Debug("Price is further away than max_gap", zap.String("symbol", req.Coin), zap.Duration("gap", result.gap), zap.Duration("max_gap", maxGap))
+		return nil, fmt.Errorf("price not found within %s", maxGap)
+	}
 
-	if priceInterface == nil {
-		s.logger.Debug("Exact price not found, searching for nearest", zap.String("symbol", req.Coin), zap.Time("timestamp", timestamp))
-		priceInterface, err = s.priceRepo.GetNearestPrice(ctx, currency.ID, timestamp)
-		if err != nil {
-			s.logger.Error("Failed to get nearest price", zap.String("symbol", req.Coin), zap.Time("timestamp", timestamp), zap.Error(err))
-			return nil, errors.New("price not found")
-		}
-		if priceInterface == nil {
-			s.logger.Warn("No price found for currency", zap.String("symbol", req.Coin), zap.Time("timestamp", timestamp))
-			return nil, errors.New("price not found")
+	s.logger.Debug("Price retrieved successfully", zap.String("symbol", req.Coin), zap.Stringer("price", result.price.Price), zap.Time("timestamp", result.price.Timestamp), zap.String("mode", string(result.mode)))
+	return s.toPriceResponse(currency, timestamp, result), nil
+}
+
+func parseLookupOptions(rawMode, rawMaxGap string) (models.PriceLookupMode, time.Duration, error) {
+	mode := models.PriceLookupMode(rawMode)
+	if mode != "" && !mode.Valid() {
+		return "", 0, fmt.Errorf("%w: unknown mode %q", ErrInvalidPriceRequest, rawMode)
+	}
+	var maxGap time.Duration
+	if rawMaxGap != "" {
+		parsed, err := time.ParseDuration(rawMaxGap)
+		if err != nil || parsed <= 0 {
+			return "", 0, fmt.Errorf("%w: max_gap must be a positive duration such as 90s or 1h", ErrInvalidPriceRequest)
 		}
+		maxGap = parsed
+	}
+	return mode, maxGap, nil
+}
+
+func (s *CurrencyService) toPriceResponse(currency *models.Currency, timestamp time.Time, result *priceLookup) *dto.PriceResponse {
+	// Age is measured against the requested moment, so a historical lookup is
+	// not reported as stale just because it is in the past.
+	reference := time.Now()
+	if timestamp.Before(reference) {
+		reference = timestamp
+	}
+	var age time.Duration
+	for _, source := range result.sources {
+		age = max(age, absDuration(reference.Sub(source)))
 	}
 
-	price := priceInterface.(*models.Price)
+	price := result.price
+	response := &dto.PriceResponse{
+		ID:               price.ID,
+		Symbol:           currency.Symbol,
+		Price:            price.Price.Round(int32(currency.Precision)),
+		Timestamp:        price.Timestamp,
+		Mode:             string(result.mode),
+		SourceTimestamps: result.sources,
+		AgeSeconds:       int64(age.Seconds()),
+		IsStale:          age > currency.StaleThreshold(s.staleMultiplier),
+	}
+	if !price.CreatedAt.IsZero() {
+		response.CreatedAt = &price.CreatedAt
+	}
+	return response
+}
 
-	s.logger.Debug("Price retrieved successfully", zap.String("symbol", req.Coin), zap.Float64("price", price.Price), zap.Time("timestamp", price.Timestamp))
-	return &dto.PriceResponse{
-		ID:        price.ID,
-		Symbol:    currency.Symbol,
-		Price:     price.Price,
-		Timestamp: price.Timestamp,
-		CreatedAt: price.CreatedAt,
-	}, nil
+// priceLookup is the answer to a point-in-time lookup. gap is the distance
+// that max_gap is compared with.
+type priceLookup struct {
+	mode    models.PriceLookupMode
+	price   models.Price
+	sources []time.Time
+	gap     time.Duration
+}
+
+func storedPrice(mode models.PriceLookupMode, price *models.Price, timestamp time.Time) *priceLookup {
+	return &priceLookup{
+		mode:    mode,
+		price:   *price,
+		sources: []time.Time{price.Timestamp},
+		gap:     absDuration(timestamp.Sub(price.Timestamp)),
+	}
+}
+
+func (s *CurrencyService) lookupDefaultPrice(ctx context.Context, currency *models.Currency, timestamp time.Time) (*priceLookup, error) {
+	price, err := s.priceRepo.GetByCurrencyAndTime(ctx, currency.ID, timestamp)
+	if errors.Is(err, repository.ErrNotFound) {
+		s.logger.Debug("Exact price not found, searching for nearest", zap.String("symbol", currency.Symbol), zap.Time("timestamp", timestamp))
+		price, err = s.priceRepo.GetNearestPrice(ctx, currency.ID, timestamp)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	mode := models.PriceLookupPrevious
+	if price.Timestamp.After(timestamp) {
+		mode = models.PriceLookupNext
+	}
+	return storedPrice(mode, price, timestamp), nil
+}
+
+func (s *CurrencyService) lookupPrice(ctx context.Context, currency *models.Currency, timestamp time.Time, mode models.PriceLookupMode) (*priceLookup, error) {
+	before, after, err := s.priceRepo.GetSurroundingPrices(ctx, currency.ID, timestamp)
+	if err != nil {
+		return nil, err
+	}
+	if result := pickPrice(currency, timestamp, mode, before, after); result != nil {
+		return result, nil
+	}
+	return nil, repository.ErrNotFound
+}
+
+// pickPrice answers a lookup from the prices surrounding timestamp, or
+// returns nil when they cannot. Without a mode it behaves like
+// lookupDefaultPrice: an exact match is also the price before the moment.
+func pickPrice(currency *models.Currency, timestamp time.Time, mode models.PriceLookupMode, before, after *models.Price) *priceLookup {
+	switch mode {
+	case "":
+		if before != nil {
+			return storedPrice(models.PriceLookupPrevious, before, timestamp)
+		}
+		if after != nil {
+			return storedPrice(models.PriceLookupNext, after, timestamp)
+		}
+	case models.PriceLookupPrevious:
+		if before != nil {
+			return storedPrice(mode, before, timestamp)
+		}
+	case models.PriceLookupNext:
+		if after != nil {
+			return storedPrice(mode, after, timestamp)
+		}
+	case models.PriceLookupNearest:
+		if before == nil && after == nil {
+			break
+		}
+		if after == nil || (before != nil && timestamp.Sub(before.Timestamp) <= after.Timestamp.Sub(timestamp)) {
+			return storedPrice(mode, before, timestamp)
+		}
+		return storedPrice(mode, after, timestamp)
+	case models.PriceLookupLinear:
+		if before == nil || after == nil {
+			break
+		}
+		if before.Timestamp.Equal(after.Timestamp) {
+			return storedPrice(mode, before, timestamp)
+		}
+		span := after.Timestamp.Sub(before.Timestamp)
+		offset := timestamp.Sub(before.Timestamp)
+		value := before.Price.Add(after.Price.Sub(before.Price).
+			Mul(decimal.NewFromInt(int64(offset))).
+			Div(decimal.NewFromInt(int64(span))))
+		return &priceLookup{
+			mode:    mode,
+			price:   models.Price{CurrencyID: currency.ID, Price: value, Timestamp: timestamp},
+			sources: []time.Time{before.Timestamp, after.Timestamp},
+			gap:     span,
+		}
+	}
+	return nil
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
 }
 
 func (s *CurrencyService) GetAllActiveCurrencies(ctx context.Context) ([]dto.CurrencyResponse, error) {
-	currenciesInterface, err := s.currencyRepo.GetAllActive(ctx)
+	currencies, err := s.currencyRepo.GetAllActive(ctx)
 	if err != nil {
 		s.logger.Error("Failed to get active currencies", zap.Error(err))
 		return nil, err
 	}
 
 	var responses []dto.CurrencyResponse
-	for _, currencyInterface := range currenciesInterface {
-		currency := currencyInterface.(*models.Currency)
-		responses = append(responses, dto.CurrencyResponse{
-			ID:        currency.ID,
-			Symbol:    currency.Symbol,
-			ApiID:     currency.ApiID,
-			Interval:  currency.Interval,
-			IsActive:  currency.IsActive,
-			CreatedAt: currency.CreatedAt,
-			UpdatedAt: currency.UpdatedAt,
-		})
+	for i := range currencies {
+		responses = append(responses, toCurrencyResponse(&currencies[i]))
 	}
 
 	s.logger.Debug("Retrieved active currencies", zap.Int("count", len(responses)))
 	return responses, nil
 }
+
+func (s *CurrencyService) GetStaleCurrencies(ctx context.Context) ([]dto.StaleCurrencyResponse, error) {
+	currencies, err := s.currencyRepo.GetAllActive(ctx)
+	if err != nil {
+		s.logger.Error("Failed to get active currencies", zap.Error(err))
+		return nil, err
+	}
+
+	latest, err := s.latestPrices(ctx, currencies)
+	if err != nil {
+		s.logger.Error("Failed to get latest prices", zap.Error(err))
+		return nil, err
+	}
+
+	now := time.Now()
+	responses := make([]dto.StaleCurrencyResponse, 0)
+	for i := range currencies {
+		currency := &currencies[i]
+		threshold := currency.StaleThreshold(s.staleMultiplier)
+		price := latest[currency.ID]
+
+		response := dto.StaleCurrencyResponse{
+			Symbol:            currency.Symbol,
+			ApiID:             currency.ApiID,
+			Interval:          currency.Interval,
+			StaleAfterSeconds: int64(threshold.Seconds()),
+		}
+
+		if price != nil {
+			age := now.Sub(price.Timestamp)
+			if age <= threshold {
+				continue
+			}
+			ageSeconds := int64(age.Seconds())
+			response.LatestPriceAt = &price.Timestamp
+			response.AgeSeconds = &ageSeconds
+		}
+
+		responses = append(responses, response)
+	}
+
+	s.logger.Debug("Retrieved stale currencies", zap.Int("count", len(responses)))
+	return responses, nil
+}
+
+// latestPrices maps each of currencies that has a price to its latest one.
+func (s *CurrencyService) latestPrices(ctx context.Context, currencies []models.Currency) (map[uint]*models.Price, error) {
+	ids := make([]uint, len(currencies))
+	for i, currency := range currencies {
+		ids[i] = currency.ID
+	}
+
+	prices, err := s.priceRepo.GetLatestPrices(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	latest := make(map[uint]*models.Price, len(prices))
+	for i := range prices {
+		latest[prices[i].CurrencyID] = &prices[i]
+	}
+	return latest, nil
+}
+
+const (
+	defaultCandleCount = 100
+	maxCandleCount     = 5000
+)
+
+var (
+	ErrInvalidCandleRequest = errors.New("invalid candle request")
+	candleIntervalPattern   = regexp.MustCompile(`^(\d+)([mhdw])$`)
+	candleIntervalUnits     = map[string]time.Duration{
+		"m": time.Minute,
+		"h": time.Hour,
+		"d": 24 * time.Hour,
+		"w": 7 * 24 * time.Hour,
+	}
+)
+
+// ParseCandleInterval accepts intervals such as 5m, 1h, 1d or 1w.
+func ParseCandleInterval(value string) (time.Duration, error) {
+	matches := candleIntervalPattern.FindStringSubmatch(value)
+	if matches == nil {
+		return 0, fmt.Errorf("%w: interval must look like 5m, 1h, 1d or 1w", ErrInvalidCandleRequest)
+	}
+	count, err := strconv.Atoi(matches[1])
+	if err != nil || count <= 0 {
+		return 0, fmt.Errorf("%w: interval must be positive", ErrInvalidCandleRequest)
+	}
+	return time.Duration(count) * candleIntervalUnits[matches[2]], nil
+}
+
+func (s *CurrencyService) GetCandles(ctx context.Context, req *dto.GetCandlesRequest) (*dto.CandlesResponse, error) {
+	interval, err := ParseCandleInterval(req.Interval)
+	if err != nil {
+		return nil, err
+	}
+
+	to := time.Now().UTC()
+	if req.To != 0 {
+		to = time.Unix(req.To, 0).UTC()
+	}
+	from := to.Add(-defaultCandleCount * interval)
+	if req.From != 0 {
+		from = time.Unix(req.From, 0).UTC()
+	}
+	// The first bucket is widened to its boundary so it is not cut in half.
+	from = models.CandleBucket(from, interval)
+	if !from.Before(to) {
+		return nil, fmt.Errorf("%w: from must be before to", ErrInvalidCandleRequest)
+	}
+	if to.Sub(from)/interval > maxCandleCount {
+		return nil, fmt.Errorf("%w: range exceeds %d candles", ErrInvalidCandleRequest, maxCandleCount)
+	}
+
+	currency, err := s.currencyRepo.GetBySymbol(ctx, req.Symbol)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			s.logger.Warn("Currency not found", zap.String("symbol", req.Symbol))
+			return nil, ErrCurrencyNotFound
+		}
+		s.logger.Error("Failed to get currency", zap.String("symbol", req.Symbol), zap.Error(err))
+		return nil, err
+	}
+
+	candles, err := s.priceRepo.GetCandles(ctx, currency.ID, from, to, interval)
+	if err != nil {
+		s.logger.Error("Failed to get candles", zap.String("symbol", req.Symbol), zap.Error(err))
+		return nil, err
+	}
+
+	response := &dto.CandlesResponse{
+		Symbol:   currency.Symbol,
+		Interval: req.Interval,
+		From:     from,
+		To:       to,
+		Candles:  make([]dto.CandleResponse, 0, len(candles)),
+	}
+	precision := int32(currency.Precision)
+
+	if !req.Fill {
+		for _, candle := range candles {
+			response.Candles = append(response.Candles, toCandleResponse(candle, precision))
+		}
+		return response, nil
+	}
+
+	var last *decimal.Decimal
+	previous, err := s.priceRepo.GetNearestPrice(ctx, currency.ID, from)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		s.logger.Error("Failed to get price before candle range", zap.String("symbol", req.Symbol), zap.Error(err))
+		return nil, err
+	}
+	if previous != nil && previous.Timestamp.Before(from) {
+		last = &previous.Price
+	}
+
+	next := 0
+	for bucket := from; bucket.Before(to); bucket = bucket.Add(interval) {
+		if next < len(candles) && !candles[next].Bucket.After(bucket) {
+			candle := candles[next]
+			next++
+			response.Candles = append(response.Candles, toCandleResponse(candle, precision))
+			last = &candle.Close
+			continue
+		}
+		if last == nil {
+			continue
+		}
+		value := last.Round(precision)
+		response.Candles = append(response.Candles, dto.CandleResponse{
+			Time:   bucket,
+			Open:   value,
+			High:   value,
+			Low:    value,
+			Close:  value,
+			Filled: true,
+		})
+	}
+
+	return response, nil
+}
+
+func toCandleResponse(candle models.Candle, precision int32) dto.CandleResponse {
+	return dto.CandleResponse{
+		Time:        candle.Bucket.UTC(),
+		Open:        candle.Open.Round(precision),
+		High:        candle.High.Round(precision),
+		Low:         candle.Low.Round(precision),
+		Close:       candle.Close.Round(precision),
+		SampleCount: candle.SampleCount,
+	}
+}
+
+func toCurrencyResponse(currency *models.Currency) dto.CurrencyResponse {
+	return dto.CurrencyResponse{
+		ID:         currency.ID,
+		Symbol:     currency.Symbol,
+		ApiID:      currency.ApiID,
+		Interval:   currency.Interval,
+		StaleAfter: currency.StaleAfter,
+		Precision:  currency.Precision,
+		Status:     currency.Status,
+		IsActive:   currency.IsActive(),
+		CreatedAt:  currency.CreatedAt,
+		UpdatedAt:  currency.UpdatedAt,
+	}
+}
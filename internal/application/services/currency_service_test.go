@@ -8,9 +8,12 @@ import (
 
 	"crypto-price-tracker-app/internal/application/dto"
 	"crypto-price-tracker-app/internal/domain/models"
+	"crypto-price-tracker-app/internal/domain/repository"
 
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 )
 
@@ -18,85 +21,151 @@ type MockCurrencyRepository struct {
 	mock.Mock
 }
 
-func (m *MockCurrencyRepository) Create(ctx context.Context, currency interface{}) error {
+func (m *MockCurrencyRepository) Create(ctx context.Context, currency *models.Currency) error {
 	args := m.Called(ctx, currency)
 	return args.Error(0)
 }
 
-func (m *MockCurrencyRepository) GetBySymbol(ctx context.Context, symbol string) (interface{}, error) {
+func (m *MockCurrencyRepository) GetBySymbol(ctx context.Context, symbol string) (*models.Currency, error) {
 	args := m.Called(ctx, symbol)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0), args.Error(1)
+	return args.Get(0).(*models.Currency), args.Error(1)
 }
 
-func (m *MockCurrencyRepository) GetAllActive(ctx context.Context) ([]interface{}, error) {
+func (m *MockCurrencyRepository) GetBySymbols(ctx context.Context, symbols []string) ([]models.Currency, error) {
+	args := m.Called(ctx, symbols)
+	currencies, _ := args.Get(0).([]models.Currency)
+	return currencies, args.Error(1)
+}
+
+func (m *MockCurrencyRepository) GetDeletedBySymbol(ctx context.Context, symbol string) (*models.Currency, error) {
+	args := m.Called(ctx, symbol)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Currency), args.Error(1)
+}
+
+func (m *MockCurrencyRepository) GetAllActive(ctx context.Context) ([]models.Currency, error) {
 	args := m.Called(ctx)
-	return args.Get(0).([]interface{}), args.Error(1)
+	return args.Get(0).([]models.Currency), args.Error(1)
 }
 
-func (m *MockCurrencyRepository) Update(ctx context.Context, currency interface{}) error {
+func (m *MockCurrencyRepository) Update(ctx context.Context, currency *models.Currency) error {
 	args := m.Called(ctx, currency)
 	return args.Error(0)
 }
 
-func (m *MockCurrencyRepository) Delete(ctx context.Context, symbol string) error {
-	args := m.Called(ctx, symbol)
+func (m *MockCurrencyRepository) Transition(ctx context.Context, transition *models.CurrencyTransition) error {
+	args := m.Called(ctx, transition)
 	return args.Error(0)
 }
 
-func (m *MockCurrencyRepository) Deactivate(ctx context.Context, symbol string) error {
-	args := m.Called(ctx, symbol)
-	return args.Error(0)
+func (m *MockCurrencyRepository) ListTransitions(ctx context.Context, currencyID uint) ([]models.CurrencyTransition, error) {
+	args := m.Called(ctx, currencyID)
+	return args.Get(0).([]models.CurrencyTransition), args.Error(1)
+}
+
+// fakeUnitOfWork runs units of work directly on the given repositories.
+type fakeUnitOfWork repository.Repositories
+
+func (u fakeUnitOfWork) WithTx(ctx context.Context, fn func(repos repository.Repositories) error) error {
+	return fn(repository.Repositories(u))
 }
 
 type MockPriceRepository struct {
 	mock.Mock
 }
 
-func (m *MockPriceRepository) Create(ctx context.Context, price interface{}) error {
+func (m *MockPriceRepository) Create(ctx context.Context, price *models.Price) error {
 	args := m.Called(ctx, price)
 	return args.Error(0)
 }
 
-func (m *MockPriceRepository) GetByCurrencyAndTime(ctx context.Context, currencyID uint, timestamp time.Time) (interface{}, error) {
+func (m *MockPriceRepository) CreateBatch(ctx context.Context, prices []models.Price, onConflict models.ConflictAction) (*models.BatchResult, error) {
+	args := m.Called(ctx, prices, onConflict)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BatchResult), args.Error(1)
+}
+
+func (m *MockPriceRepository) GetByCurrencyAndTime(ctx context.Context, currencyID uint, timestamp time.Time) (*models.Price, error) {
 	args := m.Called(ctx, currencyID, timestamp)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0), args.Error(1)
+	return args.Get(0).(*models.Price), args.Error(1)
 }
 
-func (m *MockPriceRepository) GetNearestPrice(ctx context.Context, currencyID uint, timestamp time.Time) (interface{}, error) {
+func (m *MockPriceRepository) GetNearestPrice(ctx context.Context, currencyID uint, timestamp time.Time) (*models.Price, error) {
 	args := m.Called(ctx, currencyID, timestamp)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0), args.Error(1)
+	return args.Get(0).(*models.Price), args.Error(1)
+}
+
+func (m *MockPriceRepository) GetSurroundingPrices(ctx context.Context, currencyID uint, timestamp time.Time) (*models.Price, *models.Price, error) {
+	args := m.Called(ctx, currencyID, timestamp)
+	before, _ := args.Get(0).(*models.Price)
+	after, _ := args.Get(1).(*models.Price)
+	return before, after, args.Error(2)
+}
+
+func (m *MockPriceRepository) GetSurroundingPricesBatch(ctx context.Context, keys []models.PriceKey) ([]models.SurroundingPrices, error) {
+	args := m.Called(ctx, keys)
+	prices, _ := args.Get(0).([]models.SurroundingPrices)
+	return prices, args.Error(1)
 }
 
-func (m *MockPriceRepository) GetLatestPrice(ctx context.Context, currencyID uint) (interface{}, error) {
+func (m *MockPriceRepository) GetLatestPrice(ctx context.Context, currencyID uint) (*models.Price, error) {
 	args := m.Called(ctx, currencyID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0), args.Error(1)
+	return args.Get(0).(*models.Price), args.Error(1)
+}
+
+func (m *MockPriceRepository) GetLatestPrices(ctx context.Context, currencyIDs []uint) ([]models.Price, error) {
+	args := m.Called(ctx, currencyIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Price), args.Error(1)
 }
 
-func (m *MockPriceRepository) GetPriceHistory(ctx context.Context, currencyID uint, from, to time.Time) ([]interface{}, error) {
+func (m *MockPriceRepository) GetPriceHistory(ctx context.Context, currencyID uint, from, to time.Time) ([]models.Price, error) {
 	args := m.Called(ctx, currencyID, from, to)
-	return args.Get(0).([]interface{}), args.Error(1)
+	return args.Get(0).([]models.Price), args.Error(1)
+}
+
+func (m *MockPriceRepository) GetPriceHistoryPage(ctx context.Context, currencyID uint, query models.PriceHistoryQuery) ([]models.Price, error) {
+	args := m.Called(ctx, currencyID, query)
+	return args.Get(0).([]models.Price), args.Error(1)
+}
+
+func (m *MockPriceRepository) GetCandles(ctx context.Context, currencyID uint, from, to time.Time, interval time.Duration) ([]models.Candle, error) {
+	args := m.Called(ctx, currencyID, from, to, interval)
+	return args.Get(0).([]models.Candle), args.Error(1)
+}
+
+func (m *MockPriceRepository) DeleteByCurrency(ctx context.Context, currencyID uint) (int64, error) {
+	args := m.Called(ctx, currencyID)
+	return args.Get(0).(int64), args.Error(1)
 }
 
 func TestCurrencyService_AddCurrency(t *testing.T) {
 	logger, _ := zap.NewDevelopment()
 
 	tests := []struct {
-		name    string
-		req     *dto.AddCurrencyRequest
-		setup   func(*MockCurrencyRepository)
-		wantErr bool
+		name      string
+		req       *dto.AddCurrencyRequest
+		setup     func(*MockCurrencyRepository)
+		wantErr   bool
+		wantErrIs error
 	}{
 		{
 			name: "successful add",
@@ -105,11 +174,52 @@ func TestCurrencyService_AddCurrency(t *testing.T) {
 				Interval: 60,
 			},
 			setup: func(m *MockCurrencyRepository) {
-				m.On("GetBySymbol", mock.Anything, "bitcoin").Return(nil, errors.New("not found"))
-				m.On("Create", mock.Anything, mock.AnythingOfType("*models.Currency")).Return(nil)
+				m.On("GetBySymbol", mock.Anything, "bitcoin").Return(nil, repository.ErrNotFound)
+				m.On("GetDeletedBySymbol", mock.Anything, "bitcoin").Return(nil, repository.ErrNotFound)
+				m.On("Create", mock.Anything, mock.MatchedBy(func(currency *models.Currency) bool {
+					return currency.Status == models.CurrencyStatusActive
+				})).Return(nil)
 			},
 			wantErr: false,
 		},
+		{
+			name: "currency was deleted",
+			req: &dto.AddCurrencyRequest{
+				Symbol:   "bitcoin",
+				Interval: 60,
+			},
+			setup: func(m *MockCurrencyRepository) {
+				m.On("GetBySymbol", mock.Anything, "bitcoin").Return(nil, repository.ErrNotFound)
+				m.On("GetDeletedBySymbol", mock.Anything, "bitcoin").Return(&models.Currency{Status: models.CurrencyStatusDeleted}, nil)
+			},
+			wantErr:   true,
+			wantErrIs: ErrCurrencyExists,
+		},
+		{
+			name: "currency created concurrently",
+			req: &dto.AddCurrencyRequest{
+				Symbol:   "bitcoin",
+				Interval: 60,
+			},
+			setup: func(m *MockCurrencyRepository) {
+				m.On("GetBySymbol", mock.Anything, "bitcoin").Return(nil, repository.ErrNotFound)
+				m.On("GetDeletedBySymbol", mock.Anything, "bitcoin").Return(nil, repository.ErrNotFound)
+				m.On("Create", mock.Anything, mock.Anything).Return(repository.ErrDuplicate)
+			},
+			wantErr:   true,
+			wantErrIs: ErrCurrencyExists,
+		},
+		{
+			name: "lookup failure",
+			req: &dto.AddCurrencyRequest{
+				Symbol:   "bitcoin",
+				Interval: 60,
+			},
+			setup: func(m *MockCurrencyRepository) {
+				m.On("GetBySymbol", mock.Anything, "bitcoin").Return(nil, errors.New("connection refused"))
+			},
+			wantErr: true,
+		},
 		{
 			name: "currency already exists",
 			req: &dto.AddCurrencyRequest{
@@ -119,7 +229,8 @@ func TestCurrencyService_AddCurrency(t *testing.T) {
 			setup: func(m *MockCurrencyRepository) {
 				m.On("GetBySymbol", mock.Anything, "bitcoin").Return(&models.Currency{}, nil)
 			},
-			wantErr: true,
+			wantErr:   true,
+			wantErrIs: ErrCurrencyExists,
 		},
 	}
 
@@ -129,11 +240,14 @@ func TestCurrencyService_AddCurrency(t *testing.T) {
 			mockPriceRepo := &MockPriceRepository{}
 			tt.setup(mockRepo)
 
-			service := NewCurrencyService(mockRepo, mockPriceRepo, logger)
+			service := NewCurrencyService(mockRepo, mockPriceRepo, fakeUnitOfWork{Currencies: mockRepo, Prices: mockPriceRepo, Audit: acceptAudit()}, nil, 3, logger)
 			_, err := service.AddCurrency(context.Background(), tt.req)
 
 			if tt.wantErr {
 				assert.Error(t, err)
+				if tt.wantErrIs != nil {
+					assert.ErrorIs(t, err, tt.wantErrIs)
+				}
 			} else {
 				assert.NoError(t, err)
 			}
@@ -143,6 +257,134 @@ func TestCurrencyService_AddCurrency(t *testing.T) {
 	}
 }
 
+func TestCurrencyService_GetPrice(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	currency := &models.Currency{ID: 1, Symbol: "BTC", Interval: 60, Precision: 8}
+	timestamp := time.Unix(1700000000, 0)
+
+	tests := []struct {
+		name          string
+		setupMocks    func(*MockCurrencyRepository, *MockPriceRepository)
+		expectedError string
+	}{
+		{
+			name: "exact match",
+			setupMocks: func(currencyRepo *MockCurrencyRepository, priceRepo *MockPriceRepository) {
+				currencyRepo.On("GetBySymbol", mock.Anything, "BTC").Return(currency, nil)
+				priceRepo.On("GetByCurrencyAndTime", mock.Anything, uint(1), timestamp).Return(&models.Price{ID: 1, Timestamp: timestamp}, nil)
+			},
+		},
+		{
+			name: "falls back to nearest price",
+			setupMocks: func(currencyRepo *MockCurrencyRepository, priceRepo *MockPriceRepository) {
+				currencyRepo.On("GetBySymbol", mock.Anything, "BTC").Return(currency, nil)
+				priceRepo.On("GetByCurrencyAndTime", mock.Anything, uint(1), timestamp).Return(nil, repository.ErrNotFound)
+				priceRepo.On("GetNearestPrice", mock.Anything, uint(1), timestamp).Return(&models.Price{ID: 2, Timestamp: timestamp.Add(-time.Minute)}, nil)
+			},
+		},
+		{
+			name: "no price at all",
+			setupMocks: func(currencyRepo *MockCurrencyRepository, priceRepo *MockPriceRepository) {
+				currencyRepo.On("GetBySymbol", mock.Anything, "BTC").Return(currency, nil)
+				priceRepo.On("GetByCurrencyAndTime", mock.Anything, uint(1), timestamp).Return(nil, repository.ErrNotFound)
+				priceRepo.On("GetNearestPrice", mock.Anything, uint(1), timestamp).Return(nil, repository.ErrNotFound)
+			},
+			expectedError: "price not found",
+		},
+		{
+			name: "unknown currency",
+			setupMocks: func(currencyRepo *MockCurrencyRepository, priceRepo *MockPriceRepository) {
+				currencyRepo.On("GetBySymbol", mock.Anything, "BTC").Return(nil, repository.ErrNotFound)
+			},
+			expectedError: "currency not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCurrencyRepo := new(MockCurrencyRepository)
+			mockPriceRepo := new(MockPriceRepository)
+			tt.setupMocks(mockCurrencyRepo, mockPriceRepo)
+
+			service := NewCurrencyService(mockCurrencyRepo, mockPriceRepo, fakeUnitOfWork{Currencies: mockCurrencyRepo, Prices: mockPriceRepo, Audit: acceptAudit()}, nil, 3, logger)
+			price, err := service.GetPrice(context.Background(), &dto.GetPriceRequest{Coin: "BTC", Timestamp: timestamp.Unix()})
+
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "BTC", price.Symbol)
+			}
+
+			mockCurrencyRepo.AssertExpectations(t)
+			mockPriceRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCurrencyService_GetPriceModes(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	currency := &models.Currency{ID: 1, Symbol: "BTC", Interval: 60, Precision: 2}
+	timestamp := time.Unix(1700000000, 0)
+	before := &models.Price{ID: 1, Price: decimal.RequireFromString("100"), Timestamp: timestamp.Add(-10 * time.Minute)}
+	after := &models.Price{ID: 2, Price: decimal.RequireFromString("130"), Timestamp: timestamp.Add(20 * time.Minute)}
+
+	tests := []struct {
+		name        string
+		mode        string
+		maxGap      string
+		before      *models.Price
+		after       *models.Price
+		wantPrice   string
+		wantSources []time.Time
+		wantError   string
+	}{
+		{name: "previous", mode: "previous", before: before, after: after, wantPrice: "100", wantSources: []time.Time{before.Timestamp}},
+		{name: "next", mode: "next", before: before, after: after, wantPrice: "130", wantSources: []time.Time{after.Timestamp}},
+		{name: "nearest picks the closer neighbour", mode: "nearest", before: before, after: after, wantPrice: "100", wantSources: []time.Time{before.Timestamp}},
+		{name: "nearest with one neighbour", mode: "nearest", after: after, wantPrice: "130", wantSources: []time.Time{after.Timestamp}},
+		{name: "linear", mode: "linear", before: before, after: after, wantPrice: "110", wantSources: []time.Time{before.Timestamp, after.Timestamp}},
+		{name: "linear on an exact match", mode: "linear", before: before, after: before, wantPrice: "100", wantSources: []time.Time{before.Timestamp}},
+		{name: "linear needs both neighbours", mode: "linear", before: before, wantError: "price not found"},
+		{name: "previous without one", mode: "previous", after: after, wantError: "price not found"},
+		{name: "within max_gap", mode: "previous", maxGap: "10m", before: before, after: after, wantPrice: "100", wantSources: []time.Time{before.Timestamp}},
+		{name: "beyond max_gap", mode: "next", maxGap: "10m", before: before, after: after, wantError: "price not found within 10m0s"},
+		{name: "linear neighbours beyond max_gap", mode: "linear", maxGap: "20m", before: before, after: after, wantError: "price not found within 20m0s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCurrencyRepo := new(MockCurrencyRepository)
+			mockPriceRepo := new(MockPriceRepository)
+			mockCurrencyRepo.On("GetBySymbol", mock.Anything, "BTC").Return(currency, nil)
+			mockPriceRepo.On("GetSurroundingPrices", mock.Anything, uint(1), timestamp).Return(tt.before, tt.after, nil)
+
+			service := NewCurrencyService(mockCurrencyRepo, mockPriceRepo, fakeUnitOfWork{Currencies: mockCurrencyRepo, Prices: mockPriceRepo, Audit: acceptAudit()}, nil, 3, logger)
+			price, err := service.GetPrice(context.Background(), &dto.GetPriceRequest{Coin: "BTC", Timestamp: timestamp.Unix(), Mode: tt.mode, MaxGap: tt.maxGap})
+
+			if tt.wantError != "" {
+				assert.EqualError(t, err, tt.wantError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.mode, price.Mode)
+			assert.Equal(t, tt.wantPrice, price.Price.String())
+			assert.Equal(t, tt.wantSources, price.SourceTimestamps)
+		})
+	}
+}
+
+func TestCurrencyService_GetPriceInvalidRequest(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	service := NewCurrencyService(new(MockCurrencyRepository), new(MockPriceRepository), fakeUnitOfWork{}, nil, 3, logger)
+
+	_, err := service.GetPrice(context.Background(), &dto.GetPriceRequest{Coin: "BTC", Timestamp: 1700000000, Mode: "cubic"})
+	assert.ErrorIs(t, err, ErrInvalidPriceRequest)
+
+	_, err = service.GetPrice(context.Background(), &dto.GetPriceRequest{Coin: "BTC", Timestamp: 1700000000, MaxGap: "-1h"})
+	assert.ErrorIs(t, err, ErrInvalidPriceRequest)
+}
+
 func TestCurrencyService_RemoveCurrency(t *testing.T) {
 	logger, _ := zap.NewDevelopment()
 
@@ -162,10 +404,24 @@ func TestCurrencyService_RemoveCurrency(t *testing.T) {
 					ID:       1,
 					Symbol:   "bitcoin",
 					Interval: 60,
-					IsActive: true,
+					Status:   models.CurrencyStatusActive,
 				}
 				mockRepo.On("GetBySymbol", mock.Anything, "bitcoin").Return(existingCurrency, nil)
-				mockRepo.On("Deactivate", mock.Anything, "bitcoin").Return(nil)
+				mockRepo.On("Transition", mock.Anything, mock.MatchedBy(func(transition *models.CurrencyTransition) bool {
+					return transition.CurrencyID == 1 &&
+						transition.FromStatus == models.CurrencyStatusActive &&
+						transition.ToStatus == models.CurrencyStatusPaused &&
+						transition.Actor == models.AnonymousActor
+				})).Return(nil)
+			},
+		},
+		{
+			name: "already paused",
+			req: &dto.RemoveCurrencyRequest{
+				Symbol: "bitcoin",
+			},
+			setupMocks: func(mockRepo *MockCurrencyRepository) {
+				mockRepo.On("GetBySymbol", mock.Anything, "bitcoin").Return(&models.Currency{ID: 1, Symbol: "bitcoin", Status: models.CurrencyStatusPaused}, nil)
 			},
 		},
 		{
@@ -174,7 +430,7 @@ func TestCurrencyService_RemoveCurrency(t *testing.T) {
 				Symbol: "nonexistent",
 			},
 			setupMocks: func(mockRepo *MockCurrencyRepository) {
-				mockRepo.On("GetBySymbol", mock.Anything, "nonexistent").Return(nil, errors.New("not found"))
+				mockRepo.On("GetBySymbol", mock.Anything, "nonexistent").Return(nil, repository.ErrNotFound)
 			},
 			expectedError: "currency not found",
 		},
@@ -189,7 +445,7 @@ func TestCurrencyService_RemoveCurrency(t *testing.T) {
 				tt.setupMocks(mockCurrencyRepo)
 			}
 
-			service := NewCurrencyService(mockCurrencyRepo, mockPriceRepo, logger)
+			service := NewCurrencyService(mockCurrencyRepo, mockPriceRepo, fakeUnitOfWork{Currencies: mockCurrencyRepo, Prices: mockPriceRepo, Audit: acceptAudit()}, nil, 3, logger)
 			err := service.RemoveCurrency(context.Background(), tt.req)
 
 			if tt.expectedError != "" {
@@ -203,3 +459,207 @@ func TestCurrencyService_RemoveCurrency(t *testing.T) {
 		})
 	}
 }
+
+func TestCurrencyService_UpdateCurrency(t *testing.T) {
+	interval, apiID := 120, "bitcoin-v2"
+	active, inactive := true, false
+	dbErr := errors.New("database error")
+
+	tests := []struct {
+		name          string
+		status        string
+		req           *dto.UpdateCurrencyRequest
+		setupMocks    func(*MockCurrencyRepository, *MockAuditRepository)
+		wantStatus    string
+		wantInterval  int
+		expectedError error
+	}{
+		{
+			name:   "settings and pause",
+			status: models.CurrencyStatusActive,
+			req:    &dto.UpdateCurrencyRequest{Interval: &interval, ApiID: &apiID, Active: &inactive},
+			setupMocks: func(mockRepo *MockCurrencyRepository, auditRepo *MockAuditRepository) {
+				mockRepo.On("Transition", mock.Anything, matchTransition(models.CurrencyStatusActive, models.CurrencyStatusPaused)).Return(nil)
+				mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(currency *models.Currency) bool {
+					return currency.Interval == 120 && currency.ApiID == "bitcoin-v2" && currency.Status == models.CurrencyStatusPaused
+				})).Return(nil)
+				auditRepo.On("Create", mock.Anything, matchAudit(models.AuditActionCurrencyStatus, "BTC")).Return(nil).Once()
+				auditRepo.On("Create", mock.Anything, matchAudit(models.AuditActionCurrencyUpdate, "BTC")).Return(nil).Once()
+			},
+			wantStatus:   models.CurrencyStatusPaused,
+			wantInterval: 120,
+		},
+		{
+			name:   "reactivate",
+			status: models.CurrencyStatusArchived,
+			req:    &dto.UpdateCurrencyRequest{Active: &active},
+			setupMocks: func(mockRepo *MockCurrencyRepository, auditRepo *MockAuditRepository) {
+				mockRepo.On("Transition", mock.Anything, matchTransition(models.CurrencyStatusArchived, models.CurrencyStatusActive)).Return(nil)
+				auditRepo.On("Create", mock.Anything, matchAudit(models.AuditActionCurrencyStatus, "BTC")).Return(nil).Once()
+			},
+			wantStatus:   models.CurrencyStatusActive,
+			wantInterval: 60,
+		},
+		{
+			name:         "unchanged",
+			status:       models.CurrencyStatusActive,
+			req:          &dto.UpdateCurrencyRequest{Active: &active},
+			wantStatus:   models.CurrencyStatusActive,
+			wantInterval: 60,
+		},
+		{
+			name:   "failed update",
+			status: models.CurrencyStatusActive,
+			req:    &dto.UpdateCurrencyRequest{Interval: &interval},
+			setupMocks: func(mockRepo *MockCurrencyRepository, auditRepo *MockAuditRepository) {
+				mockRepo.On("Update", mock.Anything, mock.Anything).Return(dbErr)
+			},
+			expectedError: dbErr,
+		},
+		{
+			name:   "concurrent transition",
+			status: models.CurrencyStatusActive,
+			req:    &dto.UpdateCurrencyRequest{Interval: &interval},
+			setupMocks: func(mockRepo *MockCurrencyRepository, auditRepo *MockAuditRepository) {
+				mockRepo.On("Update", mock.Anything, mock.Anything).Return(repository.ErrConflict)
+			},
+			expectedError: ErrInvalidTransition,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCurrencyRepo := new(MockCurrencyRepository)
+			mockCurrencyRepo.On("GetBySymbol", mock.Anything, "BTC").Return(&models.Currency{ID: 1, Symbol: "BTC", ApiID: "bitcoin", Interval: 60, Status: tt.status}, nil)
+			auditRepo := new(MockAuditRepository)
+			if tt.setupMocks != nil {
+				tt.setupMocks(mockCurrencyRepo, auditRepo)
+			}
+
+			// Every read goes through the unit of work, never the cached
+			// repository the service was built with.
+			service := NewCurrencyService(new(MockCurrencyRepository), new(MockPriceRepository), fakeUnitOfWork{Currencies: mockCurrencyRepo, Audit: auditRepo}, nil, 3, zap.NewNop())
+			tt.req.Symbol = "BTC"
+			currency, err := service.UpdateCurrency(context.Background(), tt.req)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantStatus, currency.Status)
+				assert.Equal(t, tt.wantInterval, currency.Interval)
+			}
+			mockCurrencyRepo.AssertExpectations(t)
+			auditRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCurrencyService_GetStaleCurrencies(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+
+	fresh := models.Currency{ID: 1, Symbol: "BTC", Interval: 60, Status: models.CurrencyStatusActive}
+	stale := models.Currency{ID: 2, Symbol: "ETH", Interval: 60, Status: models.CurrencyStatusActive}
+	custom := models.Currency{ID: 3, Symbol: "USDT", Interval: 60, StaleAfter: 3600, Status: models.CurrencyStatusActive}
+	empty := models.Currency{ID: 4, Symbol: "DOGE", Interval: 60, Status: models.CurrencyStatusActive}
+
+	mockCurrencyRepo := new(MockCurrencyRepository)
+	mockPriceRepo := new(MockPriceRepository)
+
+	now := time.Now()
+	mockCurrencyRepo.On("GetAllActive", mock.Anything).Return([]models.Currency{fresh, stale, custom, empty}, nil)
+	mockPriceRepo.On("GetLatestPrices", mock.Anything, []uint{1, 2, 3, 4}).Return([]models.Price{
+		{CurrencyID: 1, Timestamp: now.Add(-time.Minute)},
+		{CurrencyID: 2, Timestamp: now.Add(-10 * time.Minute)},
+		{CurrencyID: 3, Timestamp: now.Add(-10 * time.Minute)},
+	}, nil)
+
+	service := NewCurrencyService(mockCurrencyRepo, mockPriceRepo, fakeUnitOfWork{Currencies: mockCurrencyRepo, Prices: mockPriceRepo, Audit: acceptAudit()}, nil, 3, logger)
+	result, err := service.GetStaleCurrencies(context.Background())
+
+	assert.NoError(t, err)
+	if assert.Len(t, result, 2) {
+		assert.Equal(t, "ETH", result[0].Symbol)
+		assert.Equal(t, int64(180), result[0].StaleAfterSeconds)
+		assert.NotNil(t, result[0].AgeSeconds)
+		assert.Equal(t, "DOGE", result[1].Symbol)
+		assert.Nil(t, result[1].LatestPriceAt)
+	}
+
+	mockCurrencyRepo.AssertExpectations(t)
+	mockPriceRepo.AssertExpectations(t)
+}
+
+func TestCurrencyService_GetCandles(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+
+	currency := &models.Currency{ID: 1, Symbol: "BTC", Precision: 2}
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(4 * time.Hour)
+	candles := []models.Candle{
+		{Bucket: from.Add(time.Hour), Open: decimal.RequireFromString("100"), High: decimal.RequireFromString("110.123"), Low: decimal.RequireFromString("95"), Close: decimal.RequireFromString("105"), SampleCount: 60},
+		{Bucket: from.Add(3 * time.Hour), Open: decimal.RequireFromString("106"), High: decimal.RequireFromString("108"), Low: decimal.RequireFromString("104"), Close: decimal.RequireFromString("107"), SampleCount: 60},
+	}
+
+	t.Run("without fill", func(t *testing.T) {
+		mockCurrencyRepo := new(MockCurrencyRepository)
+		mockPriceRepo := new(MockPriceRepository)
+		mockCurrencyRepo.On("GetBySymbol", mock.Anything, "BTC").Return(currency, nil)
+		mockPriceRepo.On("GetCandles", mock.Anything, uint(1), from, to, time.Hour).Return(candles, nil)
+
+		service := NewCurrencyService(mockCurrencyRepo, mockPriceRepo, fakeUnitOfWork{Currencies: mockCurrencyRepo, Prices: mockPriceRepo, Audit: acceptAudit()}, nil, 3, logger)
+		result, err := service.GetCandles(context.Background(), &dto.GetCandlesRequest{
+			Symbol:   "BTC",
+			Interval: "1h",
+			From:     from.Unix(),
+			To:       to.Unix(),
+		})
+
+		assert.NoError(t, err)
+		if assert.Len(t, result.Candles, 2) {
+			assert.Equal(t, "110.12", result.Candles[0].High.String())
+			assert.Equal(t, int64(60), result.Candles[0].SampleCount)
+		}
+		mockPriceRepo.AssertExpectations(t)
+	})
+
+	t.Run("fill forward", func(t *testing.T) {
+		mockCurrencyRepo := new(MockCurrencyRepository)
+		mockPriceRepo := new(MockPriceRepository)
+		mockCurrencyRepo.On("GetBySymbol", mock.Anything, "BTC").Return(currency, nil)
+		mockPriceRepo.On("GetCandles", mock.Anything, uint(1), from, to, time.Hour).Return(candles, nil)
+		mockPriceRepo.On("GetNearestPrice", mock.Anything, uint(1), from).Return(&models.Price{Price: decimal.RequireFromString("99"), Timestamp: from.Add(-time.Minute)}, nil)
+
+		service := NewCurrencyService(mockCurrencyRepo, mockPriceRepo, fakeUnitOfWork{Currencies: mockCurrencyRepo, Prices: mockPriceRepo, Audit: acceptAudit()}, nil, 3, logger)
+		result, err := service.GetCandles(context.Background(), &dto.GetCandlesRequest{
+			Symbol:   "BTC",
+			Interval: "1h",
+			From:     from.Add(20 * time.Minute).Unix(),
+			To:       to.Unix(),
+			Fill:     true,
+		})
+
+		assert.NoError(t, err)
+		if assert.Len(t, result.Candles, 4) {
+			assert.True(t, result.Candles[0].Filled)
+			assert.Equal(t, "99", result.Candles[0].Close.String())
+			assert.False(t, result.Candles[1].Filled)
+			assert.True(t, result.Candles[2].Filled)
+			assert.Equal(t, "105", result.Candles[2].Open.String())
+			assert.Equal(t, int64(0), result.Candles[2].SampleCount)
+		}
+		mockPriceRepo.AssertExpectations(t)
+	})
+
+	t.Run("invalid interval", func(t *testing.T) {
+		service := NewCurrencyService(new(MockCurrencyRepository), new(MockPriceRepository), fakeUnitOfWork{}, nil, 3, logger)
+		_, err := service.GetCandles(context.Background(), &dto.GetCandlesRequest{Symbol: "BTC", Interval: "90s"})
+		assert.ErrorIs(t, err, ErrInvalidCandleRequest)
+	})
+
+	t.Run("too many candles", func(t *testing.T) {
+		service := NewCurrencyService(new(MockCurrencyRepository), new(MockPriceRepository), fakeUnitOfWork{}, nil, 3, logger)
+		_, err := service.GetCandles(context.Background(), &dto.GetCandlesRequest{Symbol: "BTC", Interval: "1m", From: from.Unix(), To: from.AddDate(0, 1, 0).Unix()})
+		assert.ErrorIs(t, err, ErrInvalidCandleRequest)
+	})
+}
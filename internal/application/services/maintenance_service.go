@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"crypto-price-tracker-app/internal/domain/models"
+	"crypto-price-tracker-app/internal/domain/repository"
+
+	"go.uber.org/zap"
+)
+
+// PartitionPolicy controls how far ahead price partitions are created and how
+// long they are kept. RetentionMonths of 0 keeps partitions forever.
+type PartitionPolicy struct {
+	MonthsAhead     int
+	RetentionMonths int
+	DropExpired     bool
+}
+
+// RollupPolicy controls rollup maintenance. Lookback is how far behind the
+// newest bucket rollups are recomputed to absorb late prices. Resolutions
+// without a retention are kept forever.
+type RollupPolicy struct {
+	Lookback  time.Duration
+	Retention map[models.Resolution]time.Duration
+}
+
+type MaintenanceService struct {
+	partitions      repository.PartitionManager
+	rollups         repository.RollupRepository
+	partitionPolicy PartitionPolicy
+	rollupPolicy    RollupPolicy
+	logger          *zap.Logger
+}
+
+func NewMaintenanceService(partitions repository.PartitionManager, rollups repository.RollupRepository, partitionPolicy PartitionPolicy, rollupPolicy RollupPolicy, logger *zap.Logger) *MaintenanceService {
+	return &MaintenanceService{
+		partitions:      partitions,
+		rollups:         rollups,
+		partitionPolicy: partitionPolicy,
+		rollupPolicy:    rollupPolicy,
+		logger:          logger,
+	}
+}
+
+func (s *MaintenanceService) MaintainPartitions(ctx context.Context, now time.Time) error {
+	created, err := s.partitions.EnsurePartitions(ctx, now, s.partitionPolicy.MonthsAhead)
+	if err != nil {
+		s.logger.Error("Failed to create price partitions", zap.Error(err))
+		return err
+	}
+	s.logger.Debug("Price partitions ensured", zap.Strings("partitions", created))
+
+	if s.partitionPolicy.RetentionMonths <= 0 {
+		return nil
+	}
+
+	month := time.Date(now.UTC().Year(), now.UTC().Month(), 1, 0, 0, 0, 0, time.UTC)
+	cutoff := month.AddDate(0, -s.partitionPolicy.RetentionMonths, 0)
+	expired, err := s.partitions.ExpirePartitions(ctx, cutoff, s.partitionPolicy.DropExpired)
+	if err != nil {
+		s.logger.Error("Failed to expire price partitions", zap.Time("cutoff", cutoff), zap.Error(err))
+		return err
+	}
+	if len(expired) > 0 {
+		s.logger.Info("Expired price partitions",
+			zap.Strings("partitions", expired),
+			zap.Time("cutoff", cutoff),
+			zap.Bool("dropped", s.partitionPolicy.DropExpired),
+		)
+	}
+	return nil
+}
+
+// MaintainRollups refreshes every rollup from finest to coarsest, then prunes
+// buckets past their retention.
+func (s *MaintenanceService) MaintainRollups(ctx context.Context, now time.Time) error {
+	for _, resolution := range models.RollupResolutions {
+		rows, err := s.rollups.Refresh(ctx, resolution, s.rollupPolicy.Lookback)
+		if err != nil {
+			s.logger.Error("Failed to refresh price rollup", zap.String("resolution", string(resolution)), zap.Error(err))
+			return err
+		}
+		s.logger.Debug("Price rollup refreshed", zap.String("resolution", string(resolution)), zap.Int64("buckets", rows))
+	}
+
+	for _, resolution := range models.RollupResolutions {
+		retention := s.rollupPolicy.Retention[resolution]
+		if retention <= 0 {
+			continue
+		}
+
+		cutoff := now.Add(-retention)
+		rows, err := s.rollups.Prune(ctx, resolution, cutoff)
+		if err != nil {
+			s.logger.Error("Failed to prune price rollup", zap.String("resolution", string(resolution)), zap.Error(err))
+			return err
+		}
+		if rows > 0 {
+			s.logger.Info("Pruned price rollup", zap.String("resolution", string(resolution)), zap.Time("cutoff", cutoff), zap.Int64("buckets", rows))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"crypto-price-tracker-app/internal/domain/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type MockPartitionManager struct {
+	mock.Mock
+}
+
+func (m *MockPartitionManager) EnsurePartitions(ctx context.Context, from time.Time, monthsAhead int) ([]string, error) {
+	args := m.Called(ctx, from, monthsAhead)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockPartitionManager) ExpirePartitions(ctx context.Context, cutoff time.Time, drop bool) ([]string, error) {
+	args := m.Called(ctx, cutoff, drop)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+type MockRollupRepository struct {
+	mock.Mock
+}
+
+func (m *MockRollupRepository) Refresh(ctx context.Context, resolution models.Resolution, lookback time.Duration) (int64, error) {
+	args := m.Called(ctx, resolution, lookback)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRollupRepository) Prune(ctx context.Context, resolution models.Resolution, before time.Time) (int64, error) {
+	args := m.Called(ctx, resolution, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestMaintenanceService_MaintainPartitions(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	now := time.Date(2024, 5, 17, 10, 0, 0, 0, time.UTC)
+
+	t.Run("keeps partitions without retention", func(t *testing.T) {
+		partitions := new(MockPartitionManager)
+		partitions.On("EnsurePartitions", mock.Anything, now, 3).Return([]string{"prices_2024_05"}, nil)
+
+		service := NewMaintenanceService(partitions, nil, PartitionPolicy{MonthsAhead: 3}, RollupPolicy{}, logger)
+
+		assert.NoError(t, service.MaintainPartitions(context.Background(), now))
+		partitions.AssertNotCalled(t, "ExpirePartitions", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("expires partitions older than retention", func(t *testing.T) {
+		partitions := new(MockPartitionManager)
+		partitions.On("EnsurePartitions", mock.Anything, now, 2).Return([]string{"prices_2024_05"}, nil)
+		partitions.On("ExpirePartitions", mock.Anything, time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC), true).Return([]string{"prices_2023_10"}, nil)
+
+		service := NewMaintenanceService(partitions, nil, PartitionPolicy{MonthsAhead: 2, RetentionMonths: 6, DropExpired: true}, RollupPolicy{}, logger)
+
+		assert.NoError(t, service.MaintainPartitions(context.Background(), now))
+		partitions.AssertExpectations(t)
+	})
+}
+
+func TestMaintenanceService_MaintainRollups(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	now := time.Date(2024, 5, 17, 10, 0, 0, 0, time.UTC)
+
+	rollups := new(MockRollupRepository)
+	var refreshed []models.Resolution
+	rollups.On("Refresh", mock.Anything, mock.Anything, time.Hour).
+		Run(func(args mock.Arguments) { refreshed = append(refreshed, args.Get(1).(models.Resolution)) }).
+		Return(int64(1), nil)
+	rollups.On("Prune", mock.Anything, models.ResolutionMinute, now.Add(-7*24*time.Hour)).Return(int64(10), nil)
+
+	service := NewMaintenanceService(nil, rollups, PartitionPolicy{}, RollupPolicy{
+		Lookback:  time.Hour,
+		Retention: map[models.Resolution]time.Duration{models.ResolutionMinute: 7 * 24 * time.Hour},
+	}, logger)
+
+	assert.NoError(t, service.MaintainRollups(context.Background(), now))
+	assert.Equal(t, []models.Resolution{models.ResolutionMinute, models.ResolutionHour, models.ResolutionDay}, refreshed)
+	rollups.AssertNumberOfCalls(t, "Prune", 1)
+}
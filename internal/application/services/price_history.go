@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"crypto-price-tracker-app/internal/application/dto"
+	"crypto-price-tracker-app/internal/domain/models"
+	"crypto-price-tracker-app/internal/domain/repository"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultHistoryRange = 24 * time.Hour
+	defaultHistoryLimit = 100
+	maxHistoryLimit     = 1000
+
+	HistoryOrderAsc  = "asc"
+	HistoryOrderDesc = "desc"
+)
+
+var ErrInvalidHistoryRequest = errors.New("invalid history request")
+
+// historyCursor is the opaque position handed to clients: the row a page
+// ended or started at, and whether to read after it (next) or before it
+// (previous) in the requested order.
+type historyCursor struct {
+	Timestamp time.Time `json:"t"`
+	ID        uint      `json:"id"`
+	Backward  bool      `json:"b,omitempty"`
+}
+
+func encodeHistoryCursor(price models.Price, backward bool) *string {
+	data, _ := json.Marshal(historyCursor{Timestamp: price.Timestamp.UTC(), ID: price.ID, Backward: backward})
+	cursor := base64.RawURLEncoding.EncodeToString(data)
+	return &cursor
+}
+
+func decodeHistoryCursor(value string) (*historyCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidHistoryRequest)
+	}
+	var cursor historyCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.Timestamp.IsZero() {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidHistoryRequest)
+	}
+	return &cursor, nil
+}
+
+// GetPriceHistory returns one page of stored prices in [from, to), by default
+// the last day, oldest first. Pages are keyed on (timestamp, id), so they stay
+// consistent while new prices arrive. With step only the first price of each
+// step-long bucket is returned.
+func (s *CurrencyService) GetPriceHistory(ctx context.Context, req *dto.GetPriceHistoryRequest) (*dto.PriceHistoryResponse, error) {
+	query := models.PriceHistoryQuery{Limit: req.Limit}
+	if query.Limit == 0 {
+		query.Limit = defaultHistoryLimit
+	}
+	if query.Limit < 0 || query.Limit > maxHistoryLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidHistoryRequest, maxHistoryLimit)
+	}
+
+	order := req.Order
+	switch order {
+	case "":
+		order = HistoryOrderAsc
+	case HistoryOrderAsc, HistoryOrderDesc:
+	default:
+		return nil, fmt.Errorf("%w: order must be asc or desc", ErrInvalidHistoryRequest)
+	}
+
+	if req.Step != "" {
+		step, err := ParseCandleInterval(req.Step)
+		if err != nil {
+			return nil, fmt.Errorf("%w: step must look like 5m, 1h, 1d or 1w", ErrInvalidHistoryRequest)
+		}
+		query.Step = step
+	}
+
+	query.To = time.Now().UTC()
+	if req.To != 0 {
+		query.To = time.Unix(req.To, 0).UTC()
+	}
+	query.From = query.To.Add(-defaultHistoryRange)
+	if req.From != 0 {
+		query.From = time.Unix(req.From, 0).UTC()
+	}
+	if !query.From.Before(query.To) {
+		return nil, fmt.Errorf("%w: from must be before to", ErrInvalidHistoryRequest)
+	}
+
+	var cursor *historyCursor
+	if req.Cursor != "" {
+		var err error
+		if cursor, err = decodeHistoryCursor(req.Cursor); err != nil {
+			return nil, err
+		}
+		query.After = &models.PriceCursor{Timestamp: cursor.Timestamp, ID: cursor.ID}
+	}
+	backward := cursor != nil && cursor.Backward
+	// A previous page is read in the opposite order and flipped back.
+	query.Descending = (order == HistoryOrderDesc) != backward
+
+	currency, err := s.currencyRepo.GetBySymbol(ctx, req.Symbol)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			s.logger.Warn("Currency not found", zap.String("symbol", req.Symbol))
+			return nil, ErrCurrencyNotFound
+		}
+		s.logger.Error("Failed to get currency", zap.String("symbol", req.Symbol), zap.Error(err))
+		return nil, err
+	}
+
+	// One extra row tells whether there is anything beyond this page.
+	limit := query.Limit
+	query.Limit++
+	prices, err := s.priceRepo.GetPriceHistoryPage(ctx, currency.ID, query)
+	if err != nil {
+		s.logger.Error("Failed to get price history", zap.String("symbol", req.Symbol), zap.Error(err))
+		return nil, err
+	}
+	more := len(prices) > limit
+	if more {
+		prices = prices[:limit]
+	}
+	if backward {
+		slices.Reverse(prices)
+	}
+
+	response := &dto.PriceHistoryResponse{
+		Symbol: currency.Symbol,
+		From:   query.From,
+		To:     query.To,
+		Order:  order,
+		Step:   req.Step,
+		Prices: make([]dto.HistoryPriceResponse, len(prices)),
+	}
+	precision := int32(currency.Precision)
+	for i, price := range prices {
+		response.Prices[i] = dto.HistoryPriceResponse{
+			ID:        price.ID,
+			Price:     price.Price.Round(precision),
+			Timestamp: price.Timestamp.UTC(),
+		}
+	}
+
+	if len(prices) > 0 {
+		// Reading forward, rows beyond the page exist if the extra row came
+		// back, and rows before it if we got here through a cursor; reading
+		// backward it is the other way round.
+		hasNext, hasPrev := more, cursor != nil
+		if backward {
+			hasNext, hasPrev = true, more
+		}
+		if hasNext {
+			response.NextCursor = encodeHistoryCursor(prices[len(prices)-1], false)
+		}
+		if hasPrev {
+			response.PrevCursor = encodeHistoryCursor(prices[0], true)
+		}
+	}
+	return response, nil
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"crypto-price-tracker-app/internal/application/dto"
+	"crypto-price-tracker-app/internal/domain/models"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestCurrencyService_GetPriceHistory(t *testing.T) {
+	from := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+	rows := make([]models.Price, 5)
+	for i := range rows {
+		rows[i] = models.Price{ID: uint(i + 1), CurrencyID: 1, Price: decimal.NewFromInt(int64(100 + i)), Timestamp: from.Add(time.Duration(i) * time.Minute)}
+	}
+	ids := func(history *dto.PriceHistoryResponse) []uint {
+		var result []uint
+		for _, price := range history.Prices {
+			result = append(result, price.ID)
+		}
+		return result
+	}
+	page := func(descending bool, after *models.PriceCursor) interface{} {
+		return mock.MatchedBy(func(query models.PriceHistoryQuery) bool {
+			return query.From.Equal(from) && query.To.Equal(to) && query.Limit == 3 && query.Descending == descending &&
+				(after == nil) == (query.After == nil) && (after == nil || *after == *query.After)
+		})
+	}
+
+	currencyRepo := new(MockCurrencyRepository)
+	priceRepo := new(MockPriceRepository)
+	currencyRepo.On("GetBySymbol", mock.Anything, "BTC").Return(&models.Currency{ID: 1, Symbol: "BTC", Precision: 8}, nil)
+	priceRepo.On("GetPriceHistoryPage", mock.Anything, uint(1), page(false, nil)).Return(rows[:3], nil)
+	priceRepo.On("GetPriceHistoryPage", mock.Anything, uint(1), page(false, &models.PriceCursor{Timestamp: rows[1].Timestamp, ID: 2})).Return(rows[2:5], nil)
+	// The previous page of [3 4] is read backwards from 3.
+	priceRepo.On("GetPriceHistoryPage", mock.Anything, uint(1), page(true, &models.PriceCursor{Timestamp: rows[2].Timestamp, ID: 3})).Return([]models.Price{rows[1], rows[0]}, nil)
+
+	service := NewCurrencyService(currencyRepo, priceRepo, fakeUnitOfWork{}, nil, 3, zap.NewNop())
+	req := &dto.GetPriceHistoryRequest{Symbol: "BTC", From: from.Unix(), To: to.Unix(), Limit: 2}
+
+	first, err := service.GetPriceHistory(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{1, 2}, ids(first))
+	assert.Nil(t, first.PrevCursor)
+	require.NotNil(t, first.NextCursor)
+
+	req.Cursor = *first.NextCursor
+	second, err := service.GetPriceHistory(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{3, 4}, ids(second))
+	require.NotNil(t, second.NextCursor)
+	require.NotNil(t, second.PrevCursor)
+
+	req.Cursor = *second.PrevCursor
+	back, err := service.GetPriceHistory(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{1, 2}, ids(back))
+	assert.Nil(t, back.PrevCursor)
+	assert.Equal(t, first.NextCursor, back.NextCursor)
+
+	priceRepo.AssertExpectations(t)
+}
+
+func TestCurrencyService_GetPriceHistoryInvalidRequest(t *testing.T) {
+	service := NewCurrencyService(new(MockCurrencyRepository), new(MockPriceRepository), fakeUnitOfWork{}, nil, 3, zap.NewNop())
+
+	for name, req := range map[string]*dto.GetPriceHistoryRequest{
+		"limit":  {Symbol: "BTC", Limit: 5000},
+		"order":  {Symbol: "BTC", Order: "random"},
+		"step":   {Symbol: "BTC", Step: "90s"},
+		"range":  {Symbol: "BTC", From: 200, To: 100},
+		"cursor": {Symbol: "BTC", Cursor: "not a cursor"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := service.GetPriceHistory(context.Background(), req)
+			assert.ErrorIs(t, err, ErrInvalidHistoryRequest)
+		})
+	}
+}
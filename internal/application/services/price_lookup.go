@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"crypto-price-tracker-app/internal/application/dto"
+	"crypto-price-tracker-app/internal/domain/models"
+
+	"go.uber.org/zap"
+)
+
+const maxLookupItems = 1000
+
+// LookupPrices answers many point-in-time lookups at once, each like GetPrice
+// would. The currencies and the prices around every moment are read with one
+// repository call each, so the cost does not grow with round trips. A lookup
+// that cannot be answered gets an error in its result instead of failing the
+// others.
+func (s *CurrencyService) LookupPrices(ctx context.Context, req *dto.LookupPricesRequest) (*dto.LookupPricesResponse, error) {
+	mode, maxGap, err := parseLookupOptions(req.Mode, req.MaxGap)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Items) == 0 || len(req.Items) > maxLookupItems {
+		return nil, fmt.Errorf("%w: between 1 and %d items are allowed", ErrInvalidPriceRequest, maxLookupItems)
+	}
+
+	seen := make(map[string]bool)
+	var symbols []string
+	for _, item := range req.Items {
+		if !seen[item.Coin] {
+			seen[item.Coin] = true
+			symbols = append(symbols, item.Coin)
+		}
+	}
+	currencies, err := s.currencyRepo.GetBySymbols(ctx, symbols)
+	if err != nil {
+		s.logger.Error("Failed to get currencies", zap.Int("symbols", len(symbols)), zap.Error(err))
+		return nil, err
+	}
+	bySymbol := make(map[string]*models.Currency, len(currencies))
+	for i := range currencies {
+		bySymbol[currencies[i].Symbol] = &currencies[i]
+	}
+
+	// keys holds only the items whose currency exists.
+	keys := make([]models.PriceKey, 0, len(req.Items))
+	for _, item := range req.Items {
+		if currency, ok := bySymbol[item.Coin]; ok {
+			keys = append(keys, models.PriceKey{CurrencyID: currency.ID, Timestamp: time.Unix(item.Timestamp, 0)})
+		}
+	}
+	surrounding, err := s.priceRepo.GetSurroundingPricesBatch(ctx, keys)
+	if err != nil {
+		s.logger.Error("Failed to look up prices", zap.Int("keys", len(keys)), zap.Error(err))
+		return nil, err
+	}
+
+	results := make([]dto.PriceLookupResult, len(req.Items))
+	next := 0
+	for i, item := range req.Items {
+		results[i] = dto.PriceLookupResult{Coin: item.Coin, Timestamp: item.Timestamp}
+		currency, ok := bySymbol[item.Coin]
+		if !ok {
+			results[i].Error = ErrCurrencyNotFound.Error()
+			continue
+		}
+		prices := surrounding[next]
+		next++
+
+		timestamp := time.Unix(item.Timestamp, 0)
+		result := pickPrice(currency, timestamp, mode, prices.Before, prices.After)
+		switch {
+		case result == nil:
+			results[i].Error = "price not found"
+		case maxGap > 0 && result.gap > maxGap:
+			results[i].Error = fmt.Sprintf("price not found within %s", maxGap)
+		default:
+			results[i].Price = s.toPriceResponse(currency, timestamp, result)
+		}
+	}
+
+	s.logger.Debug("Prices looked up", zap.Int("items", len(req.Items)), zap.Int("currencies", len(currencies)))
+	return &dto.LookupPricesResponse{Results: results}, nil
+}
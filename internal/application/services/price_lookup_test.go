@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"crypto-price-tracker-app/internal/application/dto"
+	"crypto-price-tracker-app/internal/domain/models"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestCurrencyService_LookupPrices(t *testing.T) {
+	base := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	btc := models.Currency{ID: 1, Symbol: "BTC", Interval: 60, Precision: 2}
+	eth := models.Currency{ID: 2, Symbol: "ETH", Interval: 60, Precision: 2}
+	before := &models.Price{ID: 10, CurrencyID: 1, Price: decimal.NewFromInt(100), Timestamp: base}
+	after := &models.Price{ID: 11, CurrencyID: 1, Price: decimal.NewFromInt(110), Timestamp: base.Add(10 * time.Minute)}
+
+	currencyRepo := new(MockCurrencyRepository)
+	priceRepo := new(MockPriceRepository)
+	currencyRepo.On("GetBySymbols", mock.Anything, []string{"BTC", "DOGE", "ETH"}).Return([]models.Currency{eth, btc}, nil)
+	// DOGE is unknown, so only the other items reach the price repository.
+	priceRepo.On("GetSurroundingPricesBatch", mock.Anything, []models.PriceKey{
+		{CurrencyID: 1, Timestamp: time.Unix(base.Add(5*time.Minute).Unix(), 0)},
+		{CurrencyID: 2, Timestamp: time.Unix(base.Unix(), 0)},
+		{CurrencyID: 1, Timestamp: time.Unix(base.Add(-time.Minute).Unix(), 0)},
+	}).Return([]models.SurroundingPrices{
+		{Before: before, After: after},
+		{},
+		{After: before},
+	}, nil)
+
+	service := NewCurrencyService(currencyRepo, priceRepo, fakeUnitOfWork{}, nil, 3, zap.NewNop())
+	response, err := service.LookupPrices(context.Background(), &dto.LookupPricesRequest{
+		Items: []dto.PriceLookupItem{
+			{Coin: "BTC", Timestamp: base.Add(5 * time.Minute).Unix()},
+			{Coin: "DOGE", Timestamp: base.Unix()},
+			{Coin: "ETH", Timestamp: base.Unix()},
+			{Coin: "BTC", Timestamp: base.Add(-time.Minute).Unix()},
+		},
+		Mode: string(models.PriceLookupLinear),
+	})
+	require.NoError(t, err)
+	require.Len(t, response.Results, 4)
+
+	first := response.Results[0]
+	assert.Equal(t, "BTC", first.Coin)
+	assert.Empty(t, first.Error)
+	if assert.NotNil(t, first.Price) {
+		assert.Equal(t, "105", first.Price.Price.String())
+		assert.Equal(t, []time.Time{before.Timestamp, after.Timestamp}, first.Price.SourceTimestamps)
+	}
+	assert.Equal(t, ErrCurrencyNotFound.Error(), response.Results[1].Error)
+	assert.Nil(t, response.Results[1].Price)
+	assert.Equal(t, "price not found", response.Results[2].Error)
+	// Linear needs a price on both sides.
+	assert.Equal(t, "price not found", response.Results[3].Error)
+
+	currencyRepo.AssertExpectations(t)
+	priceRepo.AssertExpectations(t)
+}
+
+func TestCurrencyService_LookupPricesMaxGap(t *testing.T) {
+	base := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	price := &models.Price{ID: 10, CurrencyID: 1, Price: decimal.NewFromInt(100), Timestamp: base}
+
+	currencyRepo := new(MockCurrencyRepository)
+	priceRepo := new(MockPriceRepository)
+	currencyRepo.On("GetBySymbols", mock.Anything, []string{"BTC"}).Return([]models.Currency{{ID: 1, Symbol: "BTC", Interval: 60, Precision: 2}}, nil)
+	priceRepo.On("GetSurroundingPricesBatch", mock.Anything, mock.Anything).Return([]models.SurroundingPrices{
+		{Before: price},
+		{Before: price},
+	}, nil)
+
+	service := NewCurrencyService(currencyRepo, priceRepo, fakeUnitOfWork{}, nil, 3, zap.NewNop())
+	response, err := service.LookupPrices(context.Background(), &dto.LookupPricesRequest{
+		Items: []dto.PriceLookupItem{
+			{Coin: "BTC", Timestamp: base.Add(30 * time.Second).Unix()},
+			{Coin: "BTC", Timestamp: base.Add(2 * time.Hour).Unix()},
+		},
+		MaxGap: "1h",
+	})
+	require.NoError(t, err)
+	require.Len(t, response.Results, 2)
+	if assert.NotNil(t, response.Results[0].Price) {
+		assert.Equal(t, string(models.PriceLookupPrevious), response.Results[0].Price.Mode)
+	}
+	assert.Equal(t, "price not found within 1h0m0s", response.Results[1].Error)
+}
+
+func TestCurrencyService_LookupPricesInvalidRequest(t *testing.T) {
+	service := NewCurrencyService(new(MockCurrencyRepository), new(MockPriceRepository), fakeUnitOfWork{}, nil, 3, zap.NewNop())
+
+	for name, req := range map[string]*dto.LookupPricesRequest{
+		"no items":     {},
+		"unknown mode": {Items: []dto.PriceLookupItem{{Coin: "BTC", Timestamp: 1}}, Mode: "cubic"},
+		"bad max_gap":  {Items: []dto.PriceLookupItem{{Coin: "BTC", Timestamp: 1}}, MaxGap: "-1h"},
+		"too many":     {Items: make([]dto.PriceLookupItem, maxLookupItems+1)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := service.LookupPrices(context.Background(), req)
+			assert.ErrorIs(t, err, ErrInvalidPriceRequest)
+		})
+	}
+}
@@ -2,27 +2,38 @@ package services
 
 import (
 	"context"
+	"errors"
+	"slices"
+	"strings"
 	"time"
 
+	"crypto-price-tracker-app/internal/application/dto"
 	"crypto-price-tracker-app/internal/domain/models"
 	"crypto-price-tracker-app/internal/domain/repository"
 
+	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
 )
 
 type PriceService struct {
-	priceRepo    repository.PriceRepository
-	currencyRepo repository.CurrencyRepository
-	priceAPI     repository.PriceAPI
-	logger       *zap.Logger
+	priceRepo       repository.PriceRepository
+	currencyRepo    repository.CurrencyRepository
+	quarantineRepo  repository.QuarantineRepository
+	priceAPI        repository.PriceAPI
+	validator       *PriceValidator
+	staleMultiplier int
+	logger          *zap.Logger
 }
 
-func NewPriceService(priceRepo repository.PriceRepository, currencyRepo repository.CurrencyRepository, priceAPI repository.PriceAPI, logger *zap.Logger) *PriceService {
+func NewPriceService(priceRepo repository.PriceRepository, currencyRepo repository.CurrencyRepository, quarantineRepo repository.QuarantineRepository, priceAPI repository.PriceAPI, validator *PriceValidator, staleMultiplier int, logger *zap.Logger) *PriceService {
 	return &PriceService{
-		priceRepo:    priceRepo,
-		currencyRepo: currencyRepo,
-		priceAPI:     priceAPI,
-		logger:       logger,
+		priceRepo:       priceRepo,
+		currencyRepo:    currencyRepo,
+		quarantineRepo:  quarantineRepo,
+		priceAPI:        priceAPI,
+		validator:       validator,
+		staleMultiplier: staleMultiplier,
+		logger:          logger,
 	}
 }
 
@@ -34,8 +45,8 @@ func (s *PriceService) UpdatePrices(ctx context.Context) error {
 	}
 
 	s.logger.Info("Starting price update for currencies", zap.Int("count", len(currencies)))
-	for _, currencyInterface := range currencies {
-		currency := currencyInterface.(*models.Currency)
+	for i := range currencies {
+		currency := &currencies[i]
 		if err := s.updateCurrencyPrice(ctx, currency); err != nil {
 			s.logger.Error("Failed to update price for currency", zap.String("symbol", currency.Symbol), zap.Error(err))
 		} else {
@@ -48,46 +59,161 @@ func (s *PriceService) UpdatePrices(ctx context.Context) error {
 }
 
 func (s *PriceService) updateCurrencyPrice(ctx context.Context, currency *models.Currency) error {
-	price, err := s.priceAPI.GetPrice(ctx, currency.ApiID)
+	quote, err := s.priceAPI.GetQuote(ctx, currency.ApiID)
 	if err != nil {
 		s.logger.Error("Failed to get price from API", zap.String("symbol", currency.Symbol), zap.String("api_id", currency.ApiID), zap.Error(err))
 		return err
 	}
 
+	now := time.Now()
+
+	if s.validator != nil {
+		last, err := s.priceRepo.GetLatestPrice(ctx, currency.ID)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			s.logger.Error("Failed to get last stored price", zap.String("symbol", currency.Symbol), zap.Error(err))
+			return err
+		}
+
+		if violation := s.validator.Validate(currency.Symbol, quote, last, now); violation != nil {
+			return s.quarantinePrice(ctx, currency, quote, last, violation, now)
+		}
+	}
+
 	priceModel := &models.Price{
 		CurrencyID: currency.ID,
-		Price:      price,
-		Timestamp:  time.Now(),
+		Price:      quote.Price.Round(int32(currency.Precision)),
+		Timestamp:  now,
 	}
 
 	if err := s.priceRepo.Create(ctx, priceModel); err != nil {
-		s.logger.Error("Failed to save price to database", zap.String("symbol", currency.Symbol), zap.Float64("price", price), zap.Error(err))
+		s.logger.Error("Failed to save price to database", zap.String("symbol", currency.Symbol), zap.Stringer("price", priceModel.Price), zap.Error(err))
 		return err
 	}
 
-	s.logger.Debug("Price saved successfully", zap.String("symbol", currency.Symbol), zap.Float64("price", price))
+	s.logger.Debug("Price saved successfully", zap.String("symbol", currency.Symbol), zap.Stringer("price", priceModel.Price))
 	return nil
 }
 
-func (s *PriceService) GetLatestPrices(ctx context.Context) ([]models.Price, error) {
+func (s *PriceService) quarantinePrice(ctx context.Context, currency *models.Currency, quote *models.Quote, last *models.Price, violation *Violation, now time.Time) error {
+	sample := &models.QuarantinedPrice{
+		CurrencyID: currency.ID,
+		Price:      quote.Price,
+		Timestamp:  now,
+		Rule:       violation.Rule,
+		Reason:     violation.Reason,
+		Status:     models.QuarantineStatusPending,
+	}
+	if last != nil {
+		referencePrice := last.Price
+		sample.ReferencePrice = &referencePrice
+	}
+
+	if err := s.quarantineRepo.Create(ctx, sample); err != nil {
+		s.logger.Error("Failed to quarantine price", zap.String("symbol", currency.Symbol), zap.Stringer("price", quote.Price), zap.Error(err))
+		return err
+	}
+
+	s.logger.Warn("Price rejected by sanity filter",
+		zap.String("symbol", currency.Symbol),
+		zap.Stringer("price", quote.Price),
+		zap.String("rule", violation.Rule),
+		zap.String("reason", violation.Reason),
+		zap.Uint("quarantine_id", sample.ID),
+	)
+	return nil
+}
+
+// changeWindow is the period GetLatestPrices reports price changes over.
+const changeWindow = 24 * time.Hour
+
+// GetLatestPrices returns the latest price of every active currency, or of
+// those among symbols when any are given, ordered by symbol. Symbols match
+// regardless of case and surrounding spaces. The change compares the latest
+// price with the last one stored at least changeWindow before it, so a
+// currency whose updates stopped still shows how it moved until then. That
+// price must be no older than the currency's stale threshold beyond the
+// window; otherwise, as when the stored history is shorter, the change is
+// left out. Currencies without any price are listed without one.
+func (s *PriceService) GetLatestPrices(ctx context.Context, symbols []string) ([]dto.LatestPriceResponse, error) {
 	currencies, err := s.currencyRepo.GetAllActive(ctx)
 	if err != nil {
 		s.logger.Error("Failed to get active currencies for latest prices", zap.Error(err))
 		return nil, err
 	}
+	if len(symbols) > 0 {
+		wanted := make(map[string]bool, len(symbols))
+		for _, symbol := range symbols {
+			wanted[strings.ToUpper(strings.TrimSpace(symbol))] = true
+		}
+		currencies = slices.DeleteFunc(currencies, func(currency models.Currency) bool {
+			return !wanted[strings.ToUpper(currency.Symbol)]
+		})
+	}
+	slices.SortFunc(currencies, func(a, b models.Currency) int {
+		return strings.Compare(a.Symbol, b.Symbol)
+	})
+
+	ids := make([]uint, len(currencies))
+	for i, currency := range currencies {
+		ids[i] = currency.ID
+	}
+
+	prices, err := s.priceRepo.GetLatestPrices(ctx, ids)
+	if err != nil {
+		s.logger.Error("Failed to get latest prices", zap.Error(err))
+		return nil, err
+	}
+	latest := make(map[uint]*models.Price, len(prices))
+	keys := make([]models.PriceKey, len(prices))
+	for i := range prices {
+		latest[prices[i].CurrencyID] = &prices[i]
+		keys[i] = models.PriceKey{CurrencyID: prices[i].CurrencyID, Timestamp: prices[i].Timestamp.Add(-changeWindow)}
+	}
 
-	var prices []models.Price
-	for _, currencyInterface := range currencies {
-		currency := currencyInterface.(*models.Currency)
-		priceInterface, err := s.priceRepo.GetLatestPrice(ctx, currency.ID)
-		if err != nil || priceInterface == nil {
-			s.logger.Debug("No latest price found for currency", zap.String("symbol", currency.Symbol))
+	surrounding, err := s.priceRepo.GetSurroundingPricesBatch(ctx, keys)
+	if err != nil {
+		s.logger.Error("Failed to get prices to compare with", zap.Error(err))
+		return nil, err
+	}
+	previous := make(map[uint]*models.Price, len(surrounding))
+	for i, around := range surrounding {
+		previous[keys[i].CurrencyID] = around.Before
+	}
+
+	now := time.Now()
+	responses := make([]dto.LatestPriceResponse, len(currencies))
+	for i := range currencies {
+		currency := &currencies[i]
+		responses[i].Symbol = currency.Symbol
+		price := latest[currency.ID]
+		if price == nil {
 			continue
 		}
-		price := priceInterface.(*models.Price)
-		prices = append(prices, *price)
+
+		precision := int32(currency.Precision)
+		value := price.Price.Round(precision)
+		age := int64(now.Sub(price.Timestamp).Seconds())
+		responses[i].Price = &value
+		responses[i].Timestamp = &price.Timestamp
+		responses[i].AgeSeconds = &age
+
+		reference := previous[currency.ID]
+		if reference == nil {
+			continue
+		}
+		if gap := price.Timestamp.Add(-changeWindow).Sub(reference.Timestamp); gap > currency.StaleThreshold(s.staleMultiplier) {
+			s.logger.Debug("No price close enough to compare with", zap.String("symbol", currency.Symbol), zap.Duration("gap", gap))
+			continue
+		}
+		change := price.Price.Sub(reference.Price)
+		rounded := change.Round(precision)
+		responses[i].Change24h = &rounded
+		if !reference.Price.IsZero() {
+			percent := change.Div(reference.Price).Mul(decimal.NewFromInt(100)).Round(2)
+			responses[i].ChangePercent24h = &percent
+		}
 	}
 
-	s.logger.Debug("Retrieved latest prices", zap.Int("count", len(prices)))
-	return prices, nil
+	s.logger.Debug("Retrieved latest prices", zap.Int("currencies", len(responses)), zap.Int("prices", len(prices)))
+	return responses, nil
 }
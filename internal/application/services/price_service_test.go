@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"crypto-price-tracker-app/internal/domain/models"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestPriceService_GetLatestPrices(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	stale := now.Add(-72 * time.Hour)
+
+	currencyRepo := new(MockCurrencyRepository)
+	priceRepo := new(MockPriceRepository)
+	currencyRepo.On("GetAllActive", mock.Anything).Return([]models.Currency{
+		{ID: 3, Symbol: "SOL", Interval: 60, Precision: 2},
+		{ID: 1, Symbol: "BTC", Interval: 60, Precision: 2},
+		{ID: 2, Symbol: "ETH", Interval: 60, Precision: 2},
+		{ID: 4, Symbol: "XRP", Interval: 60, Precision: 2},
+	}, nil)
+	priceRepo.On("GetLatestPrices", mock.Anything, []uint{1, 2, 3, 4}).Return([]models.Price{
+		{CurrencyID: 1, Price: decimal.RequireFromString("110.555"), Timestamp: now.Add(-30 * time.Second)},
+		{CurrencyID: 2, Price: decimal.NewFromInt(3000), Timestamp: now},
+		{CurrencyID: 4, Price: decimal.NewFromInt(2), Timestamp: stale},
+	}, nil)
+	// Changes are measured over the 24 hours before each latest price.
+	priceRepo.On("GetSurroundingPricesBatch", mock.Anything, []models.PriceKey{
+		{CurrencyID: 1, Timestamp: now.Add(-30 * time.Second).Add(-24 * time.Hour)},
+		{CurrencyID: 2, Timestamp: now.Add(-24 * time.Hour)},
+		{CurrencyID: 4, Timestamp: stale.Add(-24 * time.Hour)},
+	}).Return([]models.SurroundingPrices{
+		{Before: &models.Price{CurrencyID: 1, Price: decimal.NewFromInt(100), Timestamp: now.Add(-30*time.Second - 24*time.Hour - time.Minute)}},
+		{After: &models.Price{CurrencyID: 2, Price: decimal.NewFromInt(2900), Timestamp: now.Add(-23 * time.Hour)}},
+		{Before: &models.Price{CurrencyID: 4, Price: decimal.NewFromInt(4), Timestamp: stale.Add(-24*time.Hour - 2*time.Minute)}},
+	}, nil)
+
+	service := NewPriceService(priceRepo, currencyRepo, nil, nil, nil, 3, zap.NewNop())
+	prices, err := service.GetLatestPrices(context.Background(), nil)
+	require.NoError(t, err)
+	require.Len(t, prices, 4)
+
+	btc := prices[0]
+	assert.Equal(t, "BTC", btc.Symbol)
+	assert.Equal(t, "110.56", btc.Price.String())
+	assert.InDelta(t, 30, *btc.AgeSeconds, 2)
+	assert.Equal(t, "10.56", btc.Change24h.String())
+	assert.Equal(t, "10.56", btc.ChangePercent24h.String())
+
+	// ETH has no price as old as 24 hours.
+	eth := prices[1]
+	assert.Equal(t, "ETH", eth.Symbol)
+	assert.NotNil(t, eth.Price)
+	assert.Nil(t, eth.Change24h)
+	assert.Nil(t, eth.ChangePercent24h)
+
+	sol := prices[2]
+	assert.Equal(t, "SOL", sol.Symbol)
+	assert.Nil(t, sol.Price)
+	assert.Nil(t, sol.Timestamp)
+	assert.Nil(t, sol.AgeSeconds)
+
+	xrp := prices[3]
+	assert.Equal(t, "XRP", xrp.Symbol)
+	assert.InDelta(t, 72*3600, *xrp.AgeSeconds, 2)
+	assert.Equal(t, "-2", xrp.Change24h.String())
+	assert.Equal(t, "-50", xrp.ChangePercent24h.String())
+}
+
+func TestPriceService_GetLatestPricesFiltersSymbols(t *testing.T) {
+	currencyRepo := new(MockCurrencyRepository)
+	priceRepo := new(MockPriceRepository)
+	currencyRepo.On("GetAllActive", mock.Anything).Return([]models.Currency{
+		{ID: 1, Symbol: "BTC"},
+		{ID: 2, Symbol: "ETH"},
+	}, nil)
+	priceRepo.On("GetLatestPrices", mock.Anything, []uint{2}).Return([]models.Price(nil), nil)
+	priceRepo.On("GetSurroundingPricesBatch", mock.Anything, []models.PriceKey{}).Return([]models.SurroundingPrices{}, nil)
+
+	service := NewPriceService(priceRepo, currencyRepo, nil, nil, nil, 3, zap.NewNop())
+	prices, err := service.GetLatestPrices(context.Background(), []string{" eth", "DOGE"})
+	require.NoError(t, err)
+	require.Len(t, prices, 1)
+	assert.Equal(t, "ETH", prices[0].Symbol)
+	assert.Nil(t, prices[0].Price)
+}
+
+func TestPriceService_GetLatestPricesIgnoresDistantReference(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+
+	currencyRepo := new(MockCurrencyRepository)
+	priceRepo := new(MockPriceRepository)
+	currencyRepo.On("GetAllActive", mock.Anything).Return([]models.Currency{
+		{ID: 1, Symbol: "BTC", Interval: 60, Precision: 2},
+		{ID: 2, Symbol: "ETH", Interval: 60, StaleAfter: 3600, Precision: 2},
+	}, nil)
+	priceRepo.On("GetLatestPrices", mock.Anything, []uint{1, 2}).Return([]models.Price{
+		{CurrencyID: 1, Price: decimal.NewFromInt(110), Timestamp: now},
+		{CurrencyID: 2, Price: decimal.NewFromInt(3300), Timestamp: now},
+	}, nil)
+	// BTC has a gap of a month before the window, ETH one within its
+	// explicit stale_after.
+	priceRepo.On("GetSurroundingPricesBatch", mock.Anything, mock.Anything).Return([]models.SurroundingPrices{
+		{Before: &models.Price{CurrencyID: 1, Price: decimal.NewFromInt(50), Timestamp: now.Add(-30 * 24 * time.Hour)}},
+		{Before: &models.Price{CurrencyID: 2, Price: decimal.NewFromInt(3000), Timestamp: now.Add(-25 * time.Hour)}},
+	}, nil)
+
+	service := NewPriceService(priceRepo, currencyRepo, nil, nil, nil, 3, zap.NewNop())
+	prices, err := service.GetLatestPrices(context.Background(), nil)
+	require.NoError(t, err)
+	require.Len(t, prices, 2)
+
+	btc := prices[0]
+	assert.Equal(t, "110", btc.Price.String())
+	assert.Nil(t, btc.Change24h)
+	assert.Nil(t, btc.ChangePercent24h)
+
+	eth := prices[1]
+	assert.Equal(t, "300", eth.Change24h.String())
+	assert.Equal(t, "10", eth.ChangePercent24h.String())
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"crypto-price-tracker-app/internal/application/dto"
+	"crypto-price-tracker-app/internal/domain/models"
+	"crypto-price-tracker-app/internal/domain/repository"
+
+	"go.uber.org/zap"
+)
+
+// subscriberBuffer is how many events a slow client may fall behind before
+// further events are dropped for it.
+const subscriberBuffer = 32
+
+type subscriber struct {
+	symbols map[string]bool
+	events  chan dto.PriceEventResponse
+}
+
+// PriceStreamService fans new prices out to subscribed clients.
+type PriceStreamService struct {
+	currencyRepo repository.CurrencyRepository
+	logger       *zap.Logger
+
+	mu          sync.Mutex
+	subscribers map[*subscriber]struct{}
+}
+
+func NewPriceStreamService(currencyRepo repository.CurrencyRepository, logger *zap.Logger) *PriceStreamService {
+	return &PriceStreamService{
+		currencyRepo: currencyRepo,
+		logger:       logger,
+		subscribers:  make(map[*subscriber]struct{}),
+	}
+}
+
+// Subscribe returns a channel of new prices of symbols, or of every currency
+// when symbols is empty, and a function that ends the subscription.
+func (s *PriceStreamService) Subscribe(symbols []string) (<-chan dto.PriceEventResponse, func()) {
+	sub := &subscriber{events: make(chan dto.PriceEventResponse, subscriberBuffer)}
+	if len(symbols) > 0 {
+		sub.symbols = make(map[string]bool, len(symbols))
+		for _, symbol := range symbols {
+			sub.symbols[strings.ToUpper(strings.TrimSpace(symbol))] = true
+		}
+	}
+
+	s.mu.Lock()
+	s.subscribers[sub] = struct{}{}
+	s.mu.Unlock()
+
+	var once sync.Once
+	return sub.events, func() {
+		once.Do(func() {
+			s.mu.Lock()
+			delete(s.subscribers, sub)
+			s.mu.Unlock()
+			close(sub.events)
+		})
+	}
+}
+
+// Publish delivers a price.created event to matching subscribers. Other event
+// types carry no price and are ignored.
+func (s *PriceStreamService) Publish(ctx context.Context, event models.PriceEvent) {
+	if event.Type != models.PriceEventCreated || event.Price == nil || event.Timestamp == nil {
+		return
+	}
+
+	s.mu.Lock()
+	empty := len(s.subscribers) == 0
+	s.mu.Unlock()
+	if empty {
+		return
+	}
+
+	currencies, err := s.currencyRepo.GetAllActive(ctx)
+	if err != nil {
+		s.logger.Error("Failed to resolve currency of price event", zap.Uint("currency_id", event.CurrencyID), zap.Error(err))
+		return
+	}
+	var currency *models.Currency
+	for i := range currencies {
+		if currencies[i].ID == event.CurrencyID {
+			currency = &currencies[i]
+			break
+		}
+	}
+	if currency == nil {
+		return
+	}
+
+	response := dto.PriceEventResponse{
+		Symbol:    currency.Symbol,
+		Price:     event.Price.Round(int32(currency.Precision)),
+		Timestamp: *event.Timestamp,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for sub := range s.subscribers {
+		if sub.symbols != nil && !sub.symbols[strings.ToUpper(currency.Symbol)] {
+			continue
+		}
+		select {
+		case sub.events <- response:
+		default:
+			s.logger.Debug("Dropping price event for slow subscriber", zap.String("symbol", currency.Symbol))
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"crypto-price-tracker-app/internal/domain/models"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestPriceStreamService_Publish(t *testing.T) {
+	mockCurrencyRepo := new(MockCurrencyRepository)
+	mockCurrencyRepo.On("GetAllActive", mock.Anything).Return([]models.Currency{
+		{ID: 1, Symbol: "BTC", Precision: 2},
+		{ID: 2, Symbol: "ETH", Precision: 2},
+	}, nil)
+
+	service := NewPriceStreamService(mockCurrencyRepo, zap.NewNop())
+	all, unsubscribeAll := service.Subscribe(nil)
+	defer unsubscribeAll()
+	btc, unsubscribeBTC := service.Subscribe([]string{"btc"})
+
+	at := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+	service.Publish(ctx, models.NewPriceCreatedEvent(&models.Price{CurrencyID: 2, Price: decimal.RequireFromString("3000.456"), Timestamp: at}))
+	service.Publish(ctx, models.NewPricesChangedEvent(1))
+	service.Publish(ctx, models.NewPriceCreatedEvent(&models.Price{CurrencyID: 1, Price: decimal.NewFromInt(65000), Timestamp: at}))
+	service.Publish(ctx, models.NewPriceCreatedEvent(&models.Price{CurrencyID: 99, Price: decimal.NewFromInt(1), Timestamp: at}))
+
+	require.Len(t, all, 2)
+	first := <-all
+	assert.Equal(t, "ETH", first.Symbol)
+	assert.Equal(t, "3000.46", first.Price.String())
+	assert.Equal(t, at, first.Timestamp)
+	assert.Equal(t, "BTC", (<-all).Symbol)
+
+	require.Len(t, btc, 1)
+	assert.Equal(t, "BTC", (<-btc).Symbol)
+
+	unsubscribeBTC()
+	_, open := <-btc
+	assert.False(t, open)
+	unsubscribeBTC()
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"crypto-price-tracker-app/internal/domain/models"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	RuleNonPositive = "non_positive"
+	RuleMinPrice    = "min_price"
+	RuleMaxPrice    = "max_price"
+	RuleMaxChange   = "max_change"
+	RuleStaleQuote  = "stale_quote"
+)
+
+// PriceRules is the set of sanity checks applied to a fetched price before it
+// is stored. Zero values disable the corresponding check.
+type PriceRules struct {
+	MinPrice         decimal.Decimal
+	MaxPrice         decimal.Decimal
+	MaxChangePercent float64
+	MaxQuoteAge      time.Duration
+}
+
+type Violation struct {
+	Rule   string
+	Reason string
+}
+
+type PriceValidator struct {
+	defaults  PriceRules
+	overrides map[string]PriceRules
+}
+
+func NewPriceValidator(defaults PriceRules, overrides map[string]PriceRules) *PriceValidator {
+	normalized := make(map[string]PriceRules, len(overrides))
+	for symbol, rules := range overrides {
+		normalized[strings.ToLower(symbol)] = rules
+	}
+	return &PriceValidator{
+		defaults:  defaults,
+		overrides: normalized,
+	}
+}
+
+// RulesFor returns the effective rules for a currency: per-currency overrides
+// win over the defaults field by field.
+func (v *PriceValidator) RulesFor(symbol string) PriceRules {
+	rules := v.defaults
+	override, ok := v.overrides[strings.ToLower(symbol)]
+	if !ok {
+		return rules
+	}
+
+	if !override.MinPrice.IsZero() {
+		rules.MinPrice = override.MinPrice
+	}
+	if !override.MaxPrice.IsZero() {
+		rules.MaxPrice = override.MaxPrice
+	}
+	if override.MaxChangePercent != 0 {
+		rules.MaxChangePercent = override.MaxChangePercent
+	}
+	if override.MaxQuoteAge != 0 {
+		rules.MaxQuoteAge = override.MaxQuoteAge
+	}
+	return rules
+}
+
+// Validate checks a quote against the rules for the currency. last is the most
+// recently stored price and may be nil. It returns nil when the quote is sane.
+func (v *PriceValidator) Validate(symbol string, quote *models.Quote, last *models.Price, now time.Time) *Violation {
+	rules := v.RulesFor(symbol)
+
+	if !quote.Price.IsPositive() {
+		return &Violation{Rule: RuleNonPositive, Reason: fmt.Sprintf("price %s is not a positive number", quote.Price)}
+	}
+
+	if rules.MinPrice.IsPositive() && quote.Price.LessThan(rules.MinPrice) {
+		return &Violation{Rule: RuleMinPrice, Reason: fmt.Sprintf("price %s is below minimum %s", quote.Price, rules.MinPrice)}
+	}
+
+	if rules.MaxPrice.IsPositive() && quote.Price.GreaterThan(rules.MaxPrice) {
+		return &Violation{Rule: RuleMaxPrice, Reason: fmt.Sprintf("price %s is above maximum %s", quote.Price, rules.MaxPrice)}
+	}
+
+	if rules.MaxQuoteAge > 0 && !quote.UpdatedAt.IsZero() {
+		if age := now.Sub(quote.UpdatedAt); age > rules.MaxQuoteAge {
+			return &Violation{Rule: RuleStaleQuote, Reason: fmt.Sprintf("quote is %s old, maximum is %s", age.Truncate(time.Second), rules.MaxQuoteAge)}
+		}
+	}
+
+	if rules.MaxChangePercent > 0 && last != nil && last.Price.IsPositive() {
+		change := quote.Price.Sub(last.Price).Abs().Div(last.Price).Mul(decimal.NewFromInt(100))
+		if change.GreaterThan(decimal.NewFromFloat(rules.MaxChangePercent)) {
+			return &Violation{Rule: RuleMaxChange, Reason: fmt.Sprintf("price changed by %s%% from %s, maximum is %v%%", change.StringFixed(2), last.Price, rules.MaxChangePercent)}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"crypto-price-tracker-app/internal/domain/models"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriceValidator_Validate(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	validator := NewPriceValidator(
+		PriceRules{MaxChangePercent: 50, MaxQuoteAge: time.Hour},
+		map[string]PriceRules{"USDT": {MinPrice: decimal.RequireFromString("0.9"), MaxPrice: decimal.RequireFromString("1.1"), MaxChangePercent: 5}},
+	)
+
+	tests := []struct {
+		name     string
+		symbol   string
+		quote    *models.Quote
+		last     *models.Price
+		wantRule string
+	}{
+		{
+			name:   "valid price",
+			symbol: "BTC",
+			quote:  &models.Quote{Price: decimal.RequireFromString("42000"), UpdatedAt: now.Add(-time.Minute)},
+			last:   &models.Price{Price: decimal.RequireFromString("41000")},
+		},
+		{
+			name:     "zero price",
+			symbol:   "BTC",
+			quote:    &models.Quote{Price: decimal.RequireFromString("0")},
+			wantRule: RuleNonPositive,
+		},
+		{
+			name:     "negative price",
+			symbol:   "BTC",
+			quote:    &models.Quote{Price: decimal.RequireFromString("-1")},
+			wantRule: RuleNonPositive,
+		},
+		{
+			name:     "jump against last stored price",
+			symbol:   "BTC",
+			quote:    &models.Quote{Price: decimal.RequireFromString("42000000")},
+			last:     &models.Price{Price: decimal.RequireFromString("42000")},
+			wantRule: RuleMaxChange,
+		},
+		{
+			name:   "no last stored price",
+			symbol: "BTC",
+			quote:  &models.Quote{Price: decimal.RequireFromString("42000000")},
+		},
+		{
+			name:     "stale quote",
+			symbol:   "BTC",
+			quote:    &models.Quote{Price: decimal.RequireFromString("42000"), UpdatedAt: now.Add(-2 * time.Hour)},
+			wantRule: RuleStaleQuote,
+		},
+		{
+			name:     "below per-currency minimum",
+			symbol:   "usdt",
+			quote:    &models.Quote{Price: decimal.RequireFromString("0.5")},
+			wantRule: RuleMinPrice,
+		},
+		{
+			name:     "above per-currency maximum",
+			symbol:   "USDT",
+			quote:    &models.Quote{Price: decimal.RequireFromString("1.5")},
+			wantRule: RuleMaxPrice,
+		},
+		{
+			name:   "sub-cent price keeps precision",
+			symbol: "SHIB",
+			quote:  &models.Quote{Price: decimal.RequireFromString("0.000012345678901234")},
+			last:   &models.Price{Price: decimal.RequireFromString("0.000012345678901233")},
+		},
+		{
+			name:     "per-currency change limit",
+			symbol:   "USDT",
+			quote:    &models.Quote{Price: decimal.RequireFromString("1.09")},
+			last:     &models.Price{Price: decimal.RequireFromString("1.0")},
+			wantRule: RuleMaxChange,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violation := validator.Validate(tt.symbol, tt.quote, tt.last, now)
+			if tt.wantRule == "" {
+				assert.Nil(t, violation)
+				return
+			}
+			if assert.NotNil(t, violation) {
+				assert.Equal(t, tt.wantRule, violation.Rule)
+			}
+		})
+	}
+}
+
+func TestPriceValidator_RulesFor(t *testing.T) {
+	validator := NewPriceValidator(
+		PriceRules{MaxChangePercent: 50, MaxQuoteAge: time.Hour},
+		map[string]PriceRules{"usdt": {MaxChangePercent: 5}},
+	)
+
+	rules := validator.RulesFor("USDT")
+	assert.Equal(t, 5.0, rules.MaxChangePercent)
+	assert.Equal(t, time.Hour, rules.MaxQuoteAge)
+
+	assert.Equal(t, 50.0, validator.RulesFor("BTC").MaxChangePercent)
+}
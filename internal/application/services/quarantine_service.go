@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"crypto-price-tracker-app/internal/application/dto"
+	"crypto-price-tracker-app/internal/domain/models"
+	"crypto-price-tracker-app/internal/domain/repository"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrQuarantinedPriceNotFound = errors.New("quarantined price not found")
+	// ErrQuarantinedPriceReviewed means the sample was approved or rejected
+	// already, possibly by a concurrent review.
+	ErrQuarantinedPriceReviewed = errors.New("quarantined price already reviewed")
+)
+
+type QuarantineService struct {
+	quarantineRepo repository.QuarantineRepository
+	unitOfWork     repository.UnitOfWork
+	logger         *zap.Logger
+}
+
+func NewQuarantineService(quarantineRepo repository.QuarantineRepository, unitOfWork repository.UnitOfWork, logger *zap.Logger) *QuarantineService {
+	return &QuarantineService{
+		quarantineRepo: quarantineRepo,
+		unitOfWork:     unitOfWork,
+		logger:         logger,
+	}
+}
+
+func (s *QuarantineService) List(ctx context.Context, status string) ([]dto.QuarantinedPriceResponse, error) {
+	switch status {
+	case "", models.QuarantineStatusPending, models.QuarantineStatusApproved, models.QuarantineStatusRejected:
+	default:
+		return nil, errors.New("invalid quarantine status")
+	}
+
+	samples, err := s.quarantineRepo.List(ctx, status)
+	if err != nil {
+		s.logger.Error("Failed to list quarantined prices", zap.String("status", status), zap.Error(err))
+		return nil, err
+	}
+
+	responses := make([]dto.QuarantinedPriceResponse, 0, len(samples))
+	for i := range samples {
+		responses = append(responses, toQuarantinedPriceResponse(&samples[i]))
+	}
+	return responses, nil
+}
+
+// Approve stores the sample as a price and marks it approved in one
+// transaction. A concurrent review makes the guarded status update fail,
+// which rolls the price back.
+func (s *QuarantineService) Approve(ctx context.Context, id uint) (*dto.QuarantinedPriceResponse, error) {
+	var response *dto.QuarantinedPriceResponse
+	err := s.unitOfWork.WithTx(ctx, func(repos repository.Repositories) error {
+		sample, err := s.getPending(ctx, repos.Quarantine, id)
+		if err != nil {
+			return err
+		}
+
+		price := &models.Price{
+			CurrencyID: sample.CurrencyID,
+			Price:      sample.Price.Round(int32(sample.Currency.Precision)),
+			Timestamp:  sample.Timestamp,
+		}
+		if err := repos.Prices.Create(ctx, price); err != nil {
+			s.logger.Error("Failed to store approved price", zap.Uint("quarantine_id", id), zap.Error(err))
+			return err
+		}
+		response, err = s.review(ctx, repos, sample, models.QuarantineStatusApproved, models.AuditActionQuarantineApprove)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (s *QuarantineService) Reject(ctx context.Context, id uint) (*dto.QuarantinedPriceResponse, error) {
+	var response *dto.QuarantinedPriceResponse
+	err := s.unitOfWork.WithTx(ctx, func(repos repository.Repositories) error {
+		sample, err := s.getPending(ctx, repos.Quarantine, id)
+		if err != nil {
+			return err
+		}
+		response, err = s.review(ctx, repos, sample, models.QuarantineStatusRejected, models.AuditActionQuarantineReject)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (s *QuarantineService) getPending(ctx context.Context, quarantineRepo repository.QuarantineRepository, id uint) (*models.QuarantinedPrice, error) {
+	sample, err := quarantineRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrQuarantinedPriceNotFound
+		}
+		s.logger.Error("Failed to get quarantined price", zap.Uint("quarantine_id", id), zap.Error(err))
+		return nil, err
+	}
+
+	if sample.Status != models.QuarantineStatusPending {
+		return nil, ErrQuarantinedPriceReviewed
+	}
+	return sample, nil
+}
+
+func (s *QuarantineService) review(ctx context.Context, repos repository.Repositories, sample *models.QuarantinedPrice, status, action string) (*dto.QuarantinedPriceResponse, error) {
+	reviewedAt := time.Now()
+	if err := repos.Quarantine.UpdateStatus(ctx, sample.ID, status, reviewedAt); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return nil, ErrQuarantinedPriceReviewed
+		}
+		s.logger.Error("Failed to update quarantined price", zap.Uint("quarantine_id", sample.ID), zap.String("status", status), zap.Error(err))
+		return nil, err
+	}
+	err := recordAudit(ctx, repos.Audit, action, models.AuditTargetQuarantine, strconv.FormatUint(uint64(sample.ID), 10),
+		newQuarantineAuditState(sample, sample.Status), newQuarantineAuditState(sample, status))
+	if err != nil {
+		return nil, err
+	}
+
+	sample.Status = status
+	sample.ReviewedAt = &reviewedAt
+
+	s.logger.Info("Quarantined price reviewed", zap.Uint("quarantine_id", sample.ID), zap.String("status", status))
+	response := toQuarantinedPriceResponse(sample)
+	return &response, nil
+}
+
+func toQuarantinedPriceResponse(sample *models.QuarantinedPrice) dto.QuarantinedPriceResponse {
+	return dto.QuarantinedPriceResponse{
+		ID:             sample.ID,
+		Symbol:         sample.Currency.Symbol,
+		Price:          sample.Price,
+		Timestamp:      sample.Timestamp,
+		ReferencePrice: sample.ReferencePrice,
+		Rule:           sample.Rule,
+		Reason:         sample.Reason,
+		Status:         sample.Status,
+		ReviewedAt:     sample.ReviewedAt,
+		CreatedAt:      sample.CreatedAt,
+	}
+}
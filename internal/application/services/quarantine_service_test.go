@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"crypto-price-tracker-app/internal/domain/models"
+	"crypto-price-tracker-app/internal/domain/repository"
+	"crypto-price-tracker-app/internal/infrastructure/memory"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type quarantineFixture struct {
+	service    *QuarantineService
+	prices     repository.PriceRepository
+	quarantine repository.QuarantineRepository
+	audit      repository.AuditRepository
+	sample     *models.QuarantinedPrice
+}
+
+// newQuarantineFixture returns a service over in-memory storage holding one
+// pending sample.
+func newQuarantineFixture(t *testing.T) *quarantineFixture {
+	t.Helper()
+	store := memory.NewStore()
+	ctx := context.Background()
+
+	currency := &models.Currency{Symbol: "BTC", ApiID: "bitcoin", Interval: 60, Precision: 2}
+	require.NoError(t, memory.NewCurrencyRepository(store).Create(ctx, currency))
+	quarantine := memory.NewQuarantineRepository(store)
+	sample := &models.QuarantinedPrice{
+		CurrencyID: currency.ID,
+		Price:      decimal.RequireFromString("150.555"),
+		Timestamp:  time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC),
+		Rule:       "max_change",
+		Reason:     "price moved 50%",
+	}
+	require.NoError(t, quarantine.Create(ctx, sample))
+
+	return &quarantineFixture{
+		service:    NewQuarantineService(quarantine, memory.NewUnitOfWork(store), zap.NewNop()),
+		prices:     memory.NewPriceRepository(store),
+		quarantine: quarantine,
+		audit:      memory.NewAuditRepository(store),
+		sample:     sample,
+	}
+}
+
+func (f *quarantineFixture) auditActions(t *testing.T) []string {
+	t.Helper()
+	events, err := f.audit.List(context.Background(), models.AuditFilter{TargetType: models.AuditTargetQuarantine})
+	require.NoError(t, err)
+	actions := make([]string, len(events))
+	for i, event := range events {
+		actions[i] = event.Action
+	}
+	return actions
+}
+
+func TestQuarantineService_Approve(t *testing.T) {
+	f := newQuarantineFixture(t)
+	ctx := context.Background()
+
+	response, err := f.service.Approve(ctx, f.sample.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.QuarantineStatusApproved, response.Status)
+	assert.NotNil(t, response.ReviewedAt)
+
+	price, err := f.prices.GetByCurrencyAndTime(ctx, f.sample.CurrencyID, f.sample.Timestamp)
+	require.NoError(t, err)
+	assert.Equal(t, "150.56", price.Price.String())
+	assert.Equal(t, []string{models.AuditActionQuarantineApprove}, f.auditActions(t))
+}
+
+func TestQuarantineService_Reject(t *testing.T) {
+	f := newQuarantineFixture(t)
+	ctx := context.Background()
+
+	response, err := f.service.Reject(ctx, f.sample.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.QuarantineStatusRejected, response.Status)
+
+	_, err = f.prices.GetByCurrencyAndTime(ctx, f.sample.CurrencyID, f.sample.Timestamp)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	assert.Equal(t, []string{models.AuditActionQuarantineReject}, f.auditActions(t))
+}
+
+func TestQuarantineService_AlreadyReviewed(t *testing.T) {
+	f := newQuarantineFixture(t)
+	ctx := context.Background()
+
+	_, err := f.service.Reject(ctx, f.sample.ID)
+	require.NoError(t, err)
+
+	_, err = f.service.Approve(ctx, f.sample.ID)
+	assert.ErrorIs(t, err, ErrQuarantinedPriceReviewed)
+	_, err = f.service.Reject(ctx, f.sample.ID)
+	assert.ErrorIs(t, err, ErrQuarantinedPriceReviewed)
+
+	// The second review leaves neither a price nor an audit event behind.
+	_, err = f.prices.GetByCurrencyAndTime(ctx, f.sample.CurrencyID, f.sample.Timestamp)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	assert.Equal(t, []string{models.AuditActionQuarantineReject}, f.auditActions(t))
+
+	// A review racing past the pending check is still stopped by the store.
+	err = f.quarantine.UpdateStatus(ctx, f.sample.ID, models.QuarantineStatusApproved, time.Now())
+	assert.ErrorIs(t, err, repository.ErrConflict)
+}
+
+func TestQuarantineService_NotFound(t *testing.T) {
+	f := newQuarantineFixture(t)
+	ctx := context.Background()
+
+	_, err := f.service.Approve(ctx, f.sample.ID+1)
+	assert.ErrorIs(t, err, ErrQuarantinedPriceNotFound)
+	_, err = f.service.Reject(ctx, f.sample.ID+1)
+	assert.ErrorIs(t, err, ErrQuarantinedPriceNotFound)
+	assert.Empty(t, f.auditActions(t))
+}
+
+// failingReviewUnitOfWork makes every status update inside a unit of work
+// fail, after whatever the unit of work did before it.
+type failingReviewUnitOfWork struct {
+	repository.UnitOfWork
+}
+
+type failingQuarantineRepository struct {
+	repository.QuarantineRepository
+}
+
+func (failingQuarantineRepository) UpdateStatus(ctx context.Context, id uint, status string, reviewedAt time.Time) error {
+	return errors.New("connection reset")
+}
+
+func (u failingReviewUnitOfWork) WithTx(ctx context.Context, fn func(repos repository.Repositories) error) error {
+	return u.UnitOfWork.WithTx(ctx, func(repos repository.Repositories) error {
+		repos.Quarantine = failingQuarantineRepository{repos.Quarantine}
+		return fn(repos)
+	})
+}
+
+func TestQuarantineService_ApproveRollsBackPrice(t *testing.T) {
+	f := newQuarantineFixture(t)
+	ctx := context.Background()
+	f.service.unitOfWork = failingReviewUnitOfWork{f.service.unitOfWork}
+
+	_, err := f.service.Approve(ctx, f.sample.ID)
+	require.Error(t, err)
+
+	// The price created before the failed review is rolled back with it.
+	_, err = f.prices.GetByCurrencyAndTime(ctx, f.sample.CurrencyID, f.sample.Timestamp)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	found, err := f.quarantine.GetByID(ctx, f.sample.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.QuarantineStatusPending, found.Status)
+	assert.Empty(t, f.auditActions(t))
+}
@@ -1,24 +1,36 @@
 package http
 
 import (
+	"context"
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"crypto-price-tracker-app/internal/application/dto"
 	"crypto-price-tracker-app/internal/application/services"
+	"crypto-price-tracker-app/internal/domain/models"
 
 	"github.com/gin-gonic/gin"
 )
 
 type Handlers struct {
-	currencyService *services.CurrencyService
-	priceService    *services.PriceService
+	currencyService    *services.CurrencyService
+	priceService       *services.PriceService
+	quarantineService  *services.QuarantineService
+	priceStreamService *services.PriceStreamService
+	auditService       *services.AuditService
 }
 
-func NewHandlers(currencyService *services.CurrencyService, priceService *services.PriceService) *Handlers {
+func NewHandlers(currencyService *services.CurrencyService, priceService *services.PriceService, quarantineService *services.QuarantineService, priceStreamService *services.PriceStreamService, auditService *services.AuditService) *Handlers {
 	return &Handlers{
-		currencyService: currencyService,
-		priceService:    priceService,
+		currencyService:    currencyService,
+		priceService:       priceService,
+		quarantineService:  quarantineService,
+		priceStreamService: priceStreamService,
+		auditService:       auditService,
 	}
 }
 
@@ -35,11 +47,7 @@ func (h *Handlers) AddCurrency(c *gin.Context) {
 
 	currency, err := h.currencyService.AddCurrency(c.Request.Context(), &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Error:   "currency_error",
-			Message: err.Error(),
-			Code:    400,
-		})
+		currencyLifecycleError(c, err)
 		return
 	}
 
@@ -71,6 +79,127 @@ func (h *Handlers) RemoveCurrency(c *gin.Context) {
 	})
 }
 
+func (h *Handlers) GetCurrency(c *gin.Context) {
+	currency, err := h.currencyService.GetCurrency(c.Request.Context(), c.Param("symbol"))
+	if err != nil {
+		currencyLifecycleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, currency)
+}
+
+func (h *Handlers) UpdateCurrency(c *gin.Context) {
+	var req dto.UpdateCurrencyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+			Code:    400,
+		})
+		return
+	}
+	req.Symbol = c.Param("symbol")
+
+	currency, err := h.currencyService.UpdateCurrency(c.Request.Context(), &req)
+	if err != nil {
+		currencyLifecycleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, currency)
+}
+
+func (h *Handlers) ChangeCurrencyStatus(c *gin.Context) {
+	var req dto.ChangeCurrencyStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+			Code:    400,
+		})
+		return
+	}
+	req.Symbol = c.Param("symbol")
+
+	currency, err := h.currencyService.ChangeStatus(c.Request.Context(), &req)
+	if err != nil {
+		currencyLifecycleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, currency)
+}
+
+// DeleteCurrency takes what to do with the prices (keep, purge or archive)
+// and an optional reason as query parameters.
+func (h *Handlers) DeleteCurrency(c *gin.Context) {
+	req := &dto.DeleteCurrencyRequest{
+		Symbol: c.Param("symbol"),
+		Prices: c.Query("prices"),
+		Reason: c.Query("reason"),
+	}
+
+	result, err := h.currencyService.DeleteCurrency(c.Request.Context(), req)
+	if err != nil {
+		currencyLifecycleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *Handlers) RestoreCurrency(c *gin.Context) {
+	var req dto.RestoreCurrencyRequest
+	// The body is optional; it only carries the reason.
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "validation_error",
+				Message: err.Error(),
+				Code:    400,
+			})
+			return
+		}
+	}
+	req.Symbol = c.Param("symbol")
+
+	currency, err := h.currencyService.RestoreCurrency(c.Request.Context(), &req)
+	if err != nil {
+		currencyLifecycleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, currency)
+}
+
+func (h *Handlers) ListCurrencyTransitions(c *gin.Context) {
+	transitions, err := h.currencyService.ListTransitions(c.Request.Context(), c.Param("symbol"))
+	if err != nil {
+		currencyLifecycleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, transitions)
+}
+
+func currencyLifecycleError(c *gin.Context, err error) {
+	status, code := http.StatusInternalServerError, "currency_error"
+	switch {
+	case errors.Is(err, services.ErrInvalidCurrencyRequest):
+		status, code = http.StatusBadRequest, "validation_error"
+	case errors.Is(err, services.ErrCurrencyNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrInvalidTransition), errors.Is(err, services.ErrCurrencyExists):
+		status = http.StatusConflict
+	}
+	c.JSON(status, dto.ErrorResponse{
+		Error:   code,
+		Message: err.Error(),
+		Code:    status,
+	})
+}
+
 func (h *Handlers) GetPrice(c *gin.Context) {
 	coin := c.Query("coin")
 	if coin == "" {
@@ -105,10 +234,20 @@ func (h *Handlers) GetPrice(c *gin.Context) {
 	req := &dto.GetPriceRequest{
 		Coin:      coin,
 		Timestamp: timestamp,
+		Mode:      c.Query("mode"),
+		MaxGap:    c.Query("max_gap"),
 	}
 
 	price, err := h.currencyService.GetPrice(c.Request.Context(), req)
 	if err != nil {
+		if errors.Is(err, services.ErrInvalidPriceRequest) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "validation_error",
+				Message: err.Error(),
+				Code:    400,
+			})
+			return
+		}
 		c.JSON(http.StatusNotFound, dto.ErrorResponse{
 			Error:   "price_error",
 			Message: err.Error(),
@@ -120,6 +259,162 @@ func (h *Handlers) GetPrice(c *gin.Context) {
 	c.JSON(http.StatusOK, price)
 }
 
+func (h *Handlers) LookupPrices(c *gin.Context) {
+	var req dto.LookupPricesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+			Code:    400,
+		})
+		return
+	}
+
+	response, err := h.currencyService.LookupPrices(c.Request.Context(), &req)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidPriceRequest) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "validation_error",
+				Message: err.Error(),
+				Code:    400,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+			Code:    500,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *Handlers) GetCandles(c *gin.Context) {
+	req := &dto.GetCandlesRequest{
+		Symbol:   c.Param("symbol"),
+		Interval: c.DefaultQuery("interval", "1h"),
+	}
+
+	for name, target := range map[string]*int64{"from": &req.From, "to": &req.To} {
+		value := c.Query(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "validation_error",
+				Message: "invalid " + name + " format",
+				Code:    400,
+			})
+			return
+		}
+		*target = parsed
+	}
+
+	if fill := c.Query("fill"); fill != "" {
+		parsed, err := strconv.ParseBool(fill)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "validation_error",
+				Message: "invalid fill format",
+				Code:    400,
+			})
+			return
+		}
+		req.Fill = parsed
+	}
+
+	candles, err := h.currencyService.GetCandles(c.Request.Context(), req)
+	if err != nil {
+		status, code := http.StatusInternalServerError, "candles_error"
+		switch {
+		case errors.Is(err, services.ErrInvalidCandleRequest):
+			status, code = http.StatusBadRequest, "validation_error"
+		case errors.Is(err, services.ErrCurrencyNotFound):
+			status = http.StatusNotFound
+		}
+		c.JSON(status, dto.ErrorResponse{
+			Error:   code,
+			Message: err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, candles)
+}
+
+// GetPriceHistory pages through stored prices. from and to are unix seconds;
+// cursor comes from the next_cursor or prev_cursor of a previous response.
+func (h *Handlers) GetPriceHistory(c *gin.Context) {
+	req := &dto.GetPriceHistoryRequest{
+		Symbol: c.Param("symbol"),
+		Cursor: c.Query("cursor"),
+		Order:  c.Query("order"),
+		Step:   c.Query("step"),
+	}
+
+	for name, target := range map[string]*int64{"from": &req.From, "to": &req.To} {
+		value := c.Query(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "validation_error",
+				Message: "invalid " + name + " format",
+				Code:    400,
+			})
+			return
+		}
+		*target = parsed
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		parsed, err := strconv.Atoi(limit)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "validation_error",
+				Message: "invalid limit format",
+				Code:    400,
+			})
+			return
+		}
+		req.Limit = parsed
+	}
+
+	history, err := h.currencyService.GetPriceHistory(c.Request.Context(), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidHistoryRequest):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "validation_error",
+				Message: err.Error(),
+				Code:    400,
+			})
+		case errors.Is(err, services.ErrCurrencyNotFound):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "history_error",
+				Message: err.Error(),
+				Code:    404,
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Error:   "history_error",
+				Message: err.Error(),
+				Code:    500,
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
 func (h *Handlers) GetAllCurrencies(c *gin.Context) {
 	currencies, err := h.currencyService.GetAllActiveCurrencies(c.Request.Context())
 	if err != nil {
@@ -134,6 +429,152 @@ func (h *Handlers) GetAllCurrencies(c *gin.Context) {
 	c.JSON(http.StatusOK, currencies)
 }
 
+func (h *Handlers) GetStaleCurrencies(c *gin.Context) {
+	currencies, err := h.currencyService.GetStaleCurrencies(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+			Code:    500,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, currencies)
+}
+
+func (h *Handlers) ListQuarantine(c *gin.Context) {
+	samples, err := h.quarantineService.List(c.Request.Context(), c.DefaultQuery("status", models.QuarantineStatusPending))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "quarantine_error",
+			Message: err.Error(),
+			Code:    400,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, samples)
+}
+
+func (h *Handlers) ApproveQuarantined(c *gin.Context) {
+	h.reviewQuarantined(c, h.quarantineService.Approve)
+}
+
+func (h *Handlers) RejectQuarantined(c *gin.Context) {
+	h.reviewQuarantined(c, h.quarantineService.Reject)
+}
+
+func (h *Handlers) reviewQuarantined(c *gin.Context, review func(ctx context.Context, id uint) (*dto.QuarantinedPriceResponse, error)) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: "invalid id format",
+			Code:    400,
+		})
+		return
+	}
+
+	sample, err := review(c.Request.Context(), uint(id))
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, services.ErrQuarantinedPriceNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, services.ErrQuarantinedPriceReviewed):
+			status = http.StatusConflict
+		}
+		c.JSON(status, dto.ErrorResponse{
+			Error:   "quarantine_error",
+			Message: err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, sample)
+}
+
+func (h *Handlers) ListAudit(c *gin.Context) {
+	var req dto.ListAuditRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+			Code:    400,
+		})
+		return
+	}
+
+	events, err := h.auditService.List(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "audit_error",
+			Message: err.Error(),
+			Code:    500,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
+// GetLatestPrices takes an optional comma-separated symbols filter, such as
+// "btc, eth".
+func (h *Handlers) GetLatestPrices(c *gin.Context) {
+	var symbols []string
+	for _, symbol := range strings.Split(c.Query("symbols"), ",") {
+		if symbol = strings.ToUpper(strings.TrimSpace(symbol)); symbol != "" {
+			symbols = append(symbols, symbol)
+		}
+	}
+
+	prices, err := h.priceService.GetLatestPrices(c.Request.Context(), symbols)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+			Code:    500,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, prices)
+}
+
+// streamHeartbeat keeps idle streams from being closed by proxies.
+const streamHeartbeat = 15 * time.Second
+
+// StreamPrices pushes new prices as server-sent "price" events. The optional
+// symbols query parameter is a comma-separated filter.
+func (h *Handlers) StreamPrices(c *gin.Context) {
+	var symbols []string
+	if raw := c.Query("symbols"); raw != "" {
+		symbols = strings.Split(raw, ",")
+	}
+
+	events, unsubscribe := h.priceStreamService.Subscribe(symbols)
+	defer unsubscribe()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case event := <-events:
+			c.SSEvent("price", event)
+		case <-heartbeat.C:
+			c.SSEvent("ping", time.Now().Unix())
+		}
+		return true
+	})
+}
+
 func (h *Handlers) HealthCheck(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"status":  "ok",
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Deprecated marks a route as replaced by successor, a path that may refer to
+// the route's parameters as :name. Responses carry the Deprecation header of
+// RFC 9745 with the date since, the Sunset header of RFC 8594 with the date
+// after which the route may be removed, and a Link to the successor.
+func Deprecated(since, sunset time.Time, successor string) gin.HandlerFunc {
+	deprecation := fmt.Sprintf("@%d", since.Unix())
+	sunsetHeader := sunset.UTC().Format(http.TimeFormat)
+	return func(c *gin.Context) {
+		link := successor
+		for _, param := range c.Params {
+			link = strings.ReplaceAll(link, ":"+param.Key, url.PathEscape(param.Value))
+		}
+		c.Header("Deprecation", deprecation)
+		c.Header("Sunset", sunsetHeader)
+		c.Header("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, link))
+		c.Next()
+	}
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeprecated(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	since := time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2027, time.April, 18, 0, 0, 0, 0, time.UTC)
+
+	router := gin.New()
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.GET("/currency/:symbol/candles", Deprecated(since, sunset, "/api/v1/currencies/:symbol/candles"), ok)
+	router.POST("/currency/remove", Deprecated(since, sunset, "/api/v1/currencies/{symbol}"), ok)
+	router.GET("/currency/price", ok)
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		wantLink string
+	}{
+		{
+			name:     "route parameter",
+			method:   http.MethodGet,
+			path:     "/currency/BTC/candles",
+			wantLink: `</api/v1/currencies/BTC/candles>; rel="successor-version"`,
+		},
+		{
+			name:     "escaped route parameter",
+			method:   http.MethodGet,
+			path:     "/currency/a%20b/candles",
+			wantLink: `</api/v1/currencies/a%20b/candles>; rel="successor-version"`,
+		},
+		{
+			name:     "template without parameters",
+			method:   http.MethodPost,
+			path:     "/currency/remove",
+			wantLink: `</api/v1/currencies/{symbol}>; rel="successor-version"`,
+		},
+		{
+			name:   "not deprecated",
+			method: http.MethodGet,
+			path:   "/currency/price",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			if tt.wantLink == "" {
+				assert.Empty(t, recorder.Header().Get("Deprecation"))
+				assert.Empty(t, recorder.Header().Get("Sunset"))
+				assert.Empty(t, recorder.Header().Get("Link"))
+				return
+			}
+			assert.Equal(t, "@1792281600", recorder.Header().Get("Deprecation"))
+			assert.Equal(t, "Sun, 18 Apr 2027 00:00:00 GMT", recorder.Header().Get("Sunset"))
+			assert.Equal(t, tt.wantLink, recorder.Header().Get("Link"))
+		})
+	}
+}
@@ -15,6 +15,7 @@ func Logger(logger *zap.Logger) gin.HandlerFunc {
 			zap.Int("status", param.StatusCode),
 			zap.Duration("latency", param.Latency),
 			zap.String("client_ip", param.ClientIP),
+			zap.Any("request_id", param.Keys[requestIDKey]),
 			zap.String("user_agent", param.Request.UserAgent()),
 			zap.Time("timestamp", param.TimeStamp),
 		)
@@ -42,8 +43,9 @@ func CORSMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Credentials", "true")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Header("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
+		c.Header("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, X-Actor, X-Request-ID")
+		c.Header("Access-Control-Expose-Headers", "X-Request-ID, Deprecation, Link")
+		c.Header("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, PATCH, DELETE")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
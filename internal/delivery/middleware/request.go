@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"crypto-price-tracker-app/internal/application/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	RequestIDHeader = "X-Request-ID"
+	// ActorHeader names the caller in the audit log and lifecycle history.
+	// There is no authentication, so clients name themselves.
+	ActorHeader = "X-Actor"
+
+	requestIDKey = "request_id"
+)
+
+// RequestContext attaches the actor, request ID and client IP to the request
+// context for the audit log. The request ID is taken from the X-Request-ID
+// header or generated, and echoed in the response.
+func RequestContext() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" || len(requestID) > 100 {
+			requestID = newRequestID()
+		}
+		c.Set(requestIDKey, requestID)
+		c.Header(RequestIDHeader, requestID)
+
+		ctx := services.WithRequestInfo(c.Request.Context(), services.RequestInfo{
+			Actor:     c.GetHeader(ActorHeader),
+			RequestID: requestID,
+			ClientIP:  c.ClientIP(),
+		})
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
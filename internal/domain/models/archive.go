@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// ArchiveMonthLayout formats the month an archive covers.
+const ArchiveMonthLayout = "2006-01"
+
+type ArchiveFormat string
+
+const (
+	ArchiveParquet ArchiveFormat = "parquet"
+	ArchiveCSV     ArchiveFormat = "csv"
+)
+
+// ArchiveGroup is the set of prices of one currency in one calendar month
+// (UTC), the unit prices are archived and restored in.
+type ArchiveGroup struct {
+	CurrencyID uint
+	Symbol     string
+	Month      time.Time
+	Rows       int64
+}
+
+// ArchiveEntry describes one archive file in the manifest. File is relative
+// to the archive directory.
+type ArchiveEntry struct {
+	CurrencyID uint          `json:"currency_id"`
+	Symbol     string        `json:"symbol"`
+	Month      string        `json:"month"`
+	File       string        `json:"file"`
+	Format     ArchiveFormat `json:"format"`
+	Rows       int           `json:"rows"`
+	SHA256     string        `json:"sha256"`
+	From       time.Time     `json:"from"`
+	To         time.Time     `json:"to"`
+	ArchivedAt time.Time     `json:"archived_at"`
+	RestoredAt *time.Time    `json:"restored_at,omitempty"`
+}
+
+// MonthStart returns the first instant of the UTC month containing t.
+func MonthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// Audited actions.
+const (
+	AuditActionCurrencyAdd       = "currency.add"
+	AuditActionCurrencyRemove    = "currency.remove"
+	AuditActionCurrencyUpdate    = "currency.update"
+	AuditActionCurrencyStatus    = "currency.status"
+	AuditActionCurrencyDelete    = "currency.delete"
+	AuditActionCurrencyRestore   = "currency.restore"
+	AuditActionQuarantineApprove = "quarantine.approve"
+	AuditActionQuarantineReject  = "quarantine.reject"
+)
+
+// Kinds of audited targets.
+const (
+	AuditTargetCurrency   = "currency"
+	AuditTargetQuarantine = "quarantined_price"
+)
+
+// AuditEvent records one change made through the API. Before and After are
+// JSON snapshots of the target; Before is nil for creations.
+type AuditEvent struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	Actor      string    `json:"actor" gorm:"not null"`
+	Action     string    `json:"action" gorm:"not null"`
+	TargetType string    `json:"target_type" gorm:"not null"`
+	TargetID   string    `json:"target_id" gorm:"not null"`
+	Before     *string   `json:"before"`
+	After      *string   `json:"after"`
+	RequestID  string    `json:"request_id" gorm:"not null"`
+	ClientIP   string    `json:"client_ip" gorm:"not null"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// AuditFilter selects audit events created in [From, To); zero fields match
+// everything. Events are returned newest first, at most Limit of them.
+type AuditFilter struct {
+	Actor      string
+	Action     string
+	TargetType string
+	TargetID   string
+	From       time.Time
+	To         time.Time
+	Limit      int
+}
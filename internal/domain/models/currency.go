@@ -3,27 +3,87 @@ package models
 import (
 	"time"
 
+	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
 )
 
-type Currency struct {
-	ID        uint           `json:"id" gorm:"primaryKey"`
-	Symbol    string         `json:"symbol" gorm:"uniqueIndex;not null"`
-	ApiID     string         `json:"api_id" gorm:"not null"`
-	Interval  int            `json:"interval" gorm:"not null;default:60"`
-	IsActive  bool           `json:"is_active" gorm:"default:true"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+const DefaultPrecision = 8
+
+// Currency lifecycle states. Only active currencies are fetched; paused ones
+// are expected to resume, archived ones are kept for their history. A deleted
+// currency is soft-deleted and hidden until it is restored.
+const (
+	CurrencyStatusActive   = "active"
+	CurrencyStatusPaused   = "paused"
+	CurrencyStatusArchived = "archived"
+	CurrencyStatusDeleted  = "deleted"
+)
+
+// AnonymousActor is recorded when a change does not say who made it.
+const AnonymousActor = "anonymous"
+
+var currencyTransitions = map[string][]string{
+	CurrencyStatusActive:   {CurrencyStatusPaused, CurrencyStatusArchived, CurrencyStatusDeleted},
+	CurrencyStatusPaused:   {CurrencyStatusActive, CurrencyStatusArchived, CurrencyStatusDeleted},
+	CurrencyStatusArchived: {CurrencyStatusActive, CurrencyStatusPaused, CurrencyStatusDeleted},
+	CurrencyStatusDeleted:  {CurrencyStatusPaused},
 }
 
-type Price struct {
+// CanTransition reports whether a currency may move from one state to
+// another. A restored currency comes back paused.
+func CanTransition(from, to string) bool {
+	for _, allowed := range currencyTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+type Currency struct {
 	ID         uint           `json:"id" gorm:"primaryKey"`
-	CurrencyID uint           `json:"currency_id" gorm:"not null"`
-	Price      float64        `json:"price" gorm:"not null"`
-	Timestamp  time.Time      `json:"timestamp" gorm:"not null;index"`
+	Symbol     string         `json:"symbol" gorm:"uniqueIndex;not null"`
+	ApiID      string         `json:"api_id" gorm:"not null"`
+	Interval   int            `json:"interval" gorm:"not null;default:60"`
+	StaleAfter int            `json:"stale_after" gorm:"not null;default:0"`
+	Precision  int            `json:"precision" gorm:"not null;default:8"`
+	Status     string         `json:"status" gorm:"not null;default:active;index"`
 	CreatedAt  time.Time      `json:"created_at"`
 	UpdatedAt  time.Time      `json:"updated_at"`
 	DeletedAt  gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
-	Currency   Currency       `json:"currency,omitempty" gorm:"foreignKey:CurrencyID"`
+}
+
+func (c *Currency) IsActive() bool {
+	return c.Status == CurrencyStatusActive
+}
+
+// StaleThreshold returns how old the latest price may get before it is
+// considered stale. Without an explicit StaleAfter it is a multiple of Interval.
+func (c *Currency) StaleThreshold(multiplier int) time.Duration {
+	if c.StaleAfter > 0 {
+		return time.Duration(c.StaleAfter) * time.Second
+	}
+	return time.Duration(c.Interval*multiplier) * time.Second
+}
+
+// CurrencyTransition records one lifecycle change of a currency.
+type CurrencyTransition struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	CurrencyID uint      `json:"currency_id" gorm:"not null;index"`
+	FromStatus string    `json:"from_status" gorm:"not null"`
+	ToStatus   string    `json:"to_status" gorm:"not null"`
+	Actor      string    `json:"actor" gorm:"not null"`
+	Reason     string    `json:"reason" gorm:"not null"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type Price struct {
+	ID         uint            `json:"id" gorm:"primaryKey"`
+	CurrencyID uint            `json:"currency_id" gorm:"not null"`
+	Price      decimal.Decimal `json:"price" gorm:"type:numeric(38,18);not null"`
+	Timestamp  time.Time       `json:"timestamp" gorm:"not null;index"`
+	CreatedAt  time.Time       `json:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt  `json:"deleted_at,omitempty" gorm:"index"`
+	Currency   Currency        `json:"currency,omitempty" gorm:"foreignKey:CurrencyID"`
 }
@@ -0,0 +1,14 @@
+package models
+
+type ConflictAction string
+
+const (
+	ConflictSkip   ConflictAction = "skip"
+	ConflictUpdate ConflictAction = "update"
+)
+
+type BatchResult struct {
+	Inserted int `json:"inserted"`
+	Updated  int `json:"updated"`
+	Skipped  int `json:"skipped"`
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// PriceEventVersion is the payload version written by this build. Listeners
+// skip events with any other version, so the format can change without
+// breaking processes that have not been upgraded yet.
+const PriceEventVersion = 1
+
+type PriceEventType string
+
+const (
+	// PriceEventCreated carries the single price that was inserted.
+	PriceEventCreated PriceEventType = "price.created"
+	// PriceEventChanged reports that a batch changed prices of a currency
+	// without listing them.
+	PriceEventChanged PriceEventType = "prices.changed"
+)
+
+type PriceEvent struct {
+	Version    int              `json:"v"`
+	Type       PriceEventType   `json:"type"`
+	CurrencyID uint             `json:"currency_id"`
+	Price      *decimal.Decimal `json:"price,omitempty"`
+	Timestamp  *time.Time       `json:"timestamp,omitempty"`
+}
+
+func NewPriceCreatedEvent(price *Price) PriceEvent {
+	value, timestamp := price.Price, price.Timestamp
+	return PriceEvent{
+		Version:    PriceEventVersion,
+		Type:       PriceEventCreated,
+		CurrencyID: price.CurrencyID,
+		Price:      &value,
+		Timestamp:  &timestamp,
+	}
+}
+
+func NewPricesChangedEvent(currencyID uint) PriceEvent {
+	return PriceEvent{
+		Version:    PriceEventVersion,
+		Type:       PriceEventChanged,
+		CurrencyID: currencyID,
+	}
+}
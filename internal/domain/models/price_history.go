@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// PriceCursor is a position in a price history: the timestamp and ID of a
+// row, which together order prices uniquely.
+type PriceCursor struct {
+	Timestamp time.Time
+	ID        uint
+}
+
+// PriceHistoryQuery selects one page of raw prices in [From, To), ordered by
+// (timestamp, id) ascending or, with Descending, descending. After, when set,
+// skips every row up to and including it in that order. A non-zero Step keeps
+// only the first price of each Step-long bucket aligned to CandleOrigin.
+// Limit must be positive.
+type PriceHistoryQuery struct {
+	From       time.Time
+	To         time.Time
+	After      *PriceCursor
+	Descending bool
+	Step       time.Duration
+	Limit      int
+}
+
+// ByBuckets turns After into a time bound when Step is set. Skipping whole
+// buckets keeps a bucket from being split across pages, which would make its
+// remainder pick a different first price.
+func (q PriceHistoryQuery) ByBuckets() PriceHistoryQuery {
+	if q.Step <= 0 || q.After == nil {
+		return q
+	}
+	bucket := CandleBucket(q.After.Timestamp, q.Step)
+	if q.Descending {
+		if bucket.Before(q.To) {
+			q.To = bucket
+		}
+	} else if next := bucket.Add(q.Step); next.After(q.From) {
+		q.From = next
+	}
+	q.After = nil
+	return q
+}
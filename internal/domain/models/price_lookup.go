@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// PriceLookupMode selects which stored prices answer a point-in-time lookup
+// when no price was recorded at exactly that moment.
+type PriceLookupMode string
+
+const (
+	// PriceLookupPrevious takes the last price at or before the moment.
+	PriceLookupPrevious PriceLookupMode = "previous"
+	// PriceLookupNext takes the first price at or after the moment.
+	PriceLookupNext PriceLookupMode = "next"
+	// PriceLookupNearest takes whichever neighbour is closer in time.
+	PriceLookupNearest PriceLookupMode = "nearest"
+	// PriceLookupLinear interpolates between both neighbours.
+	PriceLookupLinear PriceLookupMode = "linear"
+)
+
+func (m PriceLookupMode) Valid() bool {
+	switch m {
+	case PriceLookupPrevious, PriceLookupNext, PriceLookupNearest, PriceLookupLinear:
+		return true
+	}
+	return false
+}
+
+// PriceKey asks for the price of a currency at a moment.
+type PriceKey struct {
+	CurrencyID uint
+	Timestamp  time.Time
+}
+
+// SurroundingPrices holds the last price at or before a moment and the first
+// one at or after it; either may be nil.
+type SurroundingPrices struct {
+	Before *Price
+	After  *Price
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	QuarantineStatusPending  = "pending"
+	QuarantineStatusApproved = "approved"
+	QuarantineStatusRejected = "rejected"
+)
+
+type Quote struct {
+	Price     decimal.Decimal
+	UpdatedAt time.Time
+}
+
+type QuarantinedPrice struct {
+	ID             uint             `json:"id" gorm:"primaryKey"`
+	CurrencyID     uint             `json:"currency_id" gorm:"not null;index"`
+	Price          decimal.Decimal  `json:"price" gorm:"type:numeric(38,18);not null"`
+	Timestamp      time.Time        `json:"timestamp" gorm:"not null"`
+	ReferencePrice *decimal.Decimal `json:"reference_price,omitempty" gorm:"type:numeric(38,18)"`
+	Rule           string           `json:"rule" gorm:"not null"`
+	Reason         string           `json:"reason" gorm:"not null"`
+	Status         string           `json:"status" gorm:"not null;default:pending;index"`
+	ReviewedAt     *time.Time       `json:"reviewed_at,omitempty"`
+	CreatedAt      time.Time        `json:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+	Currency       Currency         `json:"currency,omitempty" gorm:"foreignKey:CurrencyID"`
+}
@@ -0,0 +1,100 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type Resolution string
+
+const (
+	ResolutionRaw    Resolution = "raw"
+	ResolutionMinute Resolution = "1m"
+	ResolutionHour   Resolution = "1h"
+	ResolutionDay    Resolution = "1d"
+)
+
+// RollupResolutions lists rollup resolutions from finest to coarsest; each one
+// is built from the previous one, the first from raw prices.
+var RollupResolutions = []Resolution{ResolutionMinute, ResolutionHour, ResolutionDay}
+
+func (r Resolution) Duration() time.Duration {
+	switch r {
+	case ResolutionMinute:
+		return time.Minute
+	case ResolutionHour:
+		return time.Hour
+	case ResolutionDay:
+		return 24 * time.Hour
+	}
+	return 0
+}
+
+type PriceRollup struct {
+	CurrencyID  uint            `json:"currency_id"`
+	Bucket      time.Time       `json:"bucket"`
+	Open        decimal.Decimal `json:"open"`
+	High        decimal.Decimal `json:"high"`
+	Low         decimal.Decimal `json:"low"`
+	Close       decimal.Decimal `json:"close"`
+	Avg         decimal.Decimal `json:"avg"`
+	SampleCount int64           `json:"sample_count"`
+	FirstAt     time.Time       `json:"first_at"`
+	LastAt      time.Time       `json:"last_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// CandleOrigin is the Monday midnight UTC that candle buckets are aligned to,
+// so daily and weekly buckets start where people expect.
+var CandleOrigin = time.Date(2000, 1, 3, 0, 0, 0, 0, time.UTC)
+
+type Candle struct {
+	Bucket      time.Time       `json:"bucket"`
+	Open        decimal.Decimal `json:"open"`
+	High        decimal.Decimal `json:"high"`
+	Low         decimal.Decimal `json:"low"`
+	Close       decimal.Decimal `json:"close"`
+	SampleCount int64           `json:"sample_count"`
+}
+
+// CandleBucket returns the start of the candle of length interval holding t.
+func CandleBucket(t time.Time, interval time.Duration) time.Time {
+	offset := t.Sub(CandleOrigin) % interval
+	if offset < 0 {
+		offset += interval
+	}
+	return t.Add(-offset).UTC()
+}
+
+// BuildCandles aggregates prices ordered by timestamp into candles. It backs
+// the storages that cannot aggregate exact decimals themselves.
+func BuildCandles(prices []Price, interval time.Duration) []Candle {
+	var candles []Candle
+	for _, price := range prices {
+		bucket := CandleBucket(price.Timestamp, interval)
+
+		if n := len(candles); n > 0 && candles[n-1].Bucket.Equal(bucket) {
+			candle := &candles[n-1]
+			if price.Price.GreaterThan(candle.High) {
+				candle.High = price.Price
+			}
+			if price.Price.LessThan(candle.Low) {
+				candle.Low = price.Price
+			}
+			candle.Close = price.Price
+			candle.SampleCount++
+			continue
+		}
+
+		candles = append(candles, Candle{
+			Bucket:      bucket,
+			Open:        price.Price,
+			High:        price.Price,
+			Low:         price.Price,
+			Close:       price.Price,
+			SampleCount: 1,
+		})
+	}
+	return candles
+}
@@ -2,26 +2,130 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"time"
+
+	"crypto-price-tracker-app/internal/domain/models"
+
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrNotFound  = errors.New("record not found")
+	ErrDuplicate = errors.New("record already exists")
+	// ErrConflict means the record changed since it was read.
+	ErrConflict = errors.New("record was modified concurrently")
 )
 
 type CurrencyRepository interface {
-	Create(ctx context.Context, currency interface{}) error
-	GetBySymbol(ctx context.Context, symbol string) (interface{}, error)
-	GetAllActive(ctx context.Context) ([]interface{}, error)
-	Update(ctx context.Context, currency interface{}) error
-	Delete(ctx context.Context, symbol string) error
-	Deactivate(ctx context.Context, symbol string) error
+	Create(ctx context.Context, currency *models.Currency) error
+	GetBySymbol(ctx context.Context, symbol string) (*models.Currency, error)
+	// GetBySymbols returns the currencies among symbols that GetBySymbol
+	// would find, in no particular order.
+	GetBySymbols(ctx context.Context, symbols []string) ([]models.Currency, error)
+	// GetDeletedBySymbol finds a currency in the deleted state, which
+	// GetBySymbol hides.
+	GetDeletedBySymbol(ctx context.Context, symbol string) (*models.Currency, error)
+	GetAllActive(ctx context.Context) ([]models.Currency, error)
+	// Update stores the settings of currency (api_id and interval) and sets
+	// its UpdatedAt. It fails with ErrConflict when the currency is no longer
+	// in currency.Status, so a change never overwrites a concurrent
+	// transition.
+	Update(ctx context.Context, currency *models.Currency) error
+	// Transition moves a currency from transition.FromStatus to ToStatus and
+	// records it in the same transaction. It fails with ErrConflict when the
+	// currency is no longer in FromStatus.
+	Transition(ctx context.Context, transition *models.CurrencyTransition) error
+	ListTransitions(ctx context.Context, currencyID uint) ([]models.CurrencyTransition, error)
 }
 
 type PriceRepository interface {
-	Create(ctx context.Context, price interface{}) error
-	GetByCurrencyAndTime(ctx context.Context, currencyID uint, timestamp time.Time) (interface{}, error)
-	GetNearestPrice(ctx context.Context, currencyID uint, timestamp time.Time) (interface{}, error)
-	GetLatestPrice(ctx context.Context, currencyID uint) (interface{}, error)
-	GetPriceHistory(ctx context.Context, currencyID uint, from, to time.Time) ([]interface{}, error)
+	Create(ctx context.Context, price *models.Price) error
+	CreateBatch(ctx context.Context, prices []models.Price, onConflict models.ConflictAction) (*models.BatchResult, error)
+	GetByCurrencyAndTime(ctx context.Context, currencyID uint, timestamp time.Time) (*models.Price, error)
+	GetNearestPrice(ctx context.Context, currencyID uint, timestamp time.Time) (*models.Price, error)
+	// GetSurroundingPrices returns the last price at or before timestamp and
+	// the first one at or after it; either may be nil, but not both.
+	GetSurroundingPrices(ctx context.Context, currencyID uint, timestamp time.Time) (before, after *models.Price, err error)
+	// GetSurroundingPricesBatch answers GetSurroundingPrices for every key
+	// with a few queries. The result follows the order of keys; a key
+	// without any price gets both prices nil rather than an error.
+	GetSurroundingPricesBatch(ctx context.Context, keys []models.PriceKey) ([]models.SurroundingPrices, error)
+	GetLatestPrice(ctx context.Context, currencyID uint) (*models.Price, error)
+	// GetLatestPrices returns the latest price of each of currencyIDs that
+	// has one, ordered by currency ID.
+	GetLatestPrices(ctx context.Context, currencyIDs []uint) ([]models.Price, error)
+	GetPriceHistory(ctx context.Context, currencyID uint, from, to time.Time) ([]models.Price, error)
+	// GetPriceHistoryPage reads raw prices only; ranges kept just as rollups
+	// come back empty.
+	GetPriceHistoryPage(ctx context.Context, currencyID uint, query models.PriceHistoryQuery) ([]models.Price, error)
+	GetCandles(ctx context.Context, currencyID uint, from, to time.Time, interval time.Duration) ([]models.Candle, error)
+	// DeleteByCurrency permanently removes every price of a currency,
+	// including its rollups.
+	DeleteByCurrency(ctx context.Context, currencyID uint) (int64, error)
+}
+
+type QuarantineRepository interface {
+	Create(ctx context.Context, sample *models.QuarantinedPrice) error
+	GetByID(ctx context.Context, id uint) (*models.QuarantinedPrice, error)
+	List(ctx context.Context, status string) ([]models.QuarantinedPrice, error)
+	// UpdateStatus reviews a pending sample. It fails with ErrConflict when
+	// the sample does not exist or is no longer pending.
+	UpdateStatus(ctx context.Context, id uint, status string, reviewedAt time.Time) error
+}
+
+// AuditRepository stores the audit log. Services create an event in the same
+// unit of work as the change it describes, so neither is saved without the
+// other.
+type AuditRepository interface {
+	Create(ctx context.Context, event *models.AuditEvent) error
+	List(ctx context.Context, filter models.AuditFilter) ([]models.AuditEvent, error)
+}
+
+type PartitionManager interface {
+	EnsurePartitions(ctx context.Context, from time.Time, monthsAhead int) ([]string, error)
+	ExpirePartitions(ctx context.Context, cutoff time.Time, drop bool) ([]string, error)
+}
+
+type RollupRepository interface {
+	Refresh(ctx context.Context, resolution models.Resolution, lookback time.Duration) (int64, error)
+	Prune(ctx context.Context, resolution models.Resolution, before time.Time) (int64, error)
+}
+
+// PriceArchiveRepository reads and removes prices being archived. Months are
+// UTC calendar months identified by their first instant.
+type PriceArchiveRepository interface {
+	// ListArchivable returns the groups of live prices older than before,
+	// ordered by currency and month.
+	ListArchivable(ctx context.Context, before time.Time) ([]models.ArchiveGroup, error)
+	GetMonth(ctx context.Context, currencyID uint, month time.Time) ([]models.Price, error)
+	// DeleteArchived permanently removes the given prices of one group.
+	DeleteArchived(ctx context.Context, currencyID uint, month time.Time, ids []uint) (int64, error)
+}
+
+// ArchiveStore keeps archived prices outside the database.
+type ArchiveStore interface {
+	// Write stores prices and fills in File, Format, Rows and SHA256 of entry.
+	Write(ctx context.Context, entry *models.ArchiveEntry, prices []models.Price) error
+	// Read loads the prices of entry after checking its checksum.
+	Read(ctx context.Context, entry models.ArchiveEntry) ([]models.Price, error)
+	LoadManifest(ctx context.Context) ([]models.ArchiveEntry, error)
+	SaveManifest(ctx context.Context, entries []models.ArchiveEntry) error
+	// Lock waits until no other user of the store, in this or another
+	// process, holds the manifest and returns the function releasing it.
+	// Loading, changing and saving the manifest must happen under the lock.
+	Lock(ctx context.Context) (func(), error)
+}
+
+// PriceEventSource delivers price events published by any process sharing
+// the database.
+type PriceEventSource interface {
+	// Listen calls handle for every event until ctx is done. resync is called
+	// after every (re)connect, since events sent while disconnected are lost.
+	Listen(ctx context.Context, handle func(models.PriceEvent), resync func()) error
 }
 
 type PriceAPI interface {
-	GetPrice(ctx context.Context, symbol string) (float64, error)
+	GetPrice(ctx context.Context, symbol string) (decimal.Decimal, error)
+	GetQuote(ctx context.Context, symbol string) (*models.Quote, error)
 }
@@ -0,0 +1,863 @@
+// Package repositorytest holds the conformance suite every storage backend of
+// repository.CurrencyRepository, repository.PriceRepository,
+// repository.QuarantineRepository, repository.AuditRepository and
+// repository.UnitOfWork must pass.
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"crypto-price-tracker-app/internal/domain/models"
+	"crypto-price-tracker-app/internal/domain/repository"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Repositories struct {
+	Currencies repository.CurrencyRepository
+	Prices     repository.PriceRepository
+	Quarantine repository.QuarantineRepository
+	Audit      repository.AuditRepository
+	UnitOfWork repository.UnitOfWork
+}
+
+// Run executes the suite. newRepositories is called once per subtest and must
+// return repositories backed by empty storage.
+func Run(t *testing.T, newRepositories func(t *testing.T) Repositories) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, repos Repositories)
+	}{
+		{"currency create and lookup", testCurrencyCreateAndLookup},
+		{"currency duplicate symbol", testCurrencyDuplicateSymbol},
+		{"currency not found", testCurrencyNotFound},
+		{"currency lookup by symbols", testCurrencyLookupBySymbols},
+		{"currency update", testCurrencyUpdate},
+		{"currency transition", testCurrencyTransition},
+		{"currency delete and restore", testCurrencyDeleteAndRestore},
+		{"currency audit", testCurrencyAudit},
+		{"price create and exact lookup", testPriceCreateAndExactLookup},
+		{"price duplicate timestamp", testPriceDuplicateTimestamp},
+		{"price not found", testPriceNotFound},
+		{"price nearest", testPriceNearest},
+		{"price surrounding", testPriceSurrounding},
+		{"price surrounding batch", testPriceSurroundingBatch},
+		{"price latest", testPriceLatest},
+		{"price latest of many", testPriceLatestOfMany},
+		{"price history range", testPriceHistoryRange},
+		{"price history page", testPriceHistoryPage},
+		{"price history page step", testPriceHistoryPageStep},
+		{"price batch", testPriceBatch},
+		{"price batch chunks", testPriceBatchChunks},
+		{"price candles", testPriceCandles},
+		{"price delete by currency", testPriceDeleteByCurrency},
+		{"quarantine review", testQuarantineReview},
+		{"unit of work commit", testUnitOfWorkCommit},
+		{"unit of work rollback", testUnitOfWorkRollback},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.run(t, newRepositories(t))
+		})
+	}
+}
+
+var base = time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+
+func createCurrency(t *testing.T, repos Repositories, symbol string) *models.Currency {
+	t.Helper()
+	currency := &models.Currency{Symbol: symbol, ApiID: symbol + "-api", Interval: 60, Precision: 8, Status: models.CurrencyStatusActive}
+	require.NoError(t, repos.Currencies.Create(context.Background(), currency))
+	require.NotZero(t, currency.ID)
+	return currency
+}
+
+func createPrice(t *testing.T, repos Repositories, currencyID uint, value string, timestamp time.Time) *models.Price {
+	t.Helper()
+	price := &models.Price{CurrencyID: currencyID, Price: decimal.RequireFromString(value), Timestamp: timestamp}
+	require.NoError(t, repos.Prices.Create(context.Background(), price))
+	require.NotZero(t, price.ID)
+	return price
+}
+
+func assertPrice(t *testing.T, want string, wantAt time.Time, got *models.Price) {
+	t.Helper()
+	if assert.NotNil(t, got) {
+		assert.True(t, decimal.RequireFromString(want).Equal(got.Price), "price: want %s, got %s", want, got.Price)
+		assert.True(t, wantAt.Equal(got.Timestamp), "timestamp: want %s, got %s", wantAt, got.Timestamp)
+	}
+}
+
+func testCurrencyCreateAndLookup(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	created := createCurrency(t, repos, "BTC")
+
+	found, err := repos.Currencies.GetBySymbol(ctx, "BTC")
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, found.ID)
+	assert.Equal(t, "BTC-api", found.ApiID)
+	assert.Equal(t, 60, found.Interval)
+	assert.Equal(t, models.CurrencyStatusActive, found.Status)
+	assert.False(t, found.CreatedAt.IsZero())
+}
+
+func testCurrencyDuplicateSymbol(t *testing.T, repos Repositories) {
+	createCurrency(t, repos, "BTC")
+
+	err := repos.Currencies.Create(context.Background(), &models.Currency{Symbol: "BTC", ApiID: "other", Interval: 60})
+	assert.ErrorIs(t, err, repository.ErrDuplicate)
+}
+
+func testCurrencyNotFound(t *testing.T, repos Repositories) {
+	currency, err := repos.Currencies.GetBySymbol(context.Background(), "MISSING")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	assert.Nil(t, currency)
+}
+
+func testCurrencyLookupBySymbols(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	btc := createCurrency(t, repos, "BTC")
+	eth := createCurrency(t, repos, "ETH")
+	createCurrency(t, repos, "SOL")
+
+	currencies, err := repos.Currencies.GetBySymbols(ctx, []string{"ETH", "BTC", "MISSING"})
+	require.NoError(t, err)
+	ids := make([]uint, len(currencies))
+	for i, currency := range currencies {
+		ids[i] = currency.ID
+	}
+	assert.ElementsMatch(t, []uint{btc.ID, eth.ID}, ids)
+
+	currencies, err = repos.Currencies.GetBySymbols(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, currencies)
+}
+
+func testCurrencyUpdate(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	currency := createCurrency(t, repos, "BTC")
+
+	currency.Interval = 300
+	currency.ApiID = "bitcoin"
+	currency.Precision = 2
+	require.NoError(t, repos.Currencies.Update(ctx, currency))
+
+	found, err := repos.Currencies.GetBySymbol(ctx, "BTC")
+	require.NoError(t, err)
+	assert.Equal(t, 300, found.Interval)
+	assert.Equal(t, "bitcoin", found.ApiID)
+	// Only the settings are written back.
+	assert.Equal(t, 8, found.Precision)
+	assert.Equal(t, models.CurrencyStatusActive, found.Status)
+
+	// A copy read before a transition no longer matches the stored state,
+	// so writing it back would undo the transition.
+	stale := *found
+	transition(t, repos, currency, models.CurrencyStatusPaused)
+	stale.Interval = 600
+	assert.ErrorIs(t, repos.Currencies.Update(ctx, &stale), repository.ErrConflict)
+
+	transition(t, repos, currency, models.CurrencyStatusDeleted)
+	deleted := *currency
+	deleted.Interval = 600
+	assert.ErrorIs(t, repos.Currencies.Update(ctx, &deleted), repository.ErrConflict)
+
+	found, err = repos.Currencies.GetDeletedBySymbol(ctx, "BTC")
+	require.NoError(t, err)
+	assert.Equal(t, 300, found.Interval)
+}
+
+func transition(t *testing.T, repos Repositories, currency *models.Currency, to string) {
+	t.Helper()
+	require.NoError(t, repos.Currencies.Transition(context.Background(), &models.CurrencyTransition{
+		CurrencyID: currency.ID,
+		FromStatus: currency.Status,
+		ToStatus:   to,
+		Actor:      "tester",
+		Reason:     "testing",
+	}))
+	currency.Status = to
+}
+
+func testCurrencyTransition(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	btc := createCurrency(t, repos, "BTC")
+	createCurrency(t, repos, "ETH")
+
+	transition(t, repos, btc, models.CurrencyStatusPaused)
+
+	active, err := repos.Currencies.GetAllActive(ctx)
+	require.NoError(t, err)
+	if assert.Len(t, active, 1) {
+		assert.Equal(t, "ETH", active[0].Symbol)
+	}
+
+	found, err := repos.Currencies.GetBySymbol(ctx, "BTC")
+	require.NoError(t, err)
+	assert.Equal(t, models.CurrencyStatusPaused, found.Status)
+
+	// A transition from a state the currency has already left is rejected
+	// and not recorded.
+	err = repos.Currencies.Transition(ctx, &models.CurrencyTransition{
+		CurrencyID: btc.ID,
+		FromStatus: models.CurrencyStatusActive,
+		ToStatus:   models.CurrencyStatusArchived,
+		Actor:      "tester",
+	})
+	assert.ErrorIs(t, err, repository.ErrConflict)
+
+	transitions, err := repos.Currencies.ListTransitions(ctx, btc.ID)
+	require.NoError(t, err)
+	require.Len(t, transitions, 1)
+	assert.Equal(t, models.CurrencyStatusActive, transitions[0].FromStatus)
+	assert.Equal(t, models.CurrencyStatusPaused, transitions[0].ToStatus)
+	assert.Equal(t, "tester", transitions[0].Actor)
+	assert.Equal(t, "testing", transitions[0].Reason)
+	assert.False(t, transitions[0].CreatedAt.IsZero())
+}
+
+func auditEvent(action, target string) *models.AuditEvent {
+	after := `{"symbol":"` + target + `"}`
+	return &models.AuditEvent{
+		Actor:      "tester",
+		Action:     action,
+		TargetType: models.AuditTargetCurrency,
+		TargetID:   target,
+		After:      &after,
+		RequestID:  "req-" + action,
+		ClientIP:   "127.0.0.1",
+	}
+}
+
+// audited runs change in a unit of work that records event first, so a
+// failing change has to roll the event back.
+func audited(ctx context.Context, repos Repositories, event *models.AuditEvent, change func(tx repository.Repositories) error) error {
+	return repos.UnitOfWork.WithTx(ctx, func(tx repository.Repositories) error {
+		if err := tx.Audit.Create(ctx, event); err != nil {
+			return err
+		}
+		return change(tx)
+	})
+}
+
+func testCurrencyAudit(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+
+	btc := &models.Currency{Symbol: "BTC", ApiID: "bitcoin", Interval: 60, Status: models.CurrencyStatusActive}
+	require.NoError(t, audited(ctx, repos, auditEvent(models.AuditActionCurrencyAdd, "BTC"), func(tx repository.Repositories) error {
+		return tx.Currencies.Create(ctx, btc)
+	}))
+	require.NoError(t, audited(ctx, repos, auditEvent(models.AuditActionCurrencyStatus, "BTC"), func(tx repository.Repositories) error {
+		return tx.Currencies.Transition(ctx, &models.CurrencyTransition{CurrencyID: btc.ID, FromStatus: models.CurrencyStatusActive, ToStatus: models.CurrencyStatusPaused, Actor: "tester"})
+	}))
+
+	// Failed changes leave no audit event behind.
+	duplicate := &models.Currency{Symbol: "BTC", ApiID: "bitcoin", Interval: 60, Status: models.CurrencyStatusActive}
+	err := audited(ctx, repos, auditEvent("duplicate", "BTC"), func(tx repository.Repositories) error {
+		return tx.Currencies.Create(ctx, duplicate)
+	})
+	assert.ErrorIs(t, err, repository.ErrDuplicate)
+	err = audited(ctx, repos, auditEvent("conflict", "BTC"), func(tx repository.Repositories) error {
+		return tx.Currencies.Transition(ctx, &models.CurrencyTransition{CurrencyID: btc.ID, FromStatus: models.CurrencyStatusActive, ToStatus: models.CurrencyStatusArchived, Actor: "tester"})
+	})
+	assert.ErrorIs(t, err, repository.ErrConflict)
+
+	createCurrency(t, repos, "ETH")
+
+	events, err := repos.Audit.List(ctx, models.AuditFilter{})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, models.AuditActionCurrencyStatus, events[0].Action)
+	assert.Equal(t, models.AuditActionCurrencyAdd, events[1].Action)
+	assert.Equal(t, "tester", events[1].Actor)
+	assert.Equal(t, "BTC", events[1].TargetID)
+	assert.Equal(t, "req-currency.add", events[1].RequestID)
+	assert.Equal(t, "127.0.0.1", events[1].ClientIP)
+	assert.Nil(t, events[1].Before)
+	if assert.NotNil(t, events[1].After) {
+		assert.JSONEq(t, `{"symbol":"BTC"}`, *events[1].After)
+	}
+	assert.NotZero(t, events[1].ID)
+	assert.False(t, events[1].CreatedAt.IsZero())
+
+	events, err = repos.Audit.List(ctx, models.AuditFilter{Action: models.AuditActionCurrencyAdd, TargetID: "BTC"})
+	require.NoError(t, err)
+	assert.Len(t, events, 1)
+
+	events, err = repos.Audit.List(ctx, models.AuditFilter{Limit: 1})
+	require.NoError(t, err)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, models.AuditActionCurrencyStatus, events[0].Action)
+	}
+
+	events, err = repos.Audit.List(ctx, models.AuditFilter{To: time.Now().Add(-time.Hour)})
+	require.NoError(t, err)
+	assert.Empty(t, events)
+}
+
+func testCurrencyDeleteAndRestore(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	btc := createCurrency(t, repos, "BTC")
+
+	transition(t, repos, btc, models.CurrencyStatusDeleted)
+
+	_, err := repos.Currencies.GetBySymbol(ctx, "BTC")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	active, err := repos.Currencies.GetAllActive(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, active)
+
+	deleted, err := repos.Currencies.GetDeletedBySymbol(ctx, "BTC")
+	require.NoError(t, err)
+	assert.Equal(t, btc.ID, deleted.ID)
+
+	// The symbol stays reserved by the deleted row.
+	err = repos.Currencies.Create(ctx, &models.Currency{Symbol: "BTC", ApiID: "bitcoin", Interval: 60})
+	assert.ErrorIs(t, err, repository.ErrDuplicate)
+
+	transition(t, repos, btc, models.CurrencyStatusPaused)
+
+	found, err := repos.Currencies.GetBySymbol(ctx, "BTC")
+	require.NoError(t, err)
+	assert.Equal(t, models.CurrencyStatusPaused, found.Status)
+
+	_, err = repos.Currencies.GetDeletedBySymbol(ctx, "BTC")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	transitions, err := repos.Currencies.ListTransitions(ctx, btc.ID)
+	require.NoError(t, err)
+	assert.Len(t, transitions, 2)
+}
+
+func testPriceCreateAndExactLookup(t *testing.T, repos Repositories) {
+	currency := createCurrency(t, repos, "BTC")
+	createPrice(t, repos, currency.ID, "42000.123456789012345678", base)
+
+	found, err := repos.Prices.GetByCurrencyAndTime(context.Background(), currency.ID, base)
+	require.NoError(t, err)
+	assertPrice(t, "42000.123456789012345678", base, found)
+	assert.Equal(t, currency.ID, found.CurrencyID)
+}
+
+func testPriceDuplicateTimestamp(t *testing.T, repos Repositories) {
+	currency := createCurrency(t, repos, "BTC")
+	createPrice(t, repos, currency.ID, "100", base)
+
+	err := repos.Prices.Create(context.Background(), &models.Price{CurrencyID: currency.ID, Price: decimal.RequireFromString("101"), Timestamp: base})
+	assert.ErrorIs(t, err, repository.ErrDuplicate)
+}
+
+func testPriceNotFound(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	currency := createCurrency(t, repos, "BTC")
+
+	price, err := repos.Prices.GetByCurrencyAndTime(ctx, currency.ID, base)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	assert.Nil(t, price)
+
+	_, err = repos.Prices.GetNearestPrice(ctx, currency.ID, base)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	_, _, err = repos.Prices.GetSurroundingPrices(ctx, currency.ID, base)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	_, err = repos.Prices.GetLatestPrice(ctx, currency.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	history, err := repos.Prices.GetPriceHistory(ctx, currency.ID, base.Add(-time.Hour), base)
+	require.NoError(t, err)
+	assert.Empty(t, history)
+}
+
+func testPriceNearest(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	currency := createCurrency(t, repos, "BTC")
+	other := createCurrency(t, repos, "ETH")
+	createPrice(t, repos, currency.ID, "100", base)
+	createPrice(t, repos, currency.ID, "110", base.Add(10*time.Minute))
+	createPrice(t, repos, other.ID, "1", base.Add(5*time.Minute))
+
+	tests := []struct {
+		name   string
+		at     time.Time
+		want   string
+		wantAt time.Time
+	}{
+		{"exact", base, "100", base},
+		{"prefers before", base.Add(9 * time.Minute), "100", base},
+		{"after the last", base.Add(time.Hour), "110", base.Add(10 * time.Minute)},
+		{"before the first", base.Add(-time.Hour), "100", base},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price, err := repos.Prices.GetNearestPrice(ctx, currency.ID, tt.at)
+			require.NoError(t, err)
+			assertPrice(t, tt.want, tt.wantAt, price)
+		})
+	}
+}
+
+func testPriceSurrounding(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	currency := createCurrency(t, repos, "BTC")
+	other := createCurrency(t, repos, "ETH")
+	createPrice(t, repos, currency.ID, "100", base)
+	createPrice(t, repos, currency.ID, "110", base.Add(10*time.Minute))
+	createPrice(t, repos, other.ID, "1", base.Add(5*time.Minute))
+
+	before, after, err := repos.Prices.GetSurroundingPrices(ctx, currency.ID, base.Add(4*time.Minute))
+	require.NoError(t, err)
+	assertPrice(t, "100", base, before)
+	assertPrice(t, "110", base.Add(10*time.Minute), after)
+
+	before, after, err = repos.Prices.GetSurroundingPrices(ctx, currency.ID, base)
+	require.NoError(t, err)
+	assertPrice(t, "100", base, before)
+	assertPrice(t, "100", base, after)
+
+	before, after, err = repos.Prices.GetSurroundingPrices(ctx, currency.ID, base.Add(-time.Minute))
+	require.NoError(t, err)
+	assert.Nil(t, before)
+	assertPrice(t, "100", base, after)
+
+	before, after, err = repos.Prices.GetSurroundingPrices(ctx, currency.ID, base.Add(time.Hour))
+	require.NoError(t, err)
+	assertPrice(t, "110", base.Add(10*time.Minute), before)
+	assert.Nil(t, after)
+}
+
+func testPriceSurroundingBatch(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	currency := createCurrency(t, repos, "BTC")
+	other := createCurrency(t, repos, "ETH")
+	empty := createCurrency(t, repos, "SOL")
+	createPrice(t, repos, currency.ID, "100", base)
+	createPrice(t, repos, currency.ID, "110", base.Add(10*time.Minute))
+	createPrice(t, repos, other.ID, "1", base.Add(5*time.Minute))
+
+	prices, err := repos.Prices.GetSurroundingPricesBatch(ctx, []models.PriceKey{
+		{CurrencyID: currency.ID, Timestamp: base.Add(4 * time.Minute)},
+		{CurrencyID: other.ID, Timestamp: base.Add(4 * time.Minute)},
+		{CurrencyID: currency.ID, Timestamp: base},
+		{CurrencyID: empty.ID, Timestamp: base},
+		{CurrencyID: currency.ID, Timestamp: base.Add(time.Hour)},
+		{CurrencyID: currency.ID, Timestamp: base.Add(4 * time.Minute)},
+	})
+	require.NoError(t, err)
+	require.Len(t, prices, 6)
+
+	assertPrice(t, "100", base, prices[0].Before)
+	assertPrice(t, "110", base.Add(10*time.Minute), prices[0].After)
+	assert.Nil(t, prices[1].Before)
+	assertPrice(t, "1", base.Add(5*time.Minute), prices[1].After)
+	assertPrice(t, "100", base, prices[2].Before)
+	assertPrice(t, "100", base, prices[2].After)
+	assert.Nil(t, prices[3].Before)
+	assert.Nil(t, prices[3].After)
+	assertPrice(t, "110", base.Add(10*time.Minute), prices[4].Before)
+	assert.Nil(t, prices[4].After)
+	assertPrice(t, "100", base, prices[5].Before)
+	assertPrice(t, "110", base.Add(10*time.Minute), prices[5].After)
+
+	prices, err = repos.Prices.GetSurroundingPricesBatch(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, prices)
+}
+
+func testPriceLatest(t *testing.T, repos Repositories) {
+	currency := createCurrency(t, repos, "BTC")
+	createPrice(t, repos, currency.ID, "110", base.Add(10*time.Minute))
+	createPrice(t, repos, currency.ID, "100", base)
+
+	latest, err := repos.Prices.GetLatestPrice(context.Background(), currency.ID)
+	require.NoError(t, err)
+	assertPrice(t, "110", base.Add(10*time.Minute), latest)
+}
+
+func testPriceLatestOfMany(t *testing.T, repos Repositories) {
+	btc := createCurrency(t, repos, "BTC")
+	eth := createCurrency(t, repos, "ETH")
+	empty := createCurrency(t, repos, "SOL")
+	other := createCurrency(t, repos, "XRP")
+	createPrice(t, repos, eth.ID, "3000", base)
+	createPrice(t, repos, eth.ID, "3100", base.Add(time.Minute))
+	createPrice(t, repos, btc.ID, "110", base.Add(10*time.Minute))
+	createPrice(t, repos, btc.ID, "100", base)
+	createPrice(t, repos, other.ID, "1", base)
+
+	prices, err := repos.Prices.GetLatestPrices(context.Background(), []uint{eth.ID, btc.ID, empty.ID})
+	require.NoError(t, err)
+	require.Len(t, prices, 2)
+	assert.Equal(t, btc.ID, prices[0].CurrencyID)
+	assertPrice(t, "110", base.Add(10*time.Minute), &prices[0])
+	assert.Equal(t, eth.ID, prices[1].CurrencyID)
+	assertPrice(t, "3100", base.Add(time.Minute), &prices[1])
+
+	prices, err = repos.Prices.GetLatestPrices(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Empty(t, prices)
+}
+
+func testPriceHistoryRange(t *testing.T, repos Repositories) {
+	currency := createCurrency(t, repos, "BTC")
+	other := createCurrency(t, repos, "ETH")
+	for i, value := range []string{"100", "101", "102", "103"} {
+		createPrice(t, repos, currency.ID, value, base.Add(time.Duration(3-i)*time.Minute))
+	}
+	createPrice(t, repos, other.ID, "1", base.Add(time.Minute))
+
+	// Both bounds are inclusive and results are ordered oldest first.
+	history, err := repos.Prices.GetPriceHistory(context.Background(), currency.ID, base.Add(time.Minute), base.Add(2*time.Minute))
+	require.NoError(t, err)
+	if assert.Len(t, history, 2) {
+		assertPrice(t, "102", base.Add(time.Minute), &history[0])
+		assertPrice(t, "101", base.Add(2*time.Minute), &history[1])
+	}
+}
+
+func testPriceBatch(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	currency := createCurrency(t, repos, "BTC")
+	createPrice(t, repos, currency.ID, "100", base)
+	createPrice(t, repos, currency.ID, "101", base.Add(time.Minute))
+
+	batch := []models.Price{
+		{CurrencyID: currency.ID, Price: decimal.RequireFromString("100"), Timestamp: base},
+		{CurrencyID: currency.ID, Price: decimal.RequireFromString("111"), Timestamp: base.Add(time.Minute)},
+		{CurrencyID: currency.ID, Price: decimal.RequireFromString("102"), Timestamp: base.Add(2 * time.Minute)},
+		{CurrencyID: currency.ID, Price: decimal.RequireFromString("103"), Timestamp: base.Add(2 * time.Minute)},
+	}
+
+	result, err := repos.Prices.CreateBatch(ctx, batch, models.ConflictSkip)
+	require.NoError(t, err)
+	assert.Equal(t, models.BatchResult{Inserted: 1, Skipped: 3}, *result)
+
+	price, err := repos.Prices.GetByCurrencyAndTime(ctx, currency.ID, base.Add(time.Minute))
+	require.NoError(t, err)
+	assertPrice(t, "101", base.Add(time.Minute), price)
+
+	result, err = repos.Prices.CreateBatch(ctx, batch, models.ConflictUpdate)
+	require.NoError(t, err)
+	assert.Equal(t, models.BatchResult{Updated: 1, Skipped: 3}, *result)
+
+	price, err = repos.Prices.GetByCurrencyAndTime(ctx, currency.ID, base.Add(time.Minute))
+	require.NoError(t, err)
+	assertPrice(t, "111", base.Add(time.Minute), price)
+
+	price, err = repos.Prices.GetByCurrencyAndTime(ctx, currency.ID, base.Add(2*time.Minute))
+	require.NoError(t, err)
+	assertPrice(t, "103", base.Add(2*time.Minute), price)
+
+	_, err = repos.Prices.CreateBatch(ctx, batch, models.ConflictAction("merge"))
+	assert.Error(t, err)
+}
+
+// largeBatch spans three INSERT statements of the postgres backend, which
+// writes at most 1000 rows per statement.
+const largeBatch = 2500
+
+func testPriceBatchChunks(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	currency := createCurrency(t, repos, "BTC")
+
+	batch := make([]models.Price, largeBatch)
+	for i := range batch {
+		batch[i] = models.Price{CurrencyID: currency.ID, Price: decimal.NewFromInt(int64(i + 1)), Timestamp: base.Add(time.Duration(i) * time.Minute)}
+	}
+	// The last row of the first chunk is stored unchanged and the first row of
+	// the second one with another price; the trailing duplicate collapses
+	// into the first row.
+	createPrice(t, repos, currency.ID, "1000", base.Add(999*time.Minute))
+	createPrice(t, repos, currency.ID, "1", base.Add(1000*time.Minute))
+	batch = append(batch, batch[0])
+
+	result, err := repos.Prices.CreateBatch(ctx, batch, models.ConflictSkip)
+	require.NoError(t, err)
+	assert.Equal(t, models.BatchResult{Inserted: largeBatch - 2, Skipped: 3}, *result)
+
+	result, err = repos.Prices.CreateBatch(ctx, batch, models.ConflictUpdate)
+	require.NoError(t, err)
+	assert.Equal(t, models.BatchResult{Updated: 1, Skipped: largeBatch}, *result)
+
+	result, err = repos.Prices.CreateBatch(ctx, batch, models.ConflictUpdate)
+	require.NoError(t, err)
+	assert.Equal(t, models.BatchResult{Skipped: largeBatch + 1}, *result)
+
+	history, err := repos.Prices.GetPriceHistory(ctx, currency.ID, base, base.Add((largeBatch-1)*time.Minute))
+	require.NoError(t, err)
+	if assert.Len(t, history, largeBatch) {
+		assertPrice(t, "1000", base.Add(999*time.Minute), &history[999])
+		assertPrice(t, "1001", base.Add(1000*time.Minute), &history[1000])
+		assertPrice(t, "2500", base.Add((largeBatch-1)*time.Minute), &history[largeBatch-1])
+	}
+}
+
+func testPriceCandles(t *testing.T, repos Repositories) {
+	currency := createCurrency(t, repos, "BTC")
+	for i, value := range []string{"100", "120", "90", "110"} {
+		createPrice(t, repos, currency.ID, value, base.Add(time.Duration(i*20)*time.Minute))
+	}
+
+	candles, err := repos.Prices.GetCandles(context.Background(), currency.ID, base, base.Add(2*time.Hour), time.Hour)
+	require.NoError(t, err)
+	require.Len(t, candles, 2)
+
+	first := candles[0]
+	assert.True(t, base.Equal(first.Bucket), "bucket: got %s", first.Bucket)
+	assert.True(t, decimal.RequireFromString("100").Equal(first.Open))
+	assert.True(t, decimal.RequireFromString("120").Equal(first.High))
+	assert.True(t, decimal.RequireFromString("90").Equal(first.Low))
+	assert.True(t, decimal.RequireFromString("90").Equal(first.Close))
+	assert.Equal(t, int64(3), first.SampleCount)
+
+	second := candles[1]
+	assert.True(t, base.Add(time.Hour).Equal(second.Bucket), "bucket: got %s", second.Bucket)
+	assert.True(t, decimal.RequireFromString("110").Equal(second.Open))
+	assert.Equal(t, int64(1), second.SampleCount)
+}
+
+func testPriceDeleteByCurrency(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	btc := createCurrency(t, repos, "BTC")
+	eth := createCurrency(t, repos, "ETH")
+	createPrice(t, repos, btc.ID, "100", base)
+	createPrice(t, repos, btc.ID, "110", base.Add(time.Minute))
+	createPrice(t, repos, eth.ID, "3000", base)
+
+	deleted, err := repos.Prices.DeleteByCurrency(ctx, btc.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+
+	_, err = repos.Prices.GetLatestPrice(ctx, btc.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	latest, err := repos.Prices.GetLatestPrice(ctx, eth.ID)
+	require.NoError(t, err)
+	assertPrice(t, "3000", base, latest)
+
+	// Deleted rows do not block new prices at the same time.
+	createPrice(t, repos, btc.ID, "120", base)
+}
+
+func testQuarantineReview(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	btc := createCurrency(t, repos, "BTC")
+	sample := &models.QuarantinedPrice{
+		CurrencyID: btc.ID,
+		Price:      decimal.RequireFromString("150"),
+		Timestamp:  base,
+		Rule:       "max_change",
+		Reason:     "price moved 50%",
+	}
+	require.NoError(t, repos.Quarantine.Create(ctx, sample))
+
+	pending, err := repos.Quarantine.List(ctx, models.QuarantineStatusPending)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, "BTC", pending[0].Currency.Symbol)
+
+	reviewedAt := base.Add(time.Hour)
+	require.NoError(t, repos.Quarantine.UpdateStatus(ctx, sample.ID, models.QuarantineStatusRejected, reviewedAt))
+
+	// Only pending samples can be reviewed, so a concurrent second review
+	// loses instead of overwriting the first.
+	err = repos.Quarantine.UpdateStatus(ctx, sample.ID, models.QuarantineStatusApproved, reviewedAt)
+	assert.ErrorIs(t, err, repository.ErrConflict)
+	err = repos.Quarantine.UpdateStatus(ctx, sample.ID+1, models.QuarantineStatusApproved, reviewedAt)
+	assert.ErrorIs(t, err, repository.ErrConflict)
+
+	found, err := repos.Quarantine.GetByID(ctx, sample.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.QuarantineStatusRejected, found.Status)
+	if assert.NotNil(t, found.ReviewedAt) {
+		assert.True(t, reviewedAt.Equal(*found.ReviewedAt))
+	}
+	_, err = repos.Quarantine.GetByID(ctx, sample.ID+1)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
+
+func testUnitOfWorkCommit(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	btc := createCurrency(t, repos, "BTC")
+	createPrice(t, repos, btc.ID, "100", base)
+	// Warm up caching decorators, which must not serve the old rows afterwards.
+	_, err := repos.Currencies.GetBySymbol(ctx, "BTC")
+	require.NoError(t, err)
+	_, err = repos.Prices.GetLatestPrice(ctx, btc.ID)
+	require.NoError(t, err)
+
+	err = repos.UnitOfWork.WithTx(ctx, func(tx repository.Repositories) error {
+		deleted, err := tx.Prices.DeleteByCurrency(ctx, btc.ID)
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, int64(1), deleted)
+
+		// Reads inside the unit of work see its own writes.
+		_, err = tx.Prices.GetLatestPrice(ctx, btc.ID)
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+
+		if err := tx.Currencies.Transition(ctx, &models.CurrencyTransition{
+			CurrencyID: btc.ID,
+			FromStatus: models.CurrencyStatusActive,
+			ToStatus:   models.CurrencyStatusDeleted,
+			Actor:      "tester",
+		}); err != nil {
+			return err
+		}
+		return tx.Audit.Create(ctx, auditEvent(models.AuditActionCurrencyDelete, "BTC"))
+	})
+	require.NoError(t, err)
+
+	_, err = repos.Currencies.GetBySymbol(ctx, "BTC")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	_, err = repos.Prices.GetLatestPrice(ctx, btc.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	events, err := repos.Audit.List(ctx, models.AuditFilter{})
+	require.NoError(t, err)
+	assert.Len(t, events, 1)
+}
+
+func testUnitOfWorkRollback(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	btc := createCurrency(t, repos, "BTC")
+	createPrice(t, repos, btc.ID, "100", base)
+
+	// The second transition fails because the first one already moved the
+	// currency, which must undo everything done before it.
+	err := repos.UnitOfWork.WithTx(ctx, func(tx repository.Repositories) error {
+		if _, err := tx.Prices.DeleteByCurrency(ctx, btc.ID); err != nil {
+			return err
+		}
+		if err := tx.Currencies.Create(ctx, &models.Currency{Symbol: "ETH", ApiID: "ethereum", Interval: 60, Status: models.CurrencyStatusActive}); err != nil {
+			return err
+		}
+		if err := tx.Currencies.Transition(ctx, &models.CurrencyTransition{
+			CurrencyID: btc.ID,
+			FromStatus: models.CurrencyStatusActive,
+			ToStatus:   models.CurrencyStatusPaused,
+			Actor:      "tester",
+		}); err != nil {
+			return err
+		}
+		if err := tx.Audit.Create(ctx, auditEvent(models.AuditActionCurrencyStatus, "BTC")); err != nil {
+			return err
+		}
+		return tx.Currencies.Transition(ctx, &models.CurrencyTransition{
+			CurrencyID: btc.ID,
+			FromStatus: models.CurrencyStatusActive,
+			ToStatus:   models.CurrencyStatusArchived,
+			Actor:      "tester",
+		})
+	})
+	assert.ErrorIs(t, err, repository.ErrConflict)
+
+	found, err := repos.Currencies.GetBySymbol(ctx, "BTC")
+	require.NoError(t, err)
+	assert.Equal(t, models.CurrencyStatusActive, found.Status)
+	latest, err := repos.Prices.GetLatestPrice(ctx, btc.ID)
+	require.NoError(t, err)
+	assertPrice(t, "100", base, latest)
+	_, err = repos.Currencies.GetBySymbol(ctx, "ETH")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	transitions, err := repos.Currencies.ListTransitions(ctx, btc.ID)
+	require.NoError(t, err)
+	assert.Empty(t, transitions)
+	events, err := repos.Audit.List(ctx, models.AuditFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, events)
+
+	// The repositories stay usable after a rollback.
+	createCurrency(t, repos, "ETH")
+}
+
+// at returns base plus each of minutes.
+func at(minutes ...int) []time.Time {
+	timestamps := make([]time.Time, len(minutes))
+	for i, m := range minutes {
+		timestamps[i] = base.Add(time.Duration(m) * time.Minute)
+	}
+	return timestamps
+}
+
+func historyTimestamps(prices []models.Price) []time.Time {
+	timestamps := make([]time.Time, len(prices))
+	for i, price := range prices {
+		timestamps[i] = price.Timestamp.UTC()
+	}
+	return timestamps
+}
+
+func testPriceHistoryPage(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	btc := createCurrency(t, repos, "BTC")
+	eth := createCurrency(t, repos, "ETH")
+	for i := 0; i < 5; i++ {
+		createPrice(t, repos, btc.ID, "100", base.Add(time.Duration(i)*time.Minute))
+	}
+	createPrice(t, repos, eth.ID, "1", base)
+
+	// The range is [from, to).
+	query := models.PriceHistoryQuery{From: base, To: base.Add(4 * time.Minute), Limit: 2}
+	page, err := repos.Prices.GetPriceHistoryPage(ctx, btc.ID, query)
+	require.NoError(t, err)
+	assert.Equal(t, at(0, 1), historyTimestamps(page))
+
+	query.After = &models.PriceCursor{Timestamp: page[1].Timestamp, ID: page[1].ID}
+	page, err = repos.Prices.GetPriceHistoryPage(ctx, btc.ID, query)
+	require.NoError(t, err)
+	assert.Equal(t, at(2, 3), historyTimestamps(page))
+
+	query.After = &models.PriceCursor{Timestamp: page[1].Timestamp, ID: page[1].ID}
+	page, err = repos.Prices.GetPriceHistoryPage(ctx, btc.ID, query)
+	require.NoError(t, err)
+	assert.Empty(t, page)
+
+	query = models.PriceHistoryQuery{From: base, To: base.Add(time.Hour), Descending: true, Limit: 3}
+	page, err = repos.Prices.GetPriceHistoryPage(ctx, btc.ID, query)
+	require.NoError(t, err)
+	assert.Equal(t, at(4, 3, 2), historyTimestamps(page))
+
+	query.After = &models.PriceCursor{Timestamp: page[2].Timestamp, ID: page[2].ID}
+	page, err = repos.Prices.GetPriceHistoryPage(ctx, btc.ID, query)
+	require.NoError(t, err)
+	assert.Equal(t, at(1, 0), historyTimestamps(page))
+	assert.Equal(t, btc.ID, page[0].CurrencyID)
+}
+
+func testPriceHistoryPageStep(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	btc := createCurrency(t, repos, "BTC")
+	// base is 12:00, so 5-minute buckets start at 12:00, 12:05, 12:10, ...
+	for _, minute := range []int{0, 1, 4, 6, 7, 12, 13, 21} {
+		createPrice(t, repos, btc.ID, "100", base.Add(time.Duration(minute)*time.Minute))
+	}
+
+	query := models.PriceHistoryQuery{From: base, To: base.Add(time.Hour), Step: 5 * time.Minute, Limit: 2}
+	page, err := repos.Prices.GetPriceHistoryPage(ctx, btc.ID, query)
+	require.NoError(t, err)
+	assert.Equal(t, at(0, 6), historyTimestamps(page))
+
+	query.After = &models.PriceCursor{Timestamp: page[1].Timestamp, ID: page[1].ID}
+	page, err = repos.Prices.GetPriceHistoryPage(ctx, btc.ID, query)
+	require.NoError(t, err)
+	assert.Equal(t, at(12, 21), historyTimestamps(page))
+
+	// Descending pages pick the same first price of each bucket.
+	query = models.PriceHistoryQuery{From: base, To: base.Add(time.Hour), Step: 5 * time.Minute, Descending: true, Limit: 3}
+	page, err = repos.Prices.GetPriceHistoryPage(ctx, btc.ID, query)
+	require.NoError(t, err)
+	assert.Equal(t, at(21, 12, 6), historyTimestamps(page))
+
+	query.After = &models.PriceCursor{Timestamp: page[2].Timestamp, ID: page[2].ID}
+	page, err = repos.Prices.GetPriceHistoryPage(ctx, btc.ID, query)
+	require.NoError(t, err)
+	assert.Equal(t, at(0), historyTimestamps(page))
+}
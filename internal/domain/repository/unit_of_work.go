@@ -0,0 +1,20 @@
+package repository
+
+import "context"
+
+// Repositories is the set of repositories a unit of work hands out, all bound
+// to the same transaction.
+type Repositories struct {
+	Currencies CurrencyRepository
+	Prices     PriceRepository
+	Quarantine QuarantineRepository
+	Audit      AuditRepository
+}
+
+// UnitOfWork runs changes spanning several repositories atomically.
+type UnitOfWork interface {
+	// WithTx calls fn with repositories sharing one transaction. It commits
+	// when fn returns nil and rolls back otherwise, returning fn's error.
+	// Reads inside fn see its uncommitted writes and never go to replicas.
+	WithTx(ctx context.Context, fn func(repos Repositories) error) error
+}
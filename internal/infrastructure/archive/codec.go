@@ -0,0 +1,144 @@
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"crypto-price-tracker-app/internal/domain/models"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/shopspring/decimal"
+)
+
+// record is one archived price. Prices are kept as decimal strings so they
+// round-trip exactly whatever their scale.
+type record struct {
+	CurrencyID int64  `parquet:"currency_id"`
+	Symbol     string `parquet:"symbol,dict"`
+	Timestamp  int64  `parquet:"timestamp,timestamp(nanosecond)"`
+	Price      string `parquet:"price"`
+}
+
+func toRecords(symbol string, prices []models.Price) []record {
+	records := make([]record, len(prices))
+	for i, price := range prices {
+		records[i] = record{
+			CurrencyID: int64(price.CurrencyID),
+			Symbol:     symbol,
+			Timestamp:  price.Timestamp.UnixNano(),
+			Price:      price.Price.String(),
+		}
+	}
+	return records
+}
+
+func (r record) toModel() (models.Price, error) {
+	value, err := decimal.NewFromString(r.Price)
+	if err != nil {
+		return models.Price{}, fmt.Errorf("invalid price %q: %w", r.Price, err)
+	}
+	return models.Price{
+		CurrencyID: uint(r.CurrencyID),
+		Price:      value,
+		Timestamp:  time.Unix(0, r.Timestamp).UTC(),
+	}, nil
+}
+
+func fromRecords(records []record) ([]models.Price, error) {
+	prices := make([]models.Price, len(records))
+	for i, r := range records {
+		price, err := r.toModel()
+		if err != nil {
+			return nil, err
+		}
+		prices[i] = price
+	}
+	return prices, nil
+}
+
+func encodeParquet(symbol string, prices []models.Price) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := parquet.Write(&buf, toRecords(symbol, prices), parquet.Compression(&parquet.Zstd)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decodeParquet(data []byte) ([]models.Price, error) {
+	records, err := parquet.Read[record](bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	return fromRecords(records)
+}
+
+var csvHeader = []string{"currency_id", "symbol", "timestamp", "price"}
+
+func encodeCSV(symbol string, prices []models.Price) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	w := csv.NewWriter(gz)
+
+	if err := w.Write(csvHeader); err != nil {
+		return nil, err
+	}
+	for _, r := range toRecords(symbol, prices) {
+		row := []string{
+			strconv.FormatInt(r.CurrencyID, 10),
+			r.Symbol,
+			time.Unix(0, r.Timestamp).UTC().Format(time.RFC3339Nano),
+			r.Price,
+		}
+		if err := w.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decodeCSV(data []byte) ([]models.Price, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	r := csv.NewReader(gz)
+	r.FieldsPerRecord = len(csvHeader)
+
+	if _, err := r.Read(); err != nil {
+		return nil, fmt.Errorf("missing header: %w", err)
+	}
+
+	var records []record
+	for {
+		row, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		currencyID, err := strconv.ParseInt(row[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid currency id %q: %w", row[0], err)
+		}
+		timestamp, err := time.Parse(time.RFC3339Nano, row[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid timestamp %q: %w", row[2], err)
+		}
+		records = append(records, record{CurrencyID: currencyID, Symbol: row[1], Timestamp: timestamp.UnixNano(), Price: row[3]})
+	}
+	return fromRecords(records)
+}
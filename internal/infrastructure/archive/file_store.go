@@ -0,0 +1,189 @@
+package archive
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"crypto-price-tracker-app/internal/domain/models"
+	"crypto-price-tracker-app/internal/domain/repository"
+)
+
+const (
+	manifestFile = "manifest.json"
+	lockFile     = "manifest.lock"
+	// lockRetry is how often Lock tries again while another process holds
+	// the manifest.
+	lockRetry = 100 * time.Millisecond
+)
+
+var ErrChecksumMismatch = errors.New("archive checksum mismatch")
+
+var unsafeChars = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+
+// FileStore writes one file per currency and month under dir, as
+// <SYMBOL>/<YYYY-MM>.parquet or <SYMBOL>/<YYYY-MM>.csv.gz, next to a JSON
+// manifest. Directories are created on first write. Files are written to a
+// temporary name and renamed, so a crash never leaves a truncated archive.
+type FileStore struct {
+	dir    string
+	format models.ArchiveFormat
+}
+
+func NewFileStore(dir string, format models.ArchiveFormat) (repository.ArchiveStore, error) {
+	switch format {
+	case models.ArchiveParquet, models.ArchiveCSV:
+	default:
+		return nil, fmt.Errorf("unsupported archive format %q", format)
+	}
+	return &FileStore{dir: dir, format: format}, nil
+}
+
+func (s *FileStore) Write(ctx context.Context, entry *models.ArchiveEntry, prices []models.Price) error {
+	var (
+		data []byte
+		ext  string
+		err  error
+	)
+	switch s.format {
+	case models.ArchiveParquet:
+		data, err = encodeParquet(entry.Symbol, prices)
+		ext = ".parquet"
+	default:
+		data, err = encodeCSV(entry.Symbol, prices)
+		ext = ".csv.gz"
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode archive: %w", err)
+	}
+
+	file := path.Join(unsafeChars.ReplaceAllString(entry.Symbol, "_"), entry.Month+ext)
+	if err := writeAtomic(filepath.Join(s.dir, filepath.FromSlash(file)), data); err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256(data)
+	entry.File = file
+	entry.Format = s.format
+	entry.Rows = len(prices)
+	entry.SHA256 = hex.EncodeToString(sum[:])
+	return nil
+}
+
+func (s *FileStore) Read(ctx context.Context, entry models.ArchiveEntry) ([]models.Price, error) {
+	data, err := os.ReadFile(filepath.Join(s.dir, filepath.FromSlash(entry.File)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != entry.SHA256 {
+		return nil, fmt.Errorf("%w: %s", ErrChecksumMismatch, entry.File)
+	}
+
+	var prices []models.Price
+	switch entry.Format {
+	case models.ArchiveParquet:
+		prices, err = decodeParquet(data)
+	case models.ArchiveCSV:
+		prices, err = decodeCSV(data)
+	default:
+		return nil, fmt.Errorf("unsupported archive format %q", entry.Format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode archive %s: %w", entry.File, err)
+	}
+	if len(prices) != entry.Rows {
+		return nil, fmt.Errorf("archive %s has %d rows, manifest says %d", entry.File, len(prices), entry.Rows)
+	}
+	return prices, nil
+}
+
+// LoadManifest returns no entries when the manifest does not exist yet.
+func (s *FileStore) LoadManifest(ctx context.Context) ([]models.ArchiveEntry, error) {
+	data, err := os.ReadFile(filepath.Join(s.dir, manifestFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	var entries []models.ArchiveEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	return entries, nil
+}
+
+func (s *FileStore) SaveManifest(ctx context.Context, entries []models.ArchiveEntry) error {
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeAtomic(filepath.Join(s.dir, manifestFile), append(data, '\n'))
+}
+
+// Lock holds an exclusive flock on manifest.lock next to the manifest, so the
+// API and the worker can share one archive directory.
+func (s *FileStore) Lock(ctx context.Context) (func(), error) {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create archive directory: %w", err)
+	}
+	file, err := os.OpenFile(filepath.Join(s.dir, lockFile), os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open manifest lock: %w", err)
+	}
+
+	for {
+		locked, err := tryLock(file)
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to lock manifest: %w", err)
+		}
+		if locked {
+			// Closing the file releases the lock.
+			return func() { file.Close() }, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			file.Close()
+			return nil, ctx.Err()
+		case <-time.After(lockRetry):
+		}
+	}
+}
+
+func writeAtomic(name string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return fmt.Errorf("failed to create archive directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(name), "."+filepath.Base(name)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", name, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to sync %s: %w", name, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return os.Rename(tmp.Name(), name)
+}
@@ -0,0 +1,92 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"crypto-price-tracker-app/internal/domain/models"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStore_RoundTrip(t *testing.T) {
+	base := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	prices := []models.Price{
+		{CurrencyID: 1, Price: decimal.RequireFromString("65000.123456789012345678"), Timestamp: base},
+		{CurrencyID: 1, Price: decimal.RequireFromString("0.000000000000000001"), Timestamp: base.Add(90*time.Second + 123*time.Nanosecond)},
+	}
+
+	for _, format := range []models.ArchiveFormat{models.ArchiveParquet, models.ArchiveCSV} {
+		t.Run(string(format), func(t *testing.T) {
+			ctx := context.Background()
+			dir := t.TempDir()
+			store, err := NewFileStore(dir, format)
+			require.NoError(t, err)
+
+			entry := models.ArchiveEntry{CurrencyID: 1, Symbol: "BTC", Month: "2024-03"}
+			require.NoError(t, store.Write(ctx, &entry, prices))
+			assert.Equal(t, 2, entry.Rows)
+			assert.Equal(t, format, entry.Format)
+			assert.Len(t, entry.SHA256, 64)
+			assert.FileExists(t, filepath.Join(dir, entry.File))
+
+			got, err := store.Read(ctx, entry)
+			require.NoError(t, err)
+			require.Len(t, got, 2)
+			for i := range prices {
+				assert.True(t, prices[i].Price.Equal(got[i].Price), "price %d: %s", i, got[i].Price)
+				assert.True(t, prices[i].Timestamp.Equal(got[i].Timestamp), "timestamp %d: %s", i, got[i].Timestamp)
+				assert.Equal(t, prices[i].CurrencyID, got[i].CurrencyID)
+			}
+
+			require.NoError(t, store.SaveManifest(ctx, []models.ArchiveEntry{entry}))
+			manifest, err := store.LoadManifest(ctx)
+			require.NoError(t, err)
+			require.Len(t, manifest, 1)
+			assert.Equal(t, entry.SHA256, manifest[0].SHA256)
+
+			require.NoError(t, os.WriteFile(filepath.Join(dir, entry.File), []byte("corrupted"), 0o644))
+			_, err = store.Read(ctx, entry)
+			assert.True(t, errors.Is(err, ErrChecksumMismatch), "got %v", err)
+		})
+	}
+}
+
+func TestFileStore_EmptyManifest(t *testing.T) {
+	store, err := NewFileStore(t.TempDir(), models.ArchiveCSV)
+	require.NoError(t, err)
+
+	entries, err := store.LoadManifest(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	_, err = NewFileStore(t.TempDir(), "xml")
+	assert.Error(t, err)
+}
+
+func TestFileStore_Lock(t *testing.T) {
+	dir := t.TempDir()
+	first, err := NewFileStore(dir, models.ArchiveCSV)
+	require.NoError(t, err)
+	second, err := NewFileStore(dir, models.ArchiveCSV)
+	require.NoError(t, err)
+
+	unlock, err := first.Lock(context.Background())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*lockRetry)
+	defer cancel()
+	_, err = second.Lock(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	unlock()
+	unlock, err = second.Lock(context.Background())
+	require.NoError(t, err)
+	unlock()
+}
@@ -0,0 +1,12 @@
+//go:build !unix
+
+package archive
+
+import (
+	"errors"
+	"os"
+)
+
+func tryLock(file *os.File) (bool, error) {
+	return false, errors.New("archive locking is only supported on unix")
+}
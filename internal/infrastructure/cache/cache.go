@@ -0,0 +1,148 @@
+package cache
+
+import (
+	"context"
+	"expvar"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// stats exports hits, misses and hit rate of every cache as the cache expvar,
+// keyed by cache name.
+var stats = expvar.NewMap("cache")
+
+var (
+	countersMu sync.Mutex
+	counters   = map[string]*Counters{}
+)
+
+// Counters counts lookups of one cache.
+type Counters struct {
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+func (c *Counters) Hits() uint64   { return c.hits.Load() }
+func (c *Counters) Misses() uint64 { return c.misses.Load() }
+
+// HitRate is the share of lookups served from the cache, 0 before the first one.
+func (c *Counters) HitRate() float64 {
+	hits, misses := c.Hits(), c.Misses()
+	if hits+misses == 0 {
+		return 0
+	}
+	return float64(hits) / float64(hits+misses)
+}
+
+func register(name string) *Counters {
+	c := &Counters{}
+	countersMu.Lock()
+	counters[name] = c
+	countersMu.Unlock()
+	stats.Set(name, expvar.Func(func() interface{} {
+		return map[string]interface{}{"hits": c.Hits(), "misses": c.Misses(), "hit_rate": c.HitRate()}
+	}))
+	return c
+}
+
+// ReportStats logs the hit rate of every cache each interval until ctx is
+// done. A non-positive interval disables reporting.
+func ReportStats(ctx context.Context, interval time.Duration, logger *zap.Logger) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		countersMu.Lock()
+		names := make([]string, 0, len(counters))
+		for name := range counters {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			c := counters[name]
+			logger.Info("Cache stats",
+				zap.String("cache", name),
+				zap.Uint64("hits", c.Hits()),
+				zap.Uint64("misses", c.Misses()),
+				zap.Float64("hit_rate", c.HitRate()),
+			)
+		}
+		countersMu.Unlock()
+	}
+}
+
+type entry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+// ttlCache is a map whose entries expire ttl after being set.
+type ttlCache[K comparable, V any] struct {
+	mu       sync.RWMutex
+	entries  map[K]entry[V]
+	ttl      time.Duration
+	counters *Counters
+	now      func() time.Time
+}
+
+func newTTLCache[K comparable, V any](ttl time.Duration, counters *Counters) *ttlCache[K, V] {
+	return &ttlCache[K, V]{entries: map[K]entry[V]{}, ttl: ttl, counters: counters, now: time.Now}
+}
+
+func (c *ttlCache[K, V]) get(key K) (V, bool) {
+	c.mu.RLock()
+	e, ok := c.entries[key]
+	c.mu.RUnlock()
+
+	if !ok || !c.now().Before(e.expiresAt) {
+		c.counters.misses.Add(1)
+		var zero V
+		return zero, false
+	}
+	c.counters.hits.Add(1)
+	return e.value, true
+}
+
+// peek reads an entry without counting a lookup.
+func (c *ttlCache[K, V]) peek(key K) (V, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	e, ok := c.entries[key]
+	if !ok || !c.now().Before(e.expiresAt) {
+		var zero V
+		return zero, false
+	}
+	return e.value, true
+}
+
+func (c *ttlCache[K, V]) set(key K, value V) {
+	c.mu.Lock()
+	c.entries[key] = entry[V]{value: value, expiresAt: c.now().Add(c.ttl)}
+	c.mu.Unlock()
+}
+
+func (c *ttlCache[K, V]) delete(key K) {
+	c.mu.Lock()
+	delete(c.entries, key)
+	c.mu.Unlock()
+}
+
+func (c *ttlCache[K, V]) clear() {
+	c.mu.Lock()
+	c.entries = map[K]entry[V]{}
+	c.mu.Unlock()
+}
@@ -0,0 +1,125 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"crypto-price-tracker-app/internal/domain/models"
+	"crypto-price-tracker-app/internal/domain/repository"
+	"crypto-price-tracker-app/internal/domain/repository/repositorytest"
+	"crypto-price-tracker-app/internal/infrastructure/memory"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingPrices counts the calls that reach the underlying repository.
+type countingPrices struct {
+	repository.PriceRepository
+	latest, latestMany int
+}
+
+func (c *countingPrices) GetLatestPrice(ctx context.Context, currencyID uint) (*models.Price, error) {
+	c.latest++
+	return c.PriceRepository.GetLatestPrice(ctx, currencyID)
+}
+
+func (c *countingPrices) GetLatestPrices(ctx context.Context, currencyIDs []uint) ([]models.Price, error) {
+	c.latestMany++
+	return c.PriceRepository.GetLatestPrices(ctx, currencyIDs)
+}
+
+func TestPriceRepository_WriteThrough(t *testing.T) {
+	ctx := context.Background()
+	inner := &countingPrices{PriceRepository: memory.NewPriceRepository(memory.NewStore())}
+	repo := NewPriceRepository(inner, time.Minute)
+	base := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+
+	require.NoError(t, repo.Create(ctx, &models.Price{CurrencyID: 1, Price: decimal.NewFromInt(100), Timestamp: base}))
+	latest, err := repo.GetLatestPrice(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "100", latest.Price.String())
+	assert.Equal(t, 1, inner.latest)
+
+	require.NoError(t, repo.Create(ctx, &models.Price{CurrencyID: 1, Price: decimal.NewFromInt(110), Timestamp: base.Add(time.Minute)}))
+	require.NoError(t, repo.Create(ctx, &models.Price{CurrencyID: 1, Price: decimal.NewFromInt(90), Timestamp: base.Add(-time.Minute)}))
+	latest, err = repo.GetLatestPrice(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "110", latest.Price.String())
+	assert.Equal(t, 1, inner.latest, "write-through should keep the entry fresh")
+
+	require.NoError(t, repo.Create(ctx, &models.Price{CurrencyID: 2, Price: decimal.NewFromInt(5), Timestamp: base}))
+	prices, err := repo.GetLatestPrices(ctx, []uint{2, 1})
+	require.NoError(t, err)
+	require.Len(t, prices, 2)
+	assert.Equal(t, uint(1), prices[0].CurrencyID)
+	assert.Equal(t, uint(2), prices[1].CurrencyID)
+	assert.Equal(t, 1, inner.latestMany)
+
+	_, err = repo.GetLatestPrices(ctx, []uint{1, 2})
+	require.NoError(t, err)
+	assert.Equal(t, 1, inner.latestMany, "both prices should now be cached")
+	assert.Greater(t, repo.latest.counters.HitRate(), 0.5)
+}
+
+func TestPriceRepository_Expiry(t *testing.T) {
+	ctx := context.Background()
+	inner := &countingPrices{PriceRepository: memory.NewPriceRepository(memory.NewStore())}
+	repo := NewPriceRepository(inner, time.Minute)
+	now := time.Now()
+	repo.latest.now = func() time.Time { return now }
+
+	require.NoError(t, inner.Create(ctx, &models.Price{CurrencyID: 1, Price: decimal.NewFromInt(100), Timestamp: now}))
+	_, err := repo.GetLatestPrice(ctx, 1)
+	require.NoError(t, err)
+	_, err = repo.GetLatestPrice(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, inner.latest)
+
+	now = now.Add(time.Minute)
+	_, err = repo.GetLatestPrice(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 2, inner.latest)
+}
+
+func TestCurrencyRepository_InvalidatesOnWrite(t *testing.T) {
+	ctx := context.Background()
+	repo := NewCurrencyRepository(memory.NewCurrencyRepository(memory.NewStore()), time.Minute)
+
+	require.NoError(t, repo.Create(ctx, &models.Currency{Symbol: "BTC", ApiID: "bitcoin"}))
+	active, err := repo.GetAllActive(ctx)
+	require.NoError(t, err)
+	require.Len(t, active, 1)
+	btc, err := repo.GetBySymbol(ctx, "BTC")
+	require.NoError(t, err)
+
+	require.NoError(t, repo.Transition(ctx, &models.CurrencyTransition{
+		CurrencyID: btc.ID,
+		FromStatus: models.CurrencyStatusActive,
+		ToStatus:   models.CurrencyStatusPaused,
+	}))
+
+	active, err = repo.GetAllActive(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, active)
+	currency, err := repo.GetBySymbol(ctx, "BTC")
+	require.NoError(t, err)
+	assert.Equal(t, models.CurrencyStatusPaused, currency.Status)
+}
+
+func TestRepositoryContract(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) repositorytest.Repositories {
+		store := memory.NewStore()
+		currencies := NewCurrencyRepository(memory.NewCurrencyRepository(store), time.Minute)
+		prices := NewPriceRepository(memory.NewPriceRepository(store), time.Minute)
+		return repositorytest.Repositories{
+			Currencies: currencies,
+			Prices:     prices,
+			Quarantine: memory.NewQuarantineRepository(store),
+			Audit:      memory.NewAuditRepository(store),
+			UnitOfWork: NewUnitOfWork(memory.NewUnitOfWork(store), currencies, prices),
+		}
+	})
+}
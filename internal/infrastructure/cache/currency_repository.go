@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"crypto-price-tracker-app/internal/domain/models"
+	"crypto-price-tracker-app/internal/domain/repository"
+)
+
+// CurrencyRepository caches currency lookups by symbol and the active list.
+// Every write drops the whole cache: currencies change rarely, and a
+// paused or renamed currency must disappear from both at once.
+type CurrencyRepository struct {
+	repository.CurrencyRepository
+	bySymbol *ttlCache[string, models.Currency]
+	active   *ttlCache[struct{}, []models.Currency]
+}
+
+func NewCurrencyRepository(inner repository.CurrencyRepository, ttl time.Duration) *CurrencyRepository {
+	counters := register("currencies")
+	return &CurrencyRepository{
+		CurrencyRepository: inner,
+		bySymbol:           newTTLCache[string, models.Currency](ttl, counters),
+		active:             newTTLCache[struct{}, []models.Currency](ttl, counters),
+	}
+}
+
+func (r *CurrencyRepository) GetBySymbol(ctx context.Context, symbol string) (*models.Currency, error) {
+	if currency, ok := r.bySymbol.get(symbol); ok {
+		return &currency, nil
+	}
+
+	currency, err := r.CurrencyRepository.GetBySymbol(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	r.bySymbol.set(symbol, *currency)
+	return currency, nil
+}
+
+func (r *CurrencyRepository) GetAllActive(ctx context.Context) ([]models.Currency, error) {
+	if currencies, ok := r.active.get(struct{}{}); ok {
+		return append([]models.Currency(nil), currencies...), nil
+	}
+
+	currencies, err := r.CurrencyRepository.GetAllActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.active.set(struct{}{}, append([]models.Currency(nil), currencies...))
+	return currencies, nil
+}
+
+func (r *CurrencyRepository) Create(ctx context.Context, currency *models.Currency) error {
+	defer r.Invalidate()
+	return r.CurrencyRepository.Create(ctx, currency)
+}
+
+func (r *CurrencyRepository) Update(ctx context.Context, currency *models.Currency) error {
+	defer r.Invalidate()
+	return r.CurrencyRepository.Update(ctx, currency)
+}
+
+func (r *CurrencyRepository) Transition(ctx context.Context, transition *models.CurrencyTransition) error {
+	defer r.Invalidate()
+	return r.CurrencyRepository.Transition(ctx, transition)
+}
+
+// Invalidate drops every cached currency.
+func (r *CurrencyRepository) Invalidate() {
+	r.bySymbol.clear()
+	r.active.clear()
+}
@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"crypto-price-tracker-app/internal/domain/models"
+	"crypto-price-tracker-app/internal/domain/repository"
+)
+
+// PriceRepository caches the latest price of each currency. Create writes
+// through, so a process that stores prices keeps its own cache current;
+// other processes see them once the entry expires.
+type PriceRepository struct {
+	repository.PriceRepository
+	latest *ttlCache[uint, models.Price]
+}
+
+func NewPriceRepository(inner repository.PriceRepository, ttl time.Duration) *PriceRepository {
+	return &PriceRepository{
+		PriceRepository: inner,
+		latest:          newTTLCache[uint, models.Price](ttl, register("latest_prices")),
+	}
+}
+
+func (r *PriceRepository) GetLatestPrice(ctx context.Context, currencyID uint) (*models.Price, error) {
+	if price, ok := r.latest.get(currencyID); ok {
+		return &price, nil
+	}
+
+	price, err := r.PriceRepository.GetLatestPrice(ctx, currencyID)
+	if err != nil {
+		return nil, err
+	}
+	r.latest.set(currencyID, *price)
+	return price, nil
+}
+
+// GetLatestPrices serves cached currencies from memory and loads the rest
+// with one query.
+func (r *PriceRepository) GetLatestPrices(ctx context.Context, currencyIDs []uint) ([]models.Price, error) {
+	var prices []models.Price
+	var missing []uint
+	for _, id := range currencyIDs {
+		if price, ok := r.latest.get(id); ok {
+			prices = append(prices, price)
+		} else {
+			missing = append(missing, id)
+		}
+	}
+
+	if len(missing) > 0 {
+		loaded, err := r.PriceRepository.GetLatestPrices(ctx, missing)
+		if err != nil {
+			return nil, err
+		}
+		for _, price := range loaded {
+			r.latest.set(price.CurrencyID, price)
+		}
+		prices = append(prices, loaded...)
+	}
+
+	sort.Slice(prices, func(i, j int) bool { return prices[i].CurrencyID < prices[j].CurrencyID })
+	return prices, nil
+}
+
+// Create replaces the cached latest price when the new one is not older. An
+// uncached currency stays uncached: the new price may be a backfill.
+func (r *PriceRepository) Create(ctx context.Context, price *models.Price) error {
+	if err := r.PriceRepository.Create(ctx, price); err != nil {
+		return err
+	}
+	if cached, ok := r.latest.peek(price.CurrencyID); ok && !price.Timestamp.Before(cached.Timestamp) {
+		r.latest.set(price.CurrencyID, *price)
+	}
+	return nil
+}
+
+func (r *PriceRepository) CreateBatch(ctx context.Context, prices []models.Price, onConflict models.ConflictAction) (*models.BatchResult, error) {
+	defer func() {
+		for _, price := range prices {
+			r.latest.delete(price.CurrencyID)
+		}
+	}()
+	return r.PriceRepository.CreateBatch(ctx, prices, onConflict)
+}
+
+func (r *PriceRepository) DeleteByCurrency(ctx context.Context, currencyID uint) (int64, error) {
+	defer r.latest.delete(currencyID)
+	return r.PriceRepository.DeleteByCurrency(ctx, currencyID)
+}
+
+// Invalidate drops the cached latest price of currencyID.
+func (r *PriceRepository) Invalidate(currencyID uint) {
+	r.latest.delete(currencyID)
+}
+
+// InvalidateAll drops every cached latest price.
+func (r *PriceRepository) InvalidateAll() {
+	r.latest.clear()
+}
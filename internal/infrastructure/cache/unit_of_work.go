@@ -0,0 +1,28 @@
+package cache
+
+import (
+	"context"
+
+	"crypto-price-tracker-app/internal/domain/repository"
+)
+
+// UnitOfWork drops the caches after every unit of work. The repositories it
+// hands out write past the caches, and invalidating before the commit would
+// let a concurrent read cache the old rows again.
+type UnitOfWork struct {
+	inner      repository.UnitOfWork
+	currencies *CurrencyRepository
+	prices     *PriceRepository
+}
+
+func NewUnitOfWork(inner repository.UnitOfWork, currencies *CurrencyRepository, prices *PriceRepository) *UnitOfWork {
+	return &UnitOfWork{inner: inner, currencies: currencies, prices: prices}
+}
+
+func (u *UnitOfWork) WithTx(ctx context.Context, fn func(repos repository.Repositories) error) error {
+	defer func() {
+		u.currencies.Invalidate()
+		u.prices.InvalidateAll()
+	}()
+	return u.inner.WithTx(ctx, fn)
+}
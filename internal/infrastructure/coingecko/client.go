@@ -1,12 +1,17 @@
 package coingecko
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"time"
+
+	"crypto-price-tracker-app/internal/domain/models"
+
+	"github.com/shopspring/decimal"
 )
 
 type Client struct {
@@ -58,45 +63,69 @@ func NewClient(baseURL string) *Client {
 	}
 }
 
-func (c *Client) GetPrice(ctx context.Context, symbol string) (float64, error) {
-	url := fmt.Sprintf("%s/simple/price?ids=%s&vs_currencies=usd", c.baseURL, symbol)
+func (c *Client) GetPrice(ctx context.Context, symbol string) (decimal.Decimal, error) {
+	quote, err := c.GetQuote(ctx, symbol)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return quote.Price, nil
+}
+
+func (c *Client) GetQuote(ctx context.Context, symbol string) (*models.Quote, error) {
+	url := fmt.Sprintf("%s/simple/price?ids=%s&vs_currencies=usd&include_last_updated_at=true", c.baseURL, symbol)
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return 0, fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return 0, fmt.Errorf("failed to execute request: %w", err)
+		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return 0, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return 0, fmt.Errorf("failed to read response body: %w", err)
+		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
-	var priceData map[string]map[string]float64
-	if err := json.Unmarshal(body, &priceData); err != nil {
-		return 0, fmt.Errorf("failed to parse JSON response: %w", err)
+	var priceData map[string]map[string]json.Number
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&priceData); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
 	}
 
 	if priceData[symbol] == nil {
-		return 0, fmt.Errorf("currency %s not found", symbol)
+		return nil, fmt.Errorf("currency %s not found", symbol)
 	}
 
-	price, exists := priceData[symbol]["usd"]
+	rawPrice, exists := priceData[symbol]["usd"]
 	if !exists {
-		return 0, fmt.Errorf("USD price not found for %s", symbol)
+		return nil, fmt.Errorf("USD price not found for %s", symbol)
+	}
+
+	price, err := decimal.NewFromString(rawPrice.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse USD price for %s: %w", symbol, err)
+	}
+
+	quote := &models.Quote{Price: price}
+	if rawUpdatedAt, ok := priceData[symbol]["last_updated_at"]; ok {
+		updatedAt, err := rawUpdatedAt.Int64()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse last_updated_at for %s: %w", symbol, err)
+		}
+		quote.UpdatedAt = time.Unix(updatedAt, 0)
 	}
 
-	return price, nil
+	return quote, nil
 }
 
 func (c *Client) GetDetailedPrice(ctx context.Context, symbol string) (*PriceResponse, error) {
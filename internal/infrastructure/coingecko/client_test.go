@@ -0,0 +1,39 @@
+package coingecko
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_GetQuote(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/simple/price", r.URL.Path)
+		assert.Equal(t, "shiba-inu", r.URL.Query().Get("ids"))
+		w.Write([]byte(`{"shiba-inu":{"usd":0.000012345678901234,"last_updated_at":1711356300}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	quote, err := client.GetQuote(context.Background(), "shiba-inu")
+
+	require.NoError(t, err)
+	assert.Equal(t, "0.000012345678901234", quote.Price.String())
+	assert.Equal(t, int64(1711356300), quote.UpdatedAt.Unix())
+}
+
+func TestClient_GetQuote_UnknownCurrency(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	_, err := client.GetQuote(context.Background(), "unknown")
+
+	assert.Error(t, err)
+}
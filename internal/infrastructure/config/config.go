@@ -3,28 +3,65 @@ package config
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
+const (
+	StorageDatabase = "database"
+	StorageMemory   = "memory"
+
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
 type Config struct {
-	Database DatabaseConfig `mapstructure:"database"`
-	API      APIConfig      `mapstructure:"api"`
-	Worker   WorkerConfig   `mapstructure:"worker"`
-	Logging  LoggingConfig  `mapstructure:"logging"`
+	Storage      string             `mapstructure:"storage"`
+	Database     DatabaseConfig     `mapstructure:"database"`
+	API          APIConfig          `mapstructure:"api"`
+	Worker       WorkerConfig       `mapstructure:"worker"`
+	Logging      LoggingConfig      `mapstructure:"logging"`
+	Validation   ValidationConfig   `mapstructure:"validation"`
+	Partitioning PartitioningConfig `mapstructure:"partitioning"`
+	Rollups      RollupsConfig      `mapstructure:"rollups"`
+	Cache        CacheConfig        `mapstructure:"cache"`
+	Archive      ArchiveConfig      `mapstructure:"archive"`
 }
 
 type DatabaseConfig struct {
+	Driver   string `mapstructure:"driver"`
+	Path     string `mapstructure:"path"`
 	Host     string `mapstructure:"host"`
 	Port     int    `mapstructure:"port"`
 	User     string `mapstructure:"user"`
 	Password string `mapstructure:"password"`
 	DBName   string `mapstructure:"dbname"`
 	SSLMode  string `mapstructure:"sslmode"`
+
+	MaxOpenConns       int           `mapstructure:"max_open_conns"`
+	MaxIdleConns       int           `mapstructure:"max_idle_conns"`
+	ConnMaxLifetime    time.Duration `mapstructure:"conn_max_lifetime"`
+	ConnMaxIdleTime    time.Duration `mapstructure:"conn_max_idle_time"`
+	ConnectRetries     int           `mapstructure:"connect_retries"`
+	ConnectBackoff     time.Duration `mapstructure:"connect_backoff"`
+	SlowQueryThreshold time.Duration `mapstructure:"slow_query_threshold"`
+	StatsInterval      time.Duration `mapstructure:"stats_interval"`
+
+	Replicas             []string      `mapstructure:"replicas"`
+	ReplicaMaxLag        time.Duration `mapstructure:"replica_max_lag"`
+	ReplicaCheckInterval time.Duration `mapstructure:"replica_check_interval"`
 }
 
+// APIConfig configures the HTTP API. DebugAddr is the address of a separate
+// listener for /debug/vars; it is not served when empty. DeprecatedSunset is
+// the date, as YYYY-MM-DD, after which the deprecated /currency routes may be
+// removed.
 type APIConfig struct {
-	Port string `mapstructure:"port"`
+	Port             string `mapstructure:"port"`
+	StaleMultiplier  int    `mapstructure:"stale_multiplier"`
+	DebugAddr        string `mapstructure:"debug_addr"`
+	DeprecatedSunset string `mapstructure:"deprecated_sunset"`
 }
 
 type WorkerConfig struct {
@@ -35,37 +72,157 @@ type LoggingConfig struct {
 	Level string `mapstructure:"level"`
 }
 
+type ValidationConfig struct {
+	Enabled    bool                        `mapstructure:"enabled"`
+	Default    PriceRulesConfig            `mapstructure:"default"`
+	Currencies map[string]PriceRulesConfig `mapstructure:"currencies"`
+}
+
+type PartitioningConfig struct {
+	Enabled         bool          `mapstructure:"enabled"`
+	MonthsAhead     int           `mapstructure:"months_ahead"`
+	RetentionMonths int           `mapstructure:"retention_months"`
+	DropExpired     bool          `mapstructure:"drop_expired"`
+	CheckInterval   time.Duration `mapstructure:"check_interval"`
+}
+
+type RollupsConfig struct {
+	Enabled         bool                  `mapstructure:"enabled"`
+	RefreshInterval time.Duration         `mapstructure:"refresh_interval"`
+	Lookback        time.Duration         `mapstructure:"lookback"`
+	Retention       RollupRetentionConfig `mapstructure:"retention"`
+}
+
+type RollupRetentionConfig struct {
+	Minute time.Duration `mapstructure:"1m"`
+	Hour   time.Duration `mapstructure:"1h"`
+	Day    time.Duration `mapstructure:"1d"`
+}
+
+type CacheConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	CurrencyTTL   time.Duration `mapstructure:"currency_ttl"`
+	PriceTTL      time.Duration `mapstructure:"price_ttl"`
+	StatsInterval time.Duration `mapstructure:"stats_interval"`
+}
+
+// ArchiveConfig controls moving old prices to files. Months older than
+// OlderThan are archived every Interval when Enabled.
+type ArchiveConfig struct {
+	Enabled   bool          `mapstructure:"enabled"`
+	Dir       string        `mapstructure:"dir"`
+	Format    string        `mapstructure:"format"`
+	OlderThan time.Duration `mapstructure:"older_than"`
+	Interval  time.Duration `mapstructure:"interval"`
+}
+
+type PriceRulesConfig struct {
+	MinPrice         float64       `mapstructure:"min_price"`
+	MaxPrice         float64       `mapstructure:"max_price"`
+	MaxChangePercent float64       `mapstructure:"max_change_percent"`
+	MaxQuoteAge      time.Duration `mapstructure:"max_quote_age"`
+}
+
 func LoadConfig(configPath string) (*Config, error) {
 	viper.SetConfigFile(configPath)
 	viper.AutomaticEnv()
 	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 
+	viper.SetDefault("storage", StorageDatabase)
+
+	viper.SetDefault("database.driver", DriverPostgres)
+	viper.SetDefault("database.path", "data/crypto_tracker.db")
 	viper.SetDefault("database.host", "localhost")
 	viper.SetDefault("database.port", 5432)
 	viper.SetDefault("database.user", "postgres")
 	viper.SetDefault("database.password", "password")
 	viper.SetDefault("database.dbname", "crypto_tracker")
 	viper.SetDefault("database.sslmode", "disable")
+	viper.SetDefault("database.max_open_conns", 25)
+	viper.SetDefault("database.max_idle_conns", 10)
+	viper.SetDefault("database.conn_max_lifetime", "30m")
+	viper.SetDefault("database.conn_max_idle_time", "5m")
+	viper.SetDefault("database.connect_retries", 10)
+	viper.SetDefault("database.connect_backoff", "1s")
+	viper.SetDefault("database.slow_query_threshold", "200ms")
+	viper.SetDefault("database.stats_interval", "1m")
+	viper.SetDefault("database.replicas", []string{})
+	viper.SetDefault("database.replica_max_lag", "30s")
+	viper.SetDefault("database.replica_check_interval", "10s")
 
 	viper.SetDefault("api.port", "8080")
+	viper.SetDefault("api.stale_multiplier", 3)
+	viper.SetDefault("api.debug_addr", "")
+	viper.SetDefault("api.deprecated_sunset", "2027-04-18")
 
 	viper.SetDefault("worker.interval", 60)
 
 	viper.SetDefault("logging.level", "info")
 
+	viper.SetDefault("validation.enabled", true)
+	viper.SetDefault("validation.default.max_change_percent", 50)
+	viper.SetDefault("validation.default.max_quote_age", "1h")
+
+	viper.SetDefault("partitioning.enabled", true)
+	viper.SetDefault("partitioning.months_ahead", 3)
+	viper.SetDefault("partitioning.retention_months", 0)
+	viper.SetDefault("partitioning.drop_expired", false)
+	viper.SetDefault("partitioning.check_interval", "1h")
+
+	viper.SetDefault("rollups.enabled", true)
+	viper.SetDefault("rollups.refresh_interval", "1m")
+	viper.SetDefault("rollups.lookback", "1h")
+	viper.SetDefault("rollups.retention.1m", "720h")
+	viper.SetDefault("rollups.retention.1h", "8760h")
+	viper.SetDefault("rollups.retention.1d", 0)
+
+	viper.SetDefault("cache.enabled", true)
+	viper.SetDefault("cache.currency_ttl", "1m")
+	viper.SetDefault("cache.price_ttl", "30s")
+	viper.SetDefault("cache.stats_interval", "5m")
+
+	viper.SetDefault("archive.enabled", false)
+	viper.SetDefault("archive.dir", "archive")
+	viper.SetDefault("archive.format", "parquet")
+	viper.SetDefault("archive.older_than", "8760h")
+	viper.SetDefault("archive.interval", "24h")
+
+	viper.BindEnv("storage", "STORAGE")
+
+	viper.BindEnv("database.driver", "DB_DRIVER")
+	viper.BindEnv("database.path", "DB_PATH")
 	viper.BindEnv("database.host", "DB_HOST")
 	viper.BindEnv("database.port", "DB_PORT")
 	viper.BindEnv("database.user", "DB_USER")
 	viper.BindEnv("database.password", "DB_PASSWORD")
 	viper.BindEnv("database.dbname", "DB_NAME")
 	viper.BindEnv("database.sslmode", "DB_SSLMODE")
+	viper.BindEnv("database.max_open_conns", "DB_MAX_OPEN_CONNS")
+	viper.BindEnv("database.max_idle_conns", "DB_MAX_IDLE_CONNS")
+	viper.BindEnv("database.connect_retries", "DB_CONNECT_RETRIES")
+	viper.BindEnv("database.slow_query_threshold", "DB_SLOW_QUERY_THRESHOLD")
+	viper.BindEnv("database.replicas", "DB_REPLICAS")
+	viper.BindEnv("database.replica_max_lag", "DB_REPLICA_MAX_LAG")
 
 	viper.BindEnv("api.port", "API_PORT")
+	viper.BindEnv("api.stale_multiplier", "API_STALE_MULTIPLIER")
+	viper.BindEnv("api.debug_addr", "API_DEBUG_ADDR")
+	viper.BindEnv("api.deprecated_sunset", "API_DEPRECATED_SUNSET")
 
 	viper.BindEnv("worker.interval", "WORKER_INTERVAL")
 
 	viper.BindEnv("logging.level", "LOG_LEVEL")
 
+	viper.BindEnv("validation.enabled", "VALIDATION_ENABLED")
+
+	viper.BindEnv("partitioning.retention_months", "PARTITION_RETENTION_MONTHS")
+
+	viper.BindEnv("cache.enabled", "CACHE_ENABLED")
+
+	viper.BindEnv("archive.enabled", "ARCHIVE_ENABLED")
+	viper.BindEnv("archive.dir", "ARCHIVE_DIR")
+	viper.BindEnv("archive.format", "ARCHIVE_FORMAT")
+
 	if err := viper.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
 			return nil, fmt.Errorf("failed to read config file: %w", err)
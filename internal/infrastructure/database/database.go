@@ -0,0 +1,148 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"crypto-price-tracker-app/internal/domain/repository"
+	"crypto-price-tracker-app/internal/infrastructure/config"
+	"crypto-price-tracker-app/internal/infrastructure/postgres"
+	"crypto-price-tracker-app/internal/infrastructure/sqlite"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type Repositories struct {
+	Currencies repository.CurrencyRepository
+	Prices     repository.PriceRepository
+	Quarantine repository.QuarantineRepository
+	Archive    repository.PriceArchiveRepository
+	Audit      repository.AuditRepository
+	UnitOfWork repository.UnitOfWork
+}
+
+// Open connects to the database selected by cfg.Driver and configures its
+// pool. Failed attempts are retried cfg.ConnectRetries times with exponential
+// backoff, so the services can start before the database accepts connections.
+func Open(cfg config.DatabaseConfig, log *zap.Logger) (*gorm.DB, error) {
+	gormLog := NewGormLogger(log, cfg.SlowQueryThreshold)
+	backoff := cfg.ConnectBackoff
+
+	for attempt := 1; ; attempt++ {
+		db, err := connect(cfg, gormLog)
+		if err == nil {
+			if err := configurePool(db, "primary", cfg); err != nil {
+				Close(db)
+				return nil, err
+			}
+			return db, nil
+		}
+		if attempt > cfg.ConnectRetries {
+			return nil, err
+		}
+
+		log.Warn("Database is not reachable, retrying",
+			zap.Int("attempt", attempt),
+			zap.Duration("backoff", backoff),
+			zap.Error(err))
+		time.Sleep(backoff)
+		backoff = min(backoff*2, maxConnectBackoff)
+	}
+}
+
+const maxConnectBackoff = 30 * time.Second
+
+func connect(cfg config.DatabaseConfig, log logger.Interface) (*gorm.DB, error) {
+	switch cfg.Driver {
+	case config.DriverPostgres, "":
+		return postgres.NewConnection(postgresConfig(cfg), log)
+	case config.DriverSQLite:
+		return sqlite.NewConnection(cfg.Path, log)
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q", cfg.Driver)
+	}
+}
+
+// OpenReplicas connects to cfg.Replicas. Only Postgres supports replicas;
+// for other drivers they are ignored with a warning and nil is returned,
+// which the repositories treat as "read from the primary".
+func OpenReplicas(cfg config.DatabaseConfig, logger *zap.Logger) (*postgres.ReplicaSet, error) {
+	if len(cfg.Replicas) == 0 {
+		return nil, nil
+	}
+	if cfg.Driver != config.DriverPostgres && cfg.Driver != "" {
+		logger.Warn("Read replicas are only supported for postgres, ignoring them", zap.String("driver", cfg.Driver))
+		return nil, nil
+	}
+
+	dbs := make([]*gorm.DB, 0, len(cfg.Replicas))
+	for i, dsn := range cfg.Replicas {
+		db, err := postgres.Open(dsn, NewGormLogger(logger, cfg.SlowQueryThreshold))
+		if err == nil {
+			err = configurePool(db, fmt.Sprintf("replica-%d", i+1), cfg)
+		}
+		if err != nil {
+			for _, opened := range dbs {
+				Close(opened)
+			}
+			return nil, fmt.Errorf("replica %d: %w", i+1, err)
+		}
+		dbs = append(dbs, db)
+	}
+
+	logger.Info("Connected to read replicas", zap.Int("count", len(dbs)), zap.Duration("max_lag", cfg.ReplicaMaxLag))
+	return postgres.NewReplicaSet(dbs, cfg.ReplicaMaxLag, logger), nil
+}
+
+// NewPriceListener returns a listener for price events, or nil when the
+// driver cannot deliver them; only Postgres has LISTEN/NOTIFY.
+func NewPriceListener(cfg config.DatabaseConfig, logger *zap.Logger) repository.PriceEventSource {
+	if cfg.Driver != config.DriverPostgres && cfg.Driver != "" {
+		return nil
+	}
+	return postgres.NewPriceListener(postgresConfig(cfg).DSN(), logger)
+}
+
+func postgresConfig(cfg config.DatabaseConfig) *postgres.Config {
+	return &postgres.Config{
+		Host:     cfg.Host,
+		Port:     fmt.Sprintf("%d", cfg.Port),
+		User:     cfg.User,
+		Password: cfg.Password,
+		DBName:   cfg.DBName,
+		SSLMode:  cfg.SSLMode,
+	}
+}
+
+func Close(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get underlying sql.DB: %w", err)
+	}
+	return sqlDB.Close()
+}
+
+// NewRepositories returns the implementations matching the dialect of db.
+// Reads go to replicas when it is non-nil.
+func NewRepositories(db *gorm.DB, replicas *postgres.ReplicaSet) Repositories {
+	if db.Dialector.Name() == config.DriverSQLite {
+		return Repositories{
+			Currencies: sqlite.NewCurrencyRepository(db),
+			Prices:     sqlite.NewPriceRepository(db),
+			Quarantine: sqlite.NewQuarantineRepository(db),
+			Archive:    sqlite.NewPriceArchiveRepository(db),
+			Audit:      sqlite.NewAuditRepository(db),
+			UnitOfWork: sqlite.NewUnitOfWork(db),
+		}
+	}
+	return Repositories{
+		Currencies: postgres.NewCurrencyRepository(db, replicas),
+		Prices:     postgres.NewPriceRepository(db, replicas),
+		Quarantine: postgres.NewQuarantineRepository(db),
+		Archive:    postgres.NewPriceArchiveRepository(db),
+		Audit:      postgres.NewAuditRepository(db),
+		UnitOfWork: postgres.NewUnitOfWork(db),
+	}
+}
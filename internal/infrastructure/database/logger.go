@@ -0,0 +1,77 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/utils"
+)
+
+// gormLogger sends GORM output to zap. Statements are logged at debug level,
+// statements slower than slowThreshold as warnings and failures as errors;
+// record-not-found is an expected outcome and is not logged.
+type gormLogger struct {
+	logger        *zap.Logger
+	level         logger.LogLevel
+	slowThreshold time.Duration
+}
+
+// NewGormLogger returns a GORM logger writing to log. A zero slowThreshold
+// disables slow query warnings.
+func NewGormLogger(log *zap.Logger, slowThreshold time.Duration) logger.Interface {
+	return &gormLogger{
+		logger:        log.Named("gorm"),
+		level:         logger.Info,
+		slowThreshold: slowThreshold,
+	}
+}
+
+func (l *gormLogger) LogMode(level logger.LogLevel) logger.Interface {
+	copied := *l
+	copied.level = level
+	return &copied
+}
+
+func (l *gormLogger) Info(_ context.Context, msg string, args ...interface{}) {
+	if l.level >= logger.Info {
+		l.logger.Info(fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l *gormLogger) Warn(_ context.Context, msg string, args ...interface{}) {
+	if l.level >= logger.Warn {
+		l.logger.Warn(fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l *gormLogger) Error(_ context.Context, msg string, args ...interface{}) {
+	if l.level >= logger.Error {
+		l.logger.Error(fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l *gormLogger) Trace(_ context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if l.level <= logger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	switch {
+	case err != nil && l.level >= logger.Error && !errors.Is(err, gorm.ErrRecordNotFound):
+		sql, rows := fc()
+		l.logger.Error("Query failed", zap.Error(err), zap.Duration("elapsed", elapsed), zap.Int64("rows", rows), zap.String("sql", sql), zap.String("source", utils.FileWithLineNum()))
+	case l.slowThreshold > 0 && elapsed > l.slowThreshold && l.level >= logger.Warn:
+		sql, rows := fc()
+		l.logger.Warn("Slow query", zap.Duration("elapsed", elapsed), zap.Duration("threshold", l.slowThreshold), zap.Int64("rows", rows), zap.String("sql", sql), zap.String("source", utils.FileWithLineNum()))
+	case l.level >= logger.Info:
+		if ce := l.logger.Check(zap.DebugLevel, "Query"); ce != nil {
+			sql, rows := fc()
+			ce.Write(zap.Duration("elapsed", elapsed), zap.Int64("rows", rows), zap.String("sql", sql), zap.String("source", utils.FileWithLineNum()))
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"gorm.io/gorm"
+)
+
+func TestGormLogger_Trace(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	log := NewGormLogger(zap.New(core), 100*time.Millisecond)
+	query := func() (string, int64) { return "SELECT 1", 1 }
+	ctx := context.Background()
+
+	log.Trace(ctx, time.Now(), query, nil)
+	log.Trace(ctx, time.Now().Add(-time.Second), query, nil)
+	log.Trace(ctx, time.Now(), query, errors.New("boom"))
+	log.Trace(ctx, time.Now(), query, gorm.ErrRecordNotFound)
+
+	entries := logs.AllUntimed()
+	if len(entries) != 4 {
+		t.Fatalf("expected 4 entries, got %d", len(entries))
+	}
+	want := []struct {
+		level zapcore.Level
+		msg   string
+	}{
+		{zapcore.DebugLevel, "Query"},
+		{zapcore.WarnLevel, "Slow query"},
+		{zapcore.ErrorLevel, "Query failed"},
+		{zapcore.DebugLevel, "Query"},
+	}
+	for i, w := range want {
+		if entries[i].Level != w.level || entries[i].Message != w.msg {
+			t.Errorf("entry %d: got %s %q, want %s %q", i, entries[i].Level, entries[i].Message, w.level, w.msg)
+		}
+	}
+}
+
+func TestGormLogger_InfoLevelSkipsStatements(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	log := NewGormLogger(zap.New(core), 0)
+
+	log.Trace(context.Background(), time.Now().Add(-time.Hour), func() (string, int64) {
+		t.Fatal("statement should not be rendered")
+		return "", 0
+	}, nil)
+
+	if logs.Len() != 0 {
+		t.Fatalf("expected no entries, got %d", logs.Len())
+	}
+}
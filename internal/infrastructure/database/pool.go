@@ -0,0 +1,86 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"expvar"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"crypto-price-tracker-app/internal/infrastructure/config"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// poolStats exports the sql.DBStats of every pool opened by this package as
+// the db_pool expvar, keyed by pool name.
+var poolStats = expvar.NewMap("db_pool")
+
+var (
+	poolsMu sync.Mutex
+	pools   = map[string]*sql.DB{}
+)
+
+// configurePool applies the pool settings of cfg to db and registers it for
+// stats reporting under name.
+func configurePool(db *gorm.DB, name string, cfg config.DatabaseConfig) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get underlying sql.DB: %w", err)
+	}
+
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
+	poolsMu.Lock()
+	pools[name] = sqlDB
+	poolsMu.Unlock()
+	poolStats.Set(name, expvar.Func(func() interface{} { return sqlDB.Stats() }))
+	return nil
+}
+
+// ReportPoolStats logs the stats of every registered pool each interval until
+// ctx is done. A non-positive interval disables reporting.
+func ReportPoolStats(ctx context.Context, interval time.Duration, logger *zap.Logger) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		poolsMu.Lock()
+		names := make([]string, 0, len(pools))
+		for name := range pools {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			stats := pools[name].Stats()
+			logger.Info("Database pool stats",
+				zap.String("pool", name),
+				zap.Int("open", stats.OpenConnections),
+				zap.Int("in_use", stats.InUse),
+				zap.Int("idle", stats.Idle),
+				zap.Int64("wait_count", stats.WaitCount),
+				zap.Duration("wait_duration", stats.WaitDuration),
+				zap.Int64("max_idle_closed", stats.MaxIdleClosed),
+				zap.Int64("max_idle_time_closed", stats.MaxIdleTimeClosed),
+				zap.Int64("max_lifetime_closed", stats.MaxLifetimeClosed),
+			)
+		}
+		poolsMu.Unlock()
+	}
+}
@@ -0,0 +1,60 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"crypto-price-tracker-app/internal/domain/models"
+	"crypto-price-tracker-app/internal/domain/repository"
+)
+
+type AuditRepository struct {
+	store *Store
+	tx    *txn
+}
+
+func NewAuditRepository(store *Store) repository.AuditRepository {
+	return &AuditRepository{store: store}
+}
+
+func (r *AuditRepository) Create(ctx context.Context, event *models.AuditEvent) error {
+	defer r.store.lock(r.tx)()
+
+	r.store.auditSeq++
+	event.ID = r.store.auditSeq
+	event.CreatedAt = time.Now()
+	appendRow(r.tx, &r.store.audit, *event)
+	return nil
+}
+
+func (r *AuditRepository) List(ctx context.Context, filter models.AuditFilter) ([]models.AuditEvent, error) {
+	defer r.store.rlock(r.tx)()
+
+	var events []models.AuditEvent
+	// Events are appended in creation order, so walking backwards yields the
+	// newest first.
+	for i := len(r.store.audit) - 1; i >= 0; i-- {
+		event := r.store.audit[i]
+		if !matchesAudit(event, filter) {
+			continue
+		}
+		events = append(events, event)
+		if filter.Limit > 0 && len(events) == filter.Limit {
+			break
+		}
+	}
+	return events, nil
+}
+
+func matchesAudit(event models.AuditEvent, filter models.AuditFilter) bool {
+	switch {
+	case filter.Actor != "" && event.Actor != filter.Actor,
+		filter.Action != "" && event.Action != filter.Action,
+		filter.TargetType != "" && event.TargetType != filter.TargetType,
+		filter.TargetID != "" && event.TargetID != filter.TargetID,
+		!filter.From.IsZero() && event.CreatedAt.Before(filter.From),
+		!filter.To.IsZero() && !event.CreatedAt.Before(filter.To):
+		return false
+	}
+	return true
+}
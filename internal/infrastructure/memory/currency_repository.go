@@ -0,0 +1,155 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"crypto-price-tracker-app/internal/domain/models"
+	"crypto-price-tracker-app/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+type CurrencyRepository struct {
+	store *Store
+	tx    *txn
+}
+
+func NewCurrencyRepository(store *Store) repository.CurrencyRepository {
+	return &CurrencyRepository{store: store}
+}
+
+// Create applies the same column defaults as the currencies table. Symbols
+// stay unique even against soft-deleted rows, as the unique index does.
+func (r *CurrencyRepository) Create(ctx context.Context, currency *models.Currency) error {
+	defer r.store.lock(r.tx)()
+
+	for _, existing := range r.store.currencies {
+		if existing.Symbol == currency.Symbol {
+			return repository.ErrDuplicate
+		}
+	}
+
+	if currency.Interval == 0 {
+		currency.Interval = 60
+	}
+	if currency.Precision == 0 {
+		currency.Precision = models.DefaultPrecision
+	}
+	if currency.Status == "" {
+		currency.Status = models.CurrencyStatusActive
+	}
+
+	now := time.Now()
+	r.store.currencySeq++
+	currency.ID = r.store.currencySeq
+	currency.CreatedAt = now
+	currency.UpdatedAt = now
+	appendRow(r.tx, &r.store.currencies, *currency)
+	return nil
+}
+
+func (r *CurrencyRepository) GetBySymbol(ctx context.Context, symbol string) (*models.Currency, error) {
+	defer r.store.rlock(r.tx)()
+
+	for _, currency := range r.store.currencies {
+		if currency.Symbol == symbol && !currency.DeletedAt.Valid {
+			return &currency, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *CurrencyRepository) GetBySymbols(ctx context.Context, symbols []string) ([]models.Currency, error) {
+	defer r.store.rlock(r.tx)()
+
+	var currencies []models.Currency
+	for _, currency := range r.store.currencies {
+		if slices.Contains(symbols, currency.Symbol) && !currency.DeletedAt.Valid {
+			currencies = append(currencies, currency)
+		}
+	}
+	return currencies, nil
+}
+
+func (r *CurrencyRepository) GetDeletedBySymbol(ctx context.Context, symbol string) (*models.Currency, error) {
+	defer r.store.rlock(r.tx)()
+
+	for _, currency := range r.store.currencies {
+		if currency.Symbol == symbol && currency.Status == models.CurrencyStatusDeleted {
+			return &currency, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *CurrencyRepository) GetAllActive(ctx context.Context) ([]models.Currency, error) {
+	defer r.store.rlock(r.tx)()
+
+	var currencies []models.Currency
+	for _, currency := range r.store.currencies {
+		if currency.IsActive() && !currency.DeletedAt.Valid {
+			currencies = append(currencies, currency)
+		}
+	}
+	return currencies, nil
+}
+
+func (r *CurrencyRepository) Update(ctx context.Context, currency *models.Currency) error {
+	defer r.store.lock(r.tx)()
+
+	for i := range r.store.currencies {
+		existing := &r.store.currencies[i]
+		if existing.ID != currency.ID || existing.Status != currency.Status || existing.DeletedAt.Valid {
+			continue
+		}
+		keepRow(r.tx, &r.store.currencies, i)
+		now := time.Now()
+		existing.ApiID = currency.ApiID
+		existing.Interval = currency.Interval
+		existing.UpdatedAt = now
+		currency.UpdatedAt = now
+		return nil
+	}
+	return repository.ErrConflict
+}
+
+func (r *CurrencyRepository) Transition(ctx context.Context, transition *models.CurrencyTransition) error {
+	defer r.store.lock(r.tx)()
+
+	for i := range r.store.currencies {
+		currency := &r.store.currencies[i]
+		if currency.ID != transition.CurrencyID || currency.Status != transition.FromStatus {
+			continue
+		}
+		keepRow(r.tx, &r.store.currencies, i)
+
+		now := time.Now()
+		currency.Status = transition.ToStatus
+		currency.UpdatedAt = now
+		currency.DeletedAt = gorm.DeletedAt{}
+		if transition.ToStatus == models.CurrencyStatusDeleted {
+			currency.DeletedAt = gorm.DeletedAt{Time: now, Valid: true}
+		}
+
+		r.store.transitionSeq++
+		transition.ID = r.store.transitionSeq
+		transition.CreatedAt = now
+		appendRow(r.tx, &r.store.transitions, *transition)
+		return nil
+	}
+	return repository.ErrConflict
+}
+
+func (r *CurrencyRepository) ListTransitions(ctx context.Context, currencyID uint) ([]models.CurrencyTransition, error) {
+	defer r.store.rlock(r.tx)()
+
+	var transitions []models.CurrencyTransition
+	for _, transition := range r.store.transitions {
+		if transition.CurrencyID == currencyID {
+			transitions = append(transitions, transition)
+		}
+	}
+	return transitions, nil
+}
@@ -0,0 +1,139 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"crypto-price-tracker-app/internal/domain/models"
+	"crypto-price-tracker-app/internal/domain/repository"
+	"crypto-price-tracker-app/internal/domain/repository/repositorytest"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCurrencyRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewCurrencyRepository(NewStore())
+
+	btc := &models.Currency{Symbol: "BTC", ApiID: "bitcoin"}
+	require.NoError(t, repo.Create(ctx, btc))
+	assert.NotZero(t, btc.ID)
+	assert.Equal(t, models.DefaultPrecision, btc.Precision)
+	assert.Equal(t, models.CurrencyStatusActive, btc.Status)
+
+	assert.ErrorIs(t, repo.Create(ctx, &models.Currency{Symbol: "BTC"}), repository.ErrDuplicate)
+
+	require.NoError(t, repo.Transition(ctx, &models.CurrencyTransition{CurrencyID: btc.ID, FromStatus: models.CurrencyStatusActive, ToStatus: models.CurrencyStatusPaused}))
+	active, err := repo.GetAllActive(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, active)
+
+	require.NoError(t, repo.Transition(ctx, &models.CurrencyTransition{CurrencyID: btc.ID, FromStatus: models.CurrencyStatusPaused, ToStatus: models.CurrencyStatusDeleted}))
+	_, err = repo.GetBySymbol(ctx, "BTC")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	assert.ErrorIs(t, repo.Create(ctx, &models.Currency{Symbol: "BTC"}), repository.ErrDuplicate)
+}
+
+func TestPriceRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewPriceRepository(NewStore())
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	for i, value := range []string{"100", "105", "95"} {
+		require.NoError(t, repo.Create(ctx, &models.Price{CurrencyID: 1, Price: decimal.RequireFromString(value), Timestamp: base.Add(time.Duration(i) * time.Minute)}))
+	}
+	assert.ErrorIs(t, repo.Create(ctx, &models.Price{CurrencyID: 1, Timestamp: base}), repository.ErrDuplicate)
+
+	nearest, err := repo.GetNearestPrice(ctx, 1, base.Add(90*time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, "105", nearest.Price.String())
+
+	nearest, err = repo.GetNearestPrice(ctx, 1, base.Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, "100", nearest.Price.String())
+
+	_, err = repo.GetNearestPrice(ctx, 2, base)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	candles, err := repo.GetCandles(ctx, 1, base, base.Add(time.Hour), time.Hour)
+	require.NoError(t, err)
+	if assert.Len(t, candles, 1) {
+		assert.Equal(t, "100", candles[0].Open.String())
+		assert.Equal(t, "105", candles[0].High.String())
+		assert.Equal(t, "95", candles[0].Low.String())
+		assert.Equal(t, "95", candles[0].Close.String())
+		assert.Equal(t, int64(3), candles[0].SampleCount)
+	}
+
+	result, err := repo.CreateBatch(ctx, []models.Price{
+		{CurrencyID: 1, Price: decimal.RequireFromString("100"), Timestamp: base},
+		{CurrencyID: 1, Price: decimal.RequireFromString("106"), Timestamp: base.Add(time.Minute)},
+		{CurrencyID: 1, Price: decimal.RequireFromString("90"), Timestamp: base.Add(3 * time.Minute)},
+		{CurrencyID: 1, Price: decimal.RequireFromString("91"), Timestamp: base.Add(3 * time.Minute)},
+	}, models.ConflictUpdate)
+	require.NoError(t, err)
+	assert.Equal(t, models.BatchResult{Inserted: 1, Updated: 1, Skipped: 2}, *result)
+
+	latest, err := repo.GetLatestPrice(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "91", latest.Price.String())
+}
+
+func TestUnitOfWorkKeepsWritesMadeOutside(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore()
+	prices := NewPriceRepository(store)
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, prices.Create(ctx, &models.Price{CurrencyID: 1, Price: decimal.RequireFromString("100"), Timestamp: base}))
+
+	written := make(chan error, 1)
+	err := NewUnitOfWork(store).WithTx(ctx, func(tx repository.Repositories) error {
+		if _, err := tx.Prices.CreateBatch(ctx, []models.Price{
+			{CurrencyID: 1, Price: decimal.RequireFromString("101"), Timestamp: base},
+			{CurrencyID: 1, Price: decimal.RequireFromString("102"), Timestamp: base.Add(time.Minute)},
+		}, models.ConflictUpdate); err != nil {
+			return err
+		}
+
+		// A writer outside the unit, like the in-process updater, waits for
+		// it instead of interleaving with it.
+		go func() {
+			written <- prices.Create(ctx, &models.Price{CurrencyID: 2, Price: decimal.RequireFromString("5"), Timestamp: base})
+		}()
+		select {
+		case err := <-written:
+			t.Errorf("write outside the unit of work did not wait: %v", err)
+			written <- err
+		case <-time.After(50 * time.Millisecond):
+		}
+		return errors.New("review failed")
+	})
+	require.EqualError(t, err, "review failed")
+	require.NoError(t, <-written)
+
+	latest, err := prices.GetLatestPrice(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "100", latest.Price.String())
+	assert.True(t, base.Equal(latest.Timestamp))
+
+	latest, err = prices.GetLatestPrice(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "5", latest.Price.String())
+}
+
+func TestRepositoryContract(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) repositorytest.Repositories {
+		store := NewStore()
+		return repositorytest.Repositories{
+			Currencies: NewCurrencyRepository(store),
+			Prices:     NewPriceRepository(store),
+			Quarantine: NewQuarantineRepository(store),
+			Audit:      NewAuditRepository(store),
+			UnitOfWork: NewUnitOfWork(store),
+		}
+	})
+}
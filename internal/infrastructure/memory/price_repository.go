@@ -0,0 +1,321 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"crypto-price-tracker-app/internal/domain/models"
+	"crypto-price-tracker-app/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+// PriceRepository keeps raw prices only; there are no rollups in memory, so
+// every read is answered from the samples themselves.
+type PriceRepository struct {
+	store *Store
+	tx    *txn
+}
+
+func NewPriceRepository(store *Store) repository.PriceRepository {
+	return &PriceRepository{store: store}
+}
+
+func (r *PriceRepository) Create(ctx context.Context, price *models.Price) error {
+	defer r.store.lock(r.tx)()
+
+	if r.indexOf(price.CurrencyID, price.Timestamp) >= 0 {
+		return repository.ErrDuplicate
+	}
+	r.insert(price, time.Now())
+	return nil
+}
+
+func (r *PriceRepository) CreateBatch(ctx context.Context, prices []models.Price, onConflict models.ConflictAction) (*models.BatchResult, error) {
+	if onConflict != models.ConflictSkip && onConflict != models.ConflictUpdate {
+		return nil, fmt.Errorf("unsupported conflict action %q", onConflict)
+	}
+
+	defer r.store.lock(r.tx)()
+
+	// Collapse duplicates inside the batch to their last occurrence first, as
+	// the Postgres implementation does.
+	type key struct {
+		currencyID uint
+		timestamp  int64
+	}
+	index := make(map[key]int, len(prices))
+	unique := make([]models.Price, 0, len(prices))
+	for _, price := range prices {
+		k := key{price.CurrencyID, price.Timestamp.UnixNano()}
+		if i, ok := index[k]; ok {
+			unique[i] = price
+			continue
+		}
+		index[k] = len(unique)
+		unique = append(unique, price)
+	}
+
+	result := &models.BatchResult{Skipped: len(prices) - len(unique)}
+	now := time.Now()
+	for i := range unique {
+		price := &unique[i]
+		existing := r.indexOf(price.CurrencyID, price.Timestamp)
+		switch {
+		case existing < 0:
+			r.insert(price, now)
+			result.Inserted++
+		case onConflict == models.ConflictUpdate:
+			stored := &r.store.prices[existing]
+			if stored.Price.Equal(price.Price) && !stored.DeletedAt.Valid {
+				result.Skipped++
+				continue
+			}
+			keepRow(r.tx, &r.store.prices, existing)
+			stored.Price = price.Price
+			stored.UpdatedAt = now
+			stored.DeletedAt = gorm.DeletedAt{}
+			result.Updated++
+		default:
+			result.Skipped++
+		}
+	}
+	return result, nil
+}
+
+func (r *PriceRepository) GetByCurrencyAndTime(ctx context.Context, currencyID uint, timestamp time.Time) (*models.Price, error) {
+	defer r.store.rlock(r.tx)()
+
+	for _, price := range r.store.prices {
+		if price.CurrencyID == currencyID && price.Timestamp.Equal(timestamp) && !price.DeletedAt.Valid {
+			return &price, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+// GetNearestPrice prefers the last price at or before timestamp and falls
+// back to the first one after it.
+func (r *PriceRepository) GetNearestPrice(ctx context.Context, currencyID uint, timestamp time.Time) (*models.Price, error) {
+	before, after, err := r.GetSurroundingPrices(ctx, currencyID, timestamp)
+	if err != nil {
+		return nil, err
+	}
+	if before != nil {
+		return before, nil
+	}
+	return after, nil
+}
+
+func (r *PriceRepository) GetSurroundingPrices(ctx context.Context, currencyID uint, timestamp time.Time) (*models.Price, *models.Price, error) {
+	defer r.store.rlock(r.tx)()
+
+	surrounding := r.surroundingPrices(currencyID, timestamp)
+	if surrounding.Before == nil && surrounding.After == nil {
+		return nil, nil, repository.ErrNotFound
+	}
+	return surrounding.Before, surrounding.After, nil
+}
+
+func (r *PriceRepository) GetSurroundingPricesBatch(ctx context.Context, keys []models.PriceKey) ([]models.SurroundingPrices, error) {
+	defer r.store.rlock(r.tx)()
+
+	result := make([]models.SurroundingPrices, len(keys))
+	for i, key := range keys {
+		result[i] = r.surroundingPrices(key.CurrencyID, key.Timestamp)
+	}
+	return result, nil
+}
+
+// surroundingPrices must be called with the store locked.
+func (r *PriceRepository) surroundingPrices(currencyID uint, timestamp time.Time) models.SurroundingPrices {
+	var before, after *models.Price
+	for i := range r.store.prices {
+		price := &r.store.prices[i]
+		if price.CurrencyID != currencyID || price.DeletedAt.Valid {
+			continue
+		}
+		if !price.Timestamp.After(timestamp) && (before == nil || price.Timestamp.After(before.Timestamp)) {
+			before = price
+		}
+		if !price.Timestamp.Before(timestamp) && (after == nil || price.Timestamp.Before(after.Timestamp)) {
+			after = price
+		}
+	}
+	return models.SurroundingPrices{Before: clonePrice(before), After: clonePrice(after)}
+}
+
+func clonePrice(price *models.Price) *models.Price {
+	if price == nil {
+		return nil
+	}
+	result := *price
+	return &result
+}
+
+func (r *PriceRepository) GetLatestPrice(ctx context.Context, currencyID uint) (*models.Price, error) {
+	defer r.store.rlock(r.tx)()
+
+	var latest *models.Price
+	for i := range r.store.prices {
+		price := &r.store.prices[i]
+		if price.CurrencyID != currencyID || price.DeletedAt.Valid {
+			continue
+		}
+		if latest == nil || price.Timestamp.After(latest.Timestamp) {
+			latest = price
+		}
+	}
+	if latest == nil {
+		return nil, repository.ErrNotFound
+	}
+	result := *latest
+	return &result, nil
+}
+
+func (r *PriceRepository) GetLatestPrices(ctx context.Context, currencyIDs []uint) ([]models.Price, error) {
+	var prices []models.Price
+	for _, id := range currencyIDs {
+		price, err := r.GetLatestPrice(ctx, id)
+		if errors.Is(err, repository.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		prices = append(prices, *price)
+	}
+	sort.Slice(prices, func(i, j int) bool { return prices[i].CurrencyID < prices[j].CurrencyID })
+	return prices, nil
+}
+
+// GetPriceHistory returns prices with from <= timestamp <= to, oldest first.
+func (r *PriceRepository) GetPriceHistory(ctx context.Context, currencyID uint, from, to time.Time) ([]models.Price, error) {
+	defer r.store.rlock(r.tx)()
+
+	var prices []models.Price
+	for _, price := range r.store.prices {
+		if price.CurrencyID != currencyID || price.DeletedAt.Valid {
+			continue
+		}
+		if price.Timestamp.Before(from) || price.Timestamp.After(to) {
+			continue
+		}
+		prices = append(prices, price)
+	}
+	sort.Slice(prices, func(i, j int) bool {
+		return prices[i].Timestamp.Before(prices[j].Timestamp)
+	})
+	return prices, nil
+}
+
+func (r *PriceRepository) GetPriceHistoryPage(ctx context.Context, currencyID uint, query models.PriceHistoryQuery) ([]models.Price, error) {
+	query = query.ByBuckets()
+
+	unlock := r.store.rlock(r.tx)
+	var prices []models.Price
+	for _, price := range r.store.prices {
+		if price.CurrencyID != currencyID || price.DeletedAt.Valid {
+			continue
+		}
+		if price.Timestamp.Before(query.From) || !price.Timestamp.Before(query.To) {
+			continue
+		}
+		prices = append(prices, price)
+	}
+	unlock()
+
+	sort.Slice(prices, func(i, j int) bool { return priceBefore(prices[i], prices[j]) })
+	if query.Step > 0 {
+		sampled := prices[:0]
+		for _, price := range prices {
+			n := len(sampled)
+			if n == 0 || !models.CandleBucket(price.Timestamp, query.Step).Equal(models.CandleBucket(sampled[n-1].Timestamp, query.Step)) {
+				sampled = append(sampled, price)
+			}
+		}
+		prices = sampled
+	}
+	if query.Descending {
+		slices.Reverse(prices)
+	}
+
+	page := make([]models.Price, 0, query.Limit)
+	for _, price := range prices {
+		if query.After != nil {
+			cursor := models.Price{ID: query.After.ID, Timestamp: query.After.Timestamp}
+			if query.Descending && !priceBefore(price, cursor) || !query.Descending && !priceBefore(cursor, price) {
+				continue
+			}
+		}
+		page = append(page, price)
+		if len(page) == query.Limit {
+			break
+		}
+	}
+	return page, nil
+}
+
+// priceBefore orders prices by (timestamp, id).
+func priceBefore(a, b models.Price) bool {
+	if !a.Timestamp.Equal(b.Timestamp) {
+		return a.Timestamp.Before(b.Timestamp)
+	}
+	return a.ID < b.ID
+}
+
+// GetCandles buckets prices in [from, to) aligned to models.CandleOrigin.
+func (r *PriceRepository) GetCandles(ctx context.Context, currencyID uint, from, to time.Time, interval time.Duration) ([]models.Candle, error) {
+	history, err := r.GetPriceHistory(ctx, currencyID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	for len(history) > 0 && !history[len(history)-1].Timestamp.Before(to) {
+		history = history[:len(history)-1]
+	}
+	return models.BuildCandles(history, interval), nil
+}
+
+func (r *PriceRepository) DeleteByCurrency(ctx context.Context, currencyID uint) (int64, error) {
+	defer r.store.lock(r.tx)()
+
+	// The rows are copied rather than filtered in place, so a rollback can
+	// put the old slice back.
+	previous := r.store.prices
+	kept := make([]models.Price, 0, len(previous))
+	for _, price := range previous {
+		if price.CurrencyID != currencyID {
+			kept = append(kept, price)
+		}
+	}
+	r.store.prices = kept
+	r.tx.onRollback(func() { r.store.prices = previous })
+	return int64(len(previous) - len(kept)), nil
+}
+
+// indexOf looks for a row with the same key as the unique index on
+// (currency_id, timestamp), which also covers soft-deleted rows. Callers must
+// hold the lock.
+func (r *PriceRepository) indexOf(currencyID uint, timestamp time.Time) int {
+	for i, price := range r.store.prices {
+		if price.CurrencyID == currencyID && price.Timestamp.Equal(timestamp) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (r *PriceRepository) insert(price *models.Price, now time.Time) {
+	r.store.priceSeq++
+	price.ID = r.store.priceSeq
+	price.CreatedAt = now
+	price.UpdatedAt = now
+	stored := *price
+	stored.Currency = models.Currency{}
+	appendRow(r.tx, &r.store.prices, stored)
+}
@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"crypto-price-tracker-app/internal/domain/models"
+	"crypto-price-tracker-app/internal/domain/repository"
+)
+
+type QuarantineRepository struct {
+	store *Store
+	tx    *txn
+}
+
+func NewQuarantineRepository(store *Store) repository.QuarantineRepository {
+	return &QuarantineRepository{store: store}
+}
+
+func (r *QuarantineRepository) Create(ctx context.Context, sample *models.QuarantinedPrice) error {
+	defer r.store.lock(r.tx)()
+
+	if sample.Status == "" {
+		sample.Status = models.QuarantineStatusPending
+	}
+
+	now := time.Now()
+	r.store.sampleSeq++
+	sample.ID = r.store.sampleSeq
+	sample.CreatedAt = now
+	sample.UpdatedAt = now
+
+	stored := *sample
+	stored.Currency = models.Currency{}
+	appendRow(r.tx, &r.store.quarantine, stored)
+	return nil
+}
+
+func (r *QuarantineRepository) GetByID(ctx context.Context, id uint) (*models.QuarantinedPrice, error) {
+	defer r.store.rlock(r.tx)()
+
+	for _, sample := range r.store.quarantine {
+		if sample.ID == id {
+			sample.Currency, _ = r.store.currencyByID(sample.CurrencyID)
+			return &sample, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *QuarantineRepository) List(ctx context.Context, status string) ([]models.QuarantinedPrice, error) {
+	defer r.store.rlock(r.tx)()
+
+	var samples []models.QuarantinedPrice
+	for _, sample := range r.store.quarantine {
+		if status != "" && sample.Status != status {
+			continue
+		}
+		sample.Currency, _ = r.store.currencyByID(sample.CurrencyID)
+		samples = append(samples, sample)
+	}
+	sort.SliceStable(samples, func(i, j int) bool {
+		return samples[i].CreatedAt.After(samples[j].CreatedAt)
+	})
+	return samples, nil
+}
+
+func (r *QuarantineRepository) UpdateStatus(ctx context.Context, id uint, status string, reviewedAt time.Time) error {
+	defer r.store.lock(r.tx)()
+
+	for i := range r.store.quarantine {
+		sample := &r.store.quarantine[i]
+		if sample.ID != id || sample.Status != models.QuarantineStatusPending {
+			continue
+		}
+		keepRow(r.tx, &r.store.quarantine, i)
+		sample.Status = status
+		sample.ReviewedAt = &reviewedAt
+		sample.UpdatedAt = time.Now()
+		return nil
+	}
+	return repository.ErrConflict
+}
@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"sync"
+
+	"crypto-price-tracker-app/internal/domain/models"
+)
+
+// Store holds the state shared by the in-memory repositories. Like a database
+// it is meant to be created once per process and handed to every repository.
+type Store struct {
+	mu            sync.RWMutex
+	currencies    []models.Currency
+	prices        []models.Price
+	quarantine    []models.QuarantinedPrice
+	transitions   []models.CurrencyTransition
+	audit         []models.AuditEvent
+	currencySeq   uint
+	priceSeq      uint
+	sampleSeq     uint
+	transitionSeq uint
+	auditSeq      uint
+}
+
+func NewStore() *Store {
+	return &Store{}
+}
+
+// lock takes the write lock and returns its release. Repositories of a unit
+// of work, with tx set, run under the lock the unit already holds.
+func (s *Store) lock(tx *txn) func() {
+	if tx != nil {
+		return func() {}
+	}
+	s.mu.Lock()
+	return s.mu.Unlock
+}
+
+// rlock is lock for readers.
+func (s *Store) rlock(tx *txn) func() {
+	if tx != nil {
+		return func() {}
+	}
+	s.mu.RLock()
+	return s.mu.RUnlock
+}
+
+// currencyByID returns the live currency with id, mirroring how GORM preloads
+// skip soft-deleted rows. Callers must hold the lock.
+func (s *Store) currencyByID(id uint) (models.Currency, bool) {
+	for _, currency := range s.currencies {
+		if currency.ID == id && !currency.DeletedAt.Valid {
+			return currency, true
+		}
+	}
+	return models.Currency{}, false
+}
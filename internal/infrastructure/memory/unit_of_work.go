@@ -0,0 +1,73 @@
+package memory
+
+import (
+	"context"
+
+	"crypto-price-tracker-app/internal/domain/repository"
+)
+
+// UnitOfWork emulates transactions by holding the store lock for the whole
+// unit, so no other write can interleave with it, and undoing the unit's own
+// writes when fn fails. Writes made elsewhere wait for the unit to finish.
+type UnitOfWork struct {
+	store *Store
+}
+
+func NewUnitOfWork(store *Store) repository.UnitOfWork {
+	return &UnitOfWork{store: store}
+}
+
+// WithTx must not be called again from fn, and fn must only use the
+// repositories it is given: the store is already locked.
+func (u *UnitOfWork) WithTx(ctx context.Context, fn func(repos repository.Repositories) error) error {
+	u.store.mu.Lock()
+	defer u.store.mu.Unlock()
+
+	tx := &txn{}
+	err := fn(repository.Repositories{
+		Currencies: &CurrencyRepository{store: u.store, tx: tx},
+		Prices:     &PriceRepository{store: u.store, tx: tx},
+		Quarantine: &QuarantineRepository{store: u.store, tx: tx},
+		Audit:      &AuditRepository{store: u.store, tx: tx},
+	})
+	if err != nil {
+		tx.rollback()
+	}
+	return err
+}
+
+// txn is the undo log of a running unit of work. Its repositories run with
+// the store already locked. Sequences are not rolled back, as in Postgres.
+type txn struct {
+	undo []func()
+}
+
+// onRollback records how to revert a write. Outside a unit of work, where tx
+// is nil, writes are final and nothing is recorded.
+func (tx *txn) onRollback(undo func()) {
+	if tx != nil {
+		tx.undo = append(tx.undo, undo)
+	}
+}
+
+// rollback reverts the writes newest first, so every undo sees the store as
+// its write left it.
+func (tx *txn) rollback() {
+	for i := len(tx.undo) - 1; i >= 0; i-- {
+		tx.undo[i]()
+	}
+}
+
+// appendRow appends row to *rows; a rollback removes it again.
+func appendRow[T any](tx *txn, rows *[]T, row T) {
+	n := len(*rows)
+	*rows = append(*rows, row)
+	tx.onRollback(func() { *rows = (*rows)[:n] })
+}
+
+// keepRow must be called before (*rows)[i] changes; a rollback puts the
+// current value back.
+func keepRow[T any](tx *txn, rows *[]T, i int) {
+	old := (*rows)[i]
+	tx.onRollback(func() { (*rows)[i] = old })
+}
@@ -0,0 +1,90 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+const usage = "usage: migrate up | down [steps] | status | version | force <version>"
+
+// RunCommand executes a migrate subcommand as typed after the binary name,
+// e.g. "api migrate down 2".
+func RunCommand(ctx context.Context, m *Migrator, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf(usage)
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := m.Up(ctx)
+		for _, migration := range applied {
+			fmt.Fprintf(out, "applied %d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Fprintln(out, "no pending migrations")
+		}
+		return nil
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid number of steps %q", args[1])
+			}
+			steps = n
+		}
+		reverted, err := m.Down(ctx, steps)
+		for _, migration := range reverted {
+			fmt.Fprintf(out, "reverted %d_%s\n", migration.Version, migration.Name)
+		}
+		return err
+
+	case "status":
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			state := "pending"
+			if status.Applied {
+				state = "applied"
+			}
+			fmt.Fprintf(out, "%03d_%s\t%s\n", status.Version, status.Name, state)
+		}
+		return nil
+
+	case "version":
+		version, dirty, err := m.Version(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "version %d (latest %d)", version, m.Latest())
+		if dirty {
+			fmt.Fprint(out, " dirty")
+		}
+		fmt.Fprintln(out)
+		return nil
+
+	case "force":
+		if len(args) < 2 {
+			return fmt.Errorf(usage)
+		}
+		version, err := strconv.ParseUint(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+		if err := m.Force(ctx, uint(version)); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "forced version %d\n", version)
+		return nil
+	}
+
+	return fmt.Errorf("unknown migrate command %q, %s", args[0], usage)
+}
@@ -0,0 +1,269 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"gorm.io/gorm"
+)
+
+// The table layout matches golang-migrate, so databases migrated with the
+// migrate/migrate container are picked up without a re-baseline.
+const createVersionTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version BIGINT NOT NULL PRIMARY KEY,
+    dirty BOOLEAN NOT NULL
+)`
+
+var (
+	ErrDirty         = errors.New("database schema is dirty")
+	ErrSchemaDrift   = errors.New("database schema version does not match migrations")
+	ErrNoMigration   = errors.New("migration not found")
+	migrationPattern = regexp.MustCompile(`^(\d+)_(\w+?)(?:\.(\w+))?\.(up|down)\.sql$`)
+)
+
+type Migration struct {
+	Version uint
+	Name    string
+	Up      string
+	Down    string
+}
+
+type Status struct {
+	Migration
+	Applied bool
+}
+
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+func New(db *gorm.DB, fsys fs.FS) (*Migrator, error) {
+	migrations, err := Load(fsys, db.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Load reads NNN_name.up.sql / NNN_name.down.sql pairs from the root of fsys
+// and returns them ordered by version. A NNN_name.<dialect>.up.sql script
+// replaces the generic one for that dialect; scripts for other dialects are
+// ignored.
+func Load(fsys fs.FS, dialect string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := make(map[uint]*Migration)
+	overridden := make(map[string]bool)
+	for _, entry := range entries {
+		matches := migrationPattern.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || matches == nil {
+			continue
+		}
+		if matches[3] != "" && matches[3] != dialect {
+			continue
+		}
+
+		version, err := strconv.ParseUint(matches[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s: %w", entry.Name(), err)
+		}
+
+		body, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+
+		migration, ok := byVersion[uint(version)]
+		if !ok {
+			migration = &Migration{Version: uint(version), Name: matches[2]}
+			byVersion[uint(version)] = migration
+		} else if migration.Name != matches[2] {
+			return nil, fmt.Errorf("conflicting names for migration %d: %s and %s", version, migration.Name, matches[2])
+		}
+
+		key := matches[1] + "." + matches[4]
+		if overridden[key] {
+			continue
+		}
+		overridden[key] = matches[3] != ""
+
+		if matches[4] == "up" {
+			migration.Up = string(body)
+		} else {
+			migration.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up script", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+func (m *Migrator) Latest() uint {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Version returns the applied schema version; 0 means nothing is applied.
+func (m *Migrator) Version(ctx context.Context) (uint, bool, error) {
+	if err := m.db.WithContext(ctx).Exec(createVersionTable).Error; err != nil {
+		return 0, false, fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	var rows []struct {
+		Version uint
+		Dirty   bool
+	}
+	if err := m.db.WithContext(ctx).Raw("SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&rows).Error; err != nil {
+		return 0, false, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if len(rows) == 0 {
+		return 0, false, nil
+	}
+	return rows[0].Version, rows[0].Dirty, nil
+}
+
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	current, dirty, err := m.Version(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if dirty {
+		return nil, fmt.Errorf("%w at version %d, fix it manually and run force", ErrDirty, current)
+	}
+
+	var applied []Migration
+	for _, migration := range m.migrations {
+		if migration.Version <= current {
+			continue
+		}
+		if err := m.apply(ctx, migration.Up, migration.Version); err != nil {
+			return applied, fmt.Errorf("failed to apply migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		applied = append(applied, migration)
+	}
+	return applied, nil
+}
+
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	current, dirty, err := m.Version(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if dirty {
+		return nil, fmt.Errorf("%w at version %d, fix it manually and run force", ErrDirty, current)
+	}
+
+	var reverted []Migration
+	for i := len(m.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+		migration := m.migrations[i]
+		if migration.Version > current {
+			continue
+		}
+		if migration.Down == "" {
+			return reverted, fmt.Errorf("migration %d_%s has no down script", migration.Version, migration.Name)
+		}
+
+		var previous uint
+		if i > 0 {
+			previous = m.migrations[i-1].Version
+		}
+		if err := m.apply(ctx, migration.Down, previous); err != nil {
+			return reverted, fmt.Errorf("failed to revert migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		reverted = append(reverted, migration)
+		current = previous
+	}
+	return reverted, nil
+}
+
+// Force records version as applied and clean without running any SQL. It is
+// used to recover from a dirty state or to baseline an existing database.
+func (m *Migrator) Force(ctx context.Context, version uint) error {
+	if version != 0 && m.find(version) == nil {
+		return fmt.Errorf("%w: %d", ErrNoMigration, version)
+	}
+	if _, _, err := m.Version(ctx); err != nil {
+		return err
+	}
+	return m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return setVersion(tx, version)
+	})
+}
+
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	current, _, err := m.Version(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, len(m.migrations))
+	for i, migration := range m.migrations {
+		statuses[i] = Status{Migration: migration, Applied: migration.Version <= current}
+	}
+	return statuses, nil
+}
+
+// Check refuses to proceed unless the database is exactly at the latest
+// embedded version and not dirty.
+func (m *Migrator) Check(ctx context.Context) error {
+	current, dirty, err := m.Version(ctx)
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return fmt.Errorf("%w at version %d", ErrDirty, current)
+	}
+	if current != m.Latest() {
+		return fmt.Errorf("%w: database is at %d, binary expects %d", ErrSchemaDrift, current, m.Latest())
+	}
+	return nil
+}
+
+func (m *Migrator) apply(ctx context.Context, script string, version uint) error {
+	return m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(script).Error; err != nil {
+			return err
+		}
+		return setVersion(tx, version)
+	})
+}
+
+func (m *Migrator) find(version uint) *Migration {
+	for i := range m.migrations {
+		if m.migrations[i].Version == version {
+			return &m.migrations[i]
+		}
+	}
+	return nil
+}
+
+func setVersion(tx *gorm.DB, version uint) error {
+	if err := tx.Exec("DELETE FROM schema_migrations").Error; err != nil {
+		return err
+	}
+	if version == 0 {
+		return nil
+	}
+	return tx.Exec("INSERT INTO schema_migrations (version, dirty) VALUES (?, false)", version).Error
+}
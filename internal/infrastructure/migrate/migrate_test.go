@@ -0,0 +1,81 @@
+package migrate
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"crypto-price-tracker-app/migrations"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"002_second.up.sql":   {Data: []byte("CREATE TABLE b ();")},
+		"002_second.down.sql": {Data: []byte("DROP TABLE b;")},
+		"001_first.up.sql":    {Data: []byte("CREATE TABLE a ();")},
+		"001_first.down.sql":  {Data: []byte("DROP TABLE a;")},
+		"migrations.go":       {Data: []byte("package migrations")},
+	}
+
+	loaded, err := Load(fsys, "postgres")
+
+	require.NoError(t, err)
+	require.Len(t, loaded, 2)
+	assert.Equal(t, uint(1), loaded[0].Version)
+	assert.Equal(t, "first", loaded[0].Name)
+	assert.Equal(t, "DROP TABLE a;", loaded[0].Down)
+	assert.Equal(t, uint(2), loaded[1].Version)
+}
+
+func TestLoad_MissingUp(t *testing.T) {
+	fsys := fstest.MapFS{
+		"001_first.down.sql": {Data: []byte("DROP TABLE a;")},
+	}
+
+	_, err := Load(fsys, "postgres")
+
+	assert.Error(t, err)
+}
+
+func TestLoad_Dialect(t *testing.T) {
+	fsys := fstest.MapFS{
+		"001_first.up.sql":           {Data: []byte("CREATE TABLE a (id SERIAL);")},
+		"001_first.down.sql":         {Data: []byte("DROP TABLE a;")},
+		"001_first.sqlite.up.sql":    {Data: []byte("CREATE TABLE a (id INTEGER);")},
+		"001_first.mysql.up.sql":     {Data: []byte("CREATE TABLE a (id INT);")},
+		"002_second.sqlite.up.sql":   {Data: []byte("SELECT 1;")},
+		"002_second.up.sql":          {Data: []byte("CREATE TABLE b ();")},
+		"002_second.down.sql":        {Data: []byte("DROP TABLE b;")},
+		"002_second.sqlite.down.sql": {Data: []byte("SELECT 2;")},
+	}
+
+	loaded, err := Load(fsys, "sqlite")
+
+	require.NoError(t, err)
+	require.Len(t, loaded, 2)
+	assert.Equal(t, "first", loaded[0].Name)
+	assert.Equal(t, "CREATE TABLE a (id INTEGER);", loaded[0].Up)
+	assert.Equal(t, "DROP TABLE a;", loaded[0].Down)
+	assert.Equal(t, "SELECT 1;", loaded[1].Up)
+	assert.Equal(t, "SELECT 2;", loaded[1].Down)
+
+	loaded, err = Load(fsys, "postgres")
+
+	require.NoError(t, err)
+	assert.Equal(t, "CREATE TABLE a (id SERIAL);", loaded[0].Up)
+	assert.Equal(t, "DROP TABLE b;", loaded[1].Down)
+}
+
+func TestLoad_Embedded(t *testing.T) {
+	for _, dialect := range []string{"postgres", "sqlite"} {
+		loaded, err := Load(migrations.FS, dialect)
+
+		require.NoError(t, err)
+		for i, migration := range loaded {
+			assert.Equal(t, uint(i+1), migration.Version, "migration versions must be contiguous")
+			assert.NotEmpty(t, migration.Down, "migration %d_%s has no down script", migration.Version, migration.Name)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"crypto-price-tracker-app/internal/domain/models"
+	"crypto-price-tracker-app/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+// deleteChunk keeps each DELETE well below the bind parameter limit.
+const deleteChunk = 10000
+
+type PriceArchiveRepository struct {
+	db *gorm.DB
+}
+
+func NewPriceArchiveRepository(db *gorm.DB) repository.PriceArchiveRepository {
+	return &PriceArchiveRepository{db: db}
+}
+
+// ListArchivable includes currencies that were soft-deleted, since their
+// prices are still stored.
+func (r *PriceArchiveRepository) ListArchivable(ctx context.Context, before time.Time) ([]models.ArchiveGroup, error) {
+	var groups []models.ArchiveGroup
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT p.currency_id, c.symbol, date_trunc('month', p.timestamp, 'UTC') AS month, count(*) AS "rows"
+		FROM prices p
+		JOIN currencies c ON c.id = p.currency_id
+		WHERE p.deleted_at IS NULL AND p.timestamp < ?
+		GROUP BY 1, 2, 3
+		ORDER BY 1, 3`, before).Scan(&groups).Error
+	if err != nil {
+		return nil, err
+	}
+	for i := range groups {
+		groups[i].Month = groups[i].Month.UTC()
+	}
+	return groups, nil
+}
+
+func (r *PriceArchiveRepository) GetMonth(ctx context.Context, currencyID uint, month time.Time) ([]models.Price, error) {
+	return find[models.Price](r.db.WithContext(ctx).
+		Where("currency_id = ? AND timestamp >= ? AND timestamp < ?", currencyID, month, month.AddDate(0, 1, 0)).
+		Order("timestamp ASC"))
+}
+
+// DeleteArchived bounds the delete by the month so Postgres only touches
+// that month's partition.
+func (r *PriceArchiveRepository) DeleteArchived(ctx context.Context, currencyID uint, month time.Time, ids []uint) (int64, error) {
+	var deleted int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for start := 0; start < len(ids); start += deleteChunk {
+			result := tx.Unscoped().
+				Where("currency_id = ? AND timestamp >= ? AND timestamp < ?", currencyID, month, month.AddDate(0, 1, 0)).
+				Where("id IN ?", ids[start:min(start+deleteChunk, len(ids))]).
+				Delete(&models.Price{})
+			if result.Error != nil {
+				return result.Error
+			}
+			deleted += result.RowsAffected
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return deleted, nil
+}
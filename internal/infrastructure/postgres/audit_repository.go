@@ -0,0 +1,46 @@
+package postgres
+
+import (
+	"context"
+
+	"crypto-price-tracker-app/internal/domain/models"
+	"crypto-price-tracker-app/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+type AuditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) repository.AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+func (r *AuditRepository) Create(ctx context.Context, event *models.AuditEvent) error {
+	return r.db.WithContext(ctx).Create(event).Error
+}
+
+func (r *AuditRepository) List(ctx context.Context, filter models.AuditFilter) ([]models.AuditEvent, error) {
+	query := r.db.WithContext(ctx).Order("created_at DESC, id DESC")
+	for _, field := range []struct{ column, value string }{
+		{"actor", filter.Actor},
+		{"action", filter.Action},
+		{"target_type", filter.TargetType},
+		{"target_id", filter.TargetID},
+	} {
+		if field.value != "" {
+			query = query.Where(field.column+" = ?", field.value)
+		}
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", filter.To)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	return find[models.AuditEvent](query)
+}
@@ -2,7 +2,7 @@ package postgres
 
 import (
 	"context"
-	"errors"
+	"time"
 
 	"crypto-price-tracker-app/internal/domain/models"
 	"crypto-price-tracker-app/internal/domain/repository"
@@ -10,54 +10,86 @@ import (
 	"gorm.io/gorm"
 )
 
+// CurrencyRepository writes to db and reads through replicas, which may be nil.
 type CurrencyRepository struct {
-	db *gorm.DB
+	db       *gorm.DB
+	replicas *ReplicaSet
 }
 
-func NewCurrencyRepository(db *gorm.DB) repository.CurrencyRepository {
-	return &CurrencyRepository{db: db}
+func NewCurrencyRepository(db *gorm.DB, replicas *ReplicaSet) repository.CurrencyRepository {
+	return &CurrencyRepository{db: db, replicas: replicas}
 }
 
-func (r *CurrencyRepository) Create(ctx context.Context, currency interface{}) error {
-	currencyModel := currency.(*models.Currency)
-	return r.db.WithContext(ctx).Create(currencyModel).Error
+func (r *CurrencyRepository) Create(ctx context.Context, currency *models.Currency) error {
+	return translate(r.db.WithContext(ctx).Create(currency).Error)
 }
 
-func (r *CurrencyRepository) GetBySymbol(ctx context.Context, symbol string) (interface{}, error) {
-	var currency models.Currency
-	err := r.db.WithContext(ctx).Where("symbol = ?", symbol).First(&currency).Error
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, nil
-		}
-		return nil, err
-	}
-	return &currency, nil
+func (r *CurrencyRepository) GetBySymbol(ctx context.Context, symbol string) (*models.Currency, error) {
+	return read(ctx, r.replicas, r.db, func(db *gorm.DB) (*models.Currency, error) {
+		return first[models.Currency](db.Where("symbol = ?", symbol))
+	})
 }
 
-func (r *CurrencyRepository) GetAllActive(ctx context.Context) ([]interface{}, error) {
-	var currencies []models.Currency
-	err := r.db.WithContext(ctx).Where("is_active = ?", true).Find(&currencies).Error
-	if err != nil {
-		return nil, err
+func (r *CurrencyRepository) GetBySymbols(ctx context.Context, symbols []string) ([]models.Currency, error) {
+	if len(symbols) == 0 {
+		return nil, nil
 	}
+	return read(ctx, r.replicas, r.db, func(db *gorm.DB) ([]models.Currency, error) {
+		return find[models.Currency](db.Where("symbol IN ?", symbols))
+	})
+}
 
-	result := make([]interface{}, len(currencies))
-	for i, currency := range currencies {
-		result[i] = &currency
-	}
-	return result, nil
+func (r *CurrencyRepository) GetDeletedBySymbol(ctx context.Context, symbol string) (*models.Currency, error) {
+	return first[models.Currency](r.db.WithContext(ctx).Unscoped().
+		Where("symbol = ? AND status = ?", symbol, models.CurrencyStatusDeleted))
+}
+
+func (r *CurrencyRepository) GetAllActive(ctx context.Context) ([]models.Currency, error) {
+	return read(ctx, r.replicas, r.db, func(db *gorm.DB) ([]models.Currency, error) {
+		return find[models.Currency](db.Where("status = ?", models.CurrencyStatusActive))
+	})
 }
 
-func (r *CurrencyRepository) Update(ctx context.Context, currency interface{}) error {
-	currencyModel := currency.(*models.Currency)
-	return r.db.WithContext(ctx).Save(currencyModel).Error
+func (r *CurrencyRepository) Update(ctx context.Context, currency *models.Currency) error {
+	now := time.Now()
+	result := r.db.WithContext(ctx).Model(&models.Currency{}).
+		Where("id = ? AND status = ?", currency.ID, currency.Status).
+		Updates(map[string]interface{}{"api_id": currency.ApiID, "interval": currency.Interval, "updated_at": now})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repository.ErrConflict
+	}
+	currency.UpdatedAt = now
+	return nil
 }
 
-func (r *CurrencyRepository) Delete(ctx context.Context, symbol string) error {
-	return r.db.WithContext(ctx).Where("symbol = ?", symbol).Delete(&models.Currency{}).Error
+// Transition keeps deleted_at in step with the deleted state, so GORM's soft
+// delete hides deleted currencies from every other query.
+func (r *CurrencyRepository) Transition(ctx context.Context, transition *models.CurrencyTransition) error {
+	return translate(r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		updates := map[string]interface{}{"status": transition.ToStatus, "updated_at": now, "deleted_at": nil}
+		if transition.ToStatus == models.CurrencyStatusDeleted {
+			updates["deleted_at"] = now
+		}
+
+		result := tx.Unscoped().Model(&models.Currency{}).
+			Where("id = ? AND status = ?", transition.CurrencyID, transition.FromStatus).
+			Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return repository.ErrConflict
+		}
+		return tx.Create(transition).Error
+	}))
 }
 
-func (r *CurrencyRepository) Deactivate(ctx context.Context, symbol string) error {
-	return r.db.WithContext(ctx).Model(&models.Currency{}).Where("symbol = ?", symbol).Update("is_active", false).Error
+func (r *CurrencyRepository) ListTransitions(ctx context.Context, currencyID uint) ([]models.CurrencyTransition, error) {
+	return find[models.CurrencyTransition](r.db.WithContext(ctx).
+		Where("currency_id = ?", currencyID).
+		Order("created_at ASC, id ASC"))
 }
@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"crypto-price-tracker-app/internal/domain/repository"
+
+	"github.com/jackc/pgx/v5"
+	"gorm.io/gorm"
+)
+
+// partitionName matches the monthly partitions made by
+// create_prices_partition, such as prices_2024_03.
+var partitionName = regexp.MustCompile(`^prices_(\d{4}_\d{2})$`)
+
+type PartitionManager struct {
+	db *gorm.DB
+}
+
+func NewPartitionManager(db *gorm.DB) repository.PartitionManager {
+	return &PartitionManager{db: db}
+}
+
+// EnsurePartitions creates monthly partitions of prices from the month of
+// from up to monthsAhead months later. Existing partitions are left alone.
+func (m *PartitionManager) EnsurePartitions(ctx context.Context, from time.Time, monthsAhead int) ([]string, error) {
+	month := monthStart(from)
+	var partitions []string
+	for i := 0; i <= monthsAhead; i++ {
+		var name string
+		if err := m.db.WithContext(ctx).Raw("SELECT create_prices_partition(?::date)", month.AddDate(0, i, 0).Format("2006-01-02")).Scan(&name).Error; err != nil {
+			return partitions, fmt.Errorf("failed to create partition for %s: %w", month.AddDate(0, i, 0).Format("2006-01"), err)
+		}
+		partitions = append(partitions, name)
+	}
+	return partitions, nil
+}
+
+// ExpirePartitions detaches every monthly partition that ends on or before
+// cutoff, and drops it as well when drop is set.
+func (m *PartitionManager) ExpirePartitions(ctx context.Context, cutoff time.Time, drop bool) ([]string, error) {
+	var names []string
+	err := m.db.WithContext(ctx).Raw(`
+		SELECT child.relname
+		FROM pg_inherits
+		JOIN pg_class parent ON parent.oid = pg_inherits.inhparent
+		JOIN pg_class child ON child.oid = pg_inherits.inhrelid
+		WHERE parent.relname = 'prices'
+		ORDER BY child.relname`).Scan(&names).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list partitions: %w", err)
+	}
+
+	var expired []string
+	for _, name := range names {
+		month, ok := partitionMonth(name)
+		if !ok || month.AddDate(0, 1, 0).After(cutoff) {
+			continue
+		}
+
+		table := pgx.Identifier{name}.Sanitize()
+		if err := m.db.WithContext(ctx).Exec("ALTER TABLE prices DETACH PARTITION " + table).Error; err != nil {
+			return expired, fmt.Errorf("failed to detach partition %s: %w", name, err)
+		}
+		if drop {
+			if err := m.db.WithContext(ctx).Exec("DROP TABLE " + table).Error; err != nil {
+				return expired, fmt.Errorf("failed to drop partition %s: %w", name, err)
+			}
+		}
+		expired = append(expired, name)
+	}
+	return expired, nil
+}
+
+// partitionMonth returns the month of a monthly partition. Any other child
+// table of prices is reported as not a partition and left alone.
+func partitionMonth(name string) (time.Time, bool) {
+	match := partitionName.FindStringSubmatch(name)
+	if match == nil {
+		return time.Time{}, false
+	}
+	month, err := time.Parse("2006_01", match[1])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return month, true
+}
+
+func monthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,28 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPartitionMonth(t *testing.T) {
+	tests := []struct {
+		name string
+		want time.Time
+		ok   bool
+	}{
+		{"prices_2024_03", time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), true},
+		{"prices_2024_13", time.Time{}, false},
+		{"prices_default", time.Time{}, false},
+		{"prices_2024_03_old", time.Time{}, false},
+		{"2024_03", time.Time{}, false},
+		{`prices_2024_03" CASCADE`, time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := partitionMonth(tt.name)
+		if ok != tt.ok || !got.Equal(tt.want) {
+			t.Errorf("partitionMonth(%q) = %s, %v; want %s, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
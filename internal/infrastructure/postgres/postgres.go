@@ -2,9 +2,7 @@ package postgres
 
 import (
 	"fmt"
-	"log"
-
-	"crypto-price-tracker-app/internal/domain/models"
+	stdlog "log"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -20,22 +18,35 @@ type Config struct {
 	SSLMode  string
 }
 
-func NewConnection(config *Config) (*gorm.DB, error) {
-	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		config.Host, config.Port, config.User, config.Password, config.DBName, config.SSLMode)
+func (c *Config) DSN() string {
+	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		c.Host, c.Port, c.User, c.Password, c.DBName, c.SSLMode)
+}
+
+// NewConnection connects to the database described by config. A nil log
+// discards GORM output.
+func NewConnection(config *Config, log logger.Interface) (*gorm.DB, error) {
+	db, err := Open(config.DSN(), log)
+	if err != nil {
+		return nil, err
+	}
+
+	stdlog.Println("Successfully connected to PostgreSQL database")
+	return db, nil
+}
 
+// Open connects with a libpq connection string or URL, as used for replicas.
+func Open(dsn string, log logger.Interface) (*gorm.DB, error) {
+	if log == nil {
+		log = logger.Discard
+	}
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
+		Logger:         log,
+		TranslateError: true,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
-
-	if err := db.AutoMigrate(&models.Currency{}, &models.Price{}); err != nil {
-		return nil, fmt.Errorf("failed to migrate database: %w", err)
-	}
-
-	log.Println("Successfully connected to PostgreSQL database")
 	return db, nil
 }
 
@@ -49,6 +60,6 @@ func CloseConnection(db *gorm.DB) error {
 		return fmt.Errorf("failed to close database connection: %w", err)
 	}
 
-	log.Println("Database connection closed")
+	stdlog.Println("Database connection closed")
 	return nil
 }
@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"crypto-price-tracker-app/internal/domain/models"
+	"crypto-price-tracker-app/internal/domain/repository"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// PriceEventsChannel is the NOTIFY channel price events are sent on.
+const PriceEventsChannel = "price_events"
+
+const maxListenBackoff = 30 * time.Second
+
+// notify sends event on tx; Postgres delivers it only if tx commits.
+func notify(tx *gorm.DB, event models.PriceEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return tx.Exec("SELECT pg_notify(?, ?)", PriceEventsChannel, string(payload)).Error
+}
+
+// PriceListener receives price events over a dedicated connection, since
+// LISTEN does not survive being returned to a pool.
+type PriceListener struct {
+	dsn    string
+	logger *zap.Logger
+}
+
+func NewPriceListener(dsn string, logger *zap.Logger) repository.PriceEventSource {
+	return &PriceListener{dsn: dsn, logger: logger}
+}
+
+// Listen reconnects with exponential backoff whenever the connection drops.
+func (l *PriceListener) Listen(ctx context.Context, handle func(models.PriceEvent), resync func()) error {
+	backoff := time.Second
+	for {
+		err := l.listen(ctx, handle, resync, func() { backoff = time.Second })
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		l.logger.Warn("Price event listener disconnected, reconnecting", zap.Duration("backoff", backoff), zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxListenBackoff)
+	}
+}
+
+func (l *PriceListener) listen(ctx context.Context, handle func(models.PriceEvent), resync func(), connected func()) error {
+	conn, err := pgx.Connect(ctx, l.dsn)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+PriceEventsChannel); err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+	connected()
+	l.logger.Info("Listening for price events", zap.String("channel", PriceEventsChannel))
+	resync()
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		event, err := decodePriceEvent([]byte(notification.Payload))
+		if err != nil {
+			l.logger.Warn("Skipping price event", zap.String("payload", notification.Payload), zap.Error(err))
+			continue
+		}
+		handle(event)
+	}
+}
+
+var errUnsupportedVersion = errors.New("unsupported payload version")
+
+func decodePriceEvent(payload []byte) (models.PriceEvent, error) {
+	var event models.PriceEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return event, err
+	}
+	if event.Version != models.PriceEventVersion {
+		return event, fmt.Errorf("%w %d", errUnsupportedVersion, event.Version)
+	}
+	return event, nil
+}
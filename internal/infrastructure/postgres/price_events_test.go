@@ -0,0 +1,44 @@
+package postgres
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"crypto-price-tracker-app/internal/domain/models"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestDecodePriceEvent(t *testing.T) {
+	price := &models.Price{
+		CurrencyID: 7,
+		Price:      decimal.RequireFromString("65000.12345678"),
+		Timestamp:  time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC),
+	}
+	payload, err := json.Marshal(models.NewPriceCreatedEvent(price))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	event, err := decodePriceEvent(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.Type != models.PriceEventCreated || event.CurrencyID != 7 {
+		t.Errorf("unexpected event %+v", event)
+	}
+	if !event.Price.Equal(price.Price) || !event.Timestamp.Equal(price.Timestamp) {
+		t.Errorf("price or timestamp changed: %s at %s", event.Price, event.Timestamp)
+	}
+
+	_, err = decodePriceEvent([]byte(`{"v":2,"type":"price.created","currency_id":7}`))
+	if !errors.Is(err, errUnsupportedVersion) {
+		t.Errorf("expected unsupported version, got %v", err)
+	}
+
+	if _, err := decodePriceEvent([]byte(`not json`)); err == nil {
+		t.Error("expected an error for a malformed payload")
+	}
+}
@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"crypto-price-tracker-app/internal/domain/models"
+
+	"gorm.io/gorm"
+)
+
+// GetPriceHistoryPage walks the (currency_id, timestamp) index from the
+// cursor, so without Step every page costs the same however deep it is. With
+// Step, row_number() ranks every price from the cursor to the far end of the
+// range before the first of each bucket is known, so a page costs in
+// proportion to what is left of the range. The window cannot be cut at Limit
+// buckets, as empty buckets would then end a page early.
+func (r *PriceRepository) GetPriceHistoryPage(ctx context.Context, currencyID uint, query models.PriceHistoryQuery) ([]models.Price, error) {
+	return read(ctx, r.replicas, r.db, func(db *gorm.DB) ([]models.Price, error) {
+		bucket := ""
+		var bucketArgs []interface{}
+		if query.Step > 0 {
+			bucket = "date_bin(?::interval, timestamp, ?)"
+			bucketArgs = []interface{}{fmt.Sprintf("%d seconds", int64(query.Step.Seconds())), models.CandleOrigin}
+		}
+		return find[models.Price](PriceHistoryPage(db, currencyID, query, bucket, bucketArgs...))
+	})
+}
+
+// PriceHistoryPage builds the query behind GetPriceHistoryPage. bucket is the
+// SQL expression grouping timestamps into query.Step-long buckets, with its
+// arguments; it is only used when Step is set.
+func PriceHistoryPage(db *gorm.DB, currencyID uint, query models.PriceHistoryQuery, bucket string, bucketArgs ...interface{}) *gorm.DB {
+	query = query.ByBuckets()
+	order, after := "ASC", ">"
+	if query.Descending {
+		order, after = "DESC", "<"
+	}
+
+	rows := db.Model(&models.Price{}).
+		Where("currency_id = ? AND timestamp >= ? AND timestamp < ?", currencyID, query.From.UTC(), query.To.UTC())
+	if query.After != nil {
+		rows = rows.Where("(timestamp, id) "+after+" (?, ?)", query.After.Timestamp.UTC(), query.After.ID)
+	}
+
+	if query.Step > 0 {
+		// The first price of a bucket is picked in ascending order whatever
+		// the page order, so both directions see the same samples.
+		rows = rows.Select("prices.*, row_number() OVER (PARTITION BY "+bucket+" ORDER BY timestamp ASC, id ASC) AS bucket_rank", bucketArgs...)
+		rows = db.Table("(?) AS prices", rows).
+			Select("id, currency_id, price, timestamp, created_at, updated_at, deleted_at").
+			Where("bucket_rank = 1")
+	}
+
+	return rows.
+		Order(fmt.Sprintf("timestamp %s, id %s", order, order)).
+		Limit(query.Limit)
+}
@@ -0,0 +1,278 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"crypto-price-tracker-app/internal/domain/models"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// lookupRow casts the VALUES row of a batch lookup key, since PostgreSQL
+// cannot infer parameter types there.
+const lookupRow = "(?::integer, ?::bigint, ?::timestamptz)"
+
+// GetSurroundingPricesBatch picks the resolution of every key as
+// GetSurroundingPrices does, then answers the keys of each resolution with
+// one query.
+func (r *PriceRepository) GetSurroundingPricesBatch(ctx context.Context, keys []models.PriceKey) ([]models.SurroundingPrices, error) {
+	return read(ctx, r.replicas, r.db, func(db *gorm.DB) ([]models.SurroundingPrices, error) {
+		return r.on(db).getSurroundingPricesBatch(ctx, keys)
+	})
+}
+
+func (r *PriceRepository) getSurroundingPricesBatch(ctx context.Context, keys []models.PriceKey) ([]models.SurroundingPrices, error) {
+	result := make([]models.SurroundingPrices, len(keys))
+	if len(keys) == 0 {
+		return result, nil
+	}
+
+	coverage, err := r.resolveCoverage(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+	groups := make(map[models.Resolution][]int)
+	for i, key := range keys {
+		resolution := pickResolution(coverage[key.CurrencyID], key.Timestamp, 0)
+		groups[resolution] = append(groups[resolution], i)
+	}
+
+	for resolution, indexes := range groups {
+		group := make([]models.PriceKey, len(indexes))
+		for j, i := range indexes {
+			group[j] = keys[i]
+		}
+
+		var prices []models.SurroundingPrices
+		if resolution == models.ResolutionRaw {
+			prices, err = surroundingPricesBatch(r.db.WithContext(ctx), group)
+		} else {
+			prices, err = r.getSurroundingRollupPricesBatch(ctx, group, resolution)
+		}
+		if err != nil {
+			return nil, err
+		}
+		for j, i := range indexes {
+			result[i] = prices[j]
+		}
+	}
+	return result, nil
+}
+
+// resolveCoverage reads the coverage of every currency among keys, ordered
+// from the finest resolution as pickResolution expects.
+func (r *PriceRepository) resolveCoverage(ctx context.Context, keys []models.PriceKey) (map[uint][]resolutionCoverage, error) {
+	seen := make(map[uint]bool)
+	var ids []uint
+	for _, key := range keys {
+		if !seen[key.CurrencyID] {
+			seen[key.CurrencyID] = true
+			ids = append(ids, key.CurrencyID)
+		}
+	}
+
+	var rows []resolutionCoverage
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT currency_id, 'raw' AS resolution, min(timestamp) AS starts_at FROM prices
+		WHERE currency_id IN @ids AND deleted_at IS NULL GROUP BY currency_id
+		UNION ALL SELECT currency_id, '1m', min(first_at) FROM price_rollups_1m WHERE currency_id IN @ids GROUP BY currency_id
+		UNION ALL SELECT currency_id, '1h', min(first_at) FROM price_rollups_1h WHERE currency_id IN @ids GROUP BY currency_id
+		UNION ALL SELECT currency_id, '1d', min(first_at) FROM price_rollups_1d WHERE currency_id IN @ids GROUP BY currency_id`,
+		sql.Named("ids", ids)).Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve price resolution: %w", err)
+	}
+
+	slices.SortStableFunc(rows, func(a, b resolutionCoverage) int {
+		return int(a.Resolution.Duration() - b.Resolution.Duration())
+	})
+	coverage := make(map[uint][]resolutionCoverage, len(ids))
+	for _, row := range rows {
+		coverage[row.CurrencyID] = append(coverage[row.CurrencyID], row)
+	}
+	return coverage, nil
+}
+
+// surroundingPricesBatch answers GetSurroundingPricesBatch from raw prices.
+// Each key costs two LATERAL probes of the (currency_id, timestamp) index that
+// return the rows themselves. As they are bounded by the key's timestamp, they
+// are pruned to the partitions on their side of it, and stop at the first
+// partition holding a match.
+func surroundingPricesBatch(db *gorm.DB, keys []models.PriceKey) ([]models.SurroundingPrices, error) {
+	result := make([]models.SurroundingPrices, len(keys))
+	for start := 0; start < len(keys); start += batchSize {
+		values, args := lookupValues(keys[start:min(start+batchSize, len(keys))], start, lookupRow)
+
+		var rows []struct {
+			Idx  int
+			Side string
+			models.Price
+		}
+		err := db.Raw(`
+			WITH lookups (idx, currency_id, moment) AS (VALUES `+values+`)
+			SELECT lookups.idx, 'before' AS side, earlier.*
+			FROM lookups CROSS JOIN LATERAL (
+				SELECT * FROM prices
+				WHERE prices.currency_id = lookups.currency_id AND prices.timestamp <= lookups.moment AND prices.deleted_at IS NULL
+				ORDER BY prices.timestamp DESC LIMIT 1) earlier
+			UNION ALL
+			SELECT lookups.idx, 'after' AS side, later.*
+			FROM lookups CROSS JOIN LATERAL (
+				SELECT * FROM prices
+				WHERE prices.currency_id = lookups.currency_id AND prices.timestamp >= lookups.moment AND prices.deleted_at IS NULL
+				ORDER BY prices.timestamp ASC LIMIT 1) later`, args...).Scan(&rows).Error
+		if err != nil {
+			return nil, err
+		}
+
+		for _, row := range rows {
+			price := row.Price
+			if row.Side == "before" {
+				result[row.Idx].Before = &price
+			} else {
+				result[row.Idx].After = &price
+			}
+		}
+	}
+	return result, nil
+}
+
+// SurroundingPricesBatch is surroundingPricesBatch for dialects without
+// LATERAL, such as SQLite. keyRow is the VALUES row holding a key's index,
+// currency ID and timestamp, with whatever casts the dialect needs. The
+// matches are read back by ID, which on the partitioned prices table would
+// search every partition, so PostgreSQL does not use it. Each key costs two
+// index probes, and each chunk of keys two queries.
+func SurroundingPricesBatch(db *gorm.DB, keys []models.PriceKey, keyRow string) ([]models.SurroundingPrices, error) {
+	result := make([]models.SurroundingPrices, len(keys))
+	for start := 0; start < len(keys); start += batchSize {
+		values, args := lookupValues(keys[start:min(start+batchSize, len(keys))], start, keyRow)
+
+		var matches []struct {
+			Idx      int
+			BeforeID *uint
+			AfterID  *uint
+		}
+		err := db.Raw(`
+			WITH lookups (idx, currency_id, moment) AS (VALUES `+values+`)
+			SELECT idx,
+				(SELECT id FROM prices
+				 WHERE prices.currency_id = lookups.currency_id AND prices.timestamp <= lookups.moment AND prices.deleted_at IS NULL
+				 ORDER BY prices.timestamp DESC LIMIT 1) AS before_id,
+				(SELECT id FROM prices
+				 WHERE prices.currency_id = lookups.currency_id AND prices.timestamp >= lookups.moment AND prices.deleted_at IS NULL
+				 ORDER BY prices.timestamp ASC LIMIT 1) AS after_id
+			FROM lookups`, args...).Scan(&matches).Error
+		if err != nil {
+			return nil, err
+		}
+
+		seen := make(map[uint]bool)
+		var ids []uint
+		for _, match := range matches {
+			for _, id := range []*uint{match.BeforeID, match.AfterID} {
+				if id != nil && !seen[*id] {
+					seen[*id] = true
+					ids = append(ids, *id)
+				}
+			}
+		}
+		if len(ids) == 0 {
+			continue
+		}
+		prices, err := find[models.Price](db.Where("id IN ?", ids))
+		if err != nil {
+			return nil, err
+		}
+
+		byID := make(map[uint]models.Price, len(prices))
+		for _, price := range prices {
+			byID[price.ID] = price
+		}
+		lookup := func(id *uint) *models.Price {
+			if id == nil {
+				return nil
+			}
+			price, ok := byID[*id]
+			if !ok {
+				return nil
+			}
+			return &price
+		}
+		for _, match := range matches {
+			result[match.Idx] = models.SurroundingPrices{Before: lookup(match.BeforeID), After: lookup(match.AfterID)}
+		}
+	}
+	return result, nil
+}
+
+// getSurroundingRollupPricesBatch is the batch form of
+// getSurroundingRollupPrices.
+func (r *PriceRepository) getSurroundingRollupPricesBatch(ctx context.Context, keys []models.PriceKey, resolution models.Resolution) ([]models.SurroundingPrices, error) {
+	table := rollupTables[resolution]
+	result := make([]models.SurroundingPrices, len(keys))
+	for start := 0; start < len(keys); start += batchSize {
+		values, args := lookupValues(keys[start:min(start+batchSize, len(keys))], start, lookupRow)
+		args = append(args, fmt.Sprintf("%d seconds", int64(resolution.Duration().Seconds())))
+
+		var rows []struct {
+			Idx         int
+			CurrencyID  uint
+			BeforePrice decimal.NullDecimal
+			BeforeAt    *time.Time
+			AfterPrice  decimal.NullDecimal
+			AfterAt     *time.Time
+		}
+		err := r.db.WithContext(ctx).Raw(fmt.Sprintf(`
+			WITH lookups (idx, currency_id, moment) AS (VALUES %[1]s),
+			matches AS (
+				SELECT idx, currency_id,
+					(SELECT bucket FROM %[2]s rollups
+					 WHERE rollups.currency_id = lookups.currency_id AND rollups.bucket <= lookups.moment AND rollups.last_at <= lookups.moment
+					 ORDER BY rollups.bucket DESC LIMIT 1) AS before_bucket,
+					(SELECT bucket FROM %[2]s rollups
+					 WHERE rollups.currency_id = lookups.currency_id AND rollups.bucket >= lookups.moment - ?::interval AND rollups.first_at >= lookups.moment
+					 ORDER BY rollups.bucket ASC LIMIT 1) AS after_bucket
+				FROM lookups
+			)
+			SELECT matches.idx, matches.currency_id,
+				earlier.close AS before_price, earlier.last_at AS before_at,
+				later.open AS after_price, later.first_at AS after_at
+			FROM matches
+			LEFT JOIN %[2]s earlier ON earlier.currency_id = matches.currency_id AND earlier.bucket = matches.before_bucket
+			LEFT JOIN %[2]s later ON later.currency_id = matches.currency_id AND later.bucket = matches.after_bucket`,
+			values, table), args...).Scan(&rows).Error
+		if err != nil {
+			return nil, err
+		}
+
+		for _, row := range rows {
+			if row.BeforeAt != nil {
+				price := rollupPrice{CurrencyID: row.CurrencyID, Price: row.BeforePrice.Decimal, Timestamp: *row.BeforeAt}.toModel()
+				result[row.Idx].Before = &price
+			}
+			if row.AfterAt != nil {
+				price := rollupPrice{CurrencyID: row.CurrencyID, Price: row.AfterPrice.Decimal, Timestamp: *row.AfterAt}.toModel()
+				result[row.Idx].After = &price
+			}
+		}
+	}
+	return result, nil
+}
+
+// lookupValues renders keys as VALUES rows numbered from offset.
+func lookupValues(keys []models.PriceKey, offset int, keyRow string) (string, []interface{}) {
+	rows := make([]string, len(keys))
+	args := make([]interface{}, 0, 3*len(keys))
+	for i, key := range keys {
+		rows[i] = keyRow
+		args = append(args, offset+i, key.CurrencyID, key.Timestamp.UTC())
+	}
+	return strings.Join(rows, ", "), args
+}
@@ -2,99 +2,461 @@ package postgres
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"crypto-price-tracker-app/internal/domain/models"
 	"crypto-price-tracker-app/internal/domain/repository"
 
+	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
 )
 
+// PriceRepository reads the monthly-partitioned prices table. Every query
+// filters or orders by timestamp so Postgres can prune partitions. Writes go
+// to db; reads go through replicas, which may be nil.
 type PriceRepository struct {
-	db *gorm.DB
+	db       *gorm.DB
+	replicas *ReplicaSet
 }
 
-func NewPriceRepository(db *gorm.DB) repository.PriceRepository {
+func NewPriceRepository(db *gorm.DB, replicas *ReplicaSet) repository.PriceRepository {
+	return &PriceRepository{db: db, replicas: replicas}
+}
+
+// on returns a repository running every query on db, so a read and the
+// resolution lookup it depends on hit the same server.
+func (r *PriceRepository) on(db *gorm.DB) *PriceRepository {
 	return &PriceRepository{db: db}
 }
 
-func (r *PriceRepository) Create(ctx context.Context, price interface{}) error {
-	priceModel := price.(*models.Price)
-	return r.db.WithContext(ctx).Create(priceModel).Error
+func (r *PriceRepository) GetByCurrencyAndTime(ctx context.Context, currencyID uint, timestamp time.Time) (*models.Price, error) {
+	return read(ctx, r.replicas, r.db, func(db *gorm.DB) (*models.Price, error) {
+		return r.on(db).getByCurrencyAndTime(ctx, currencyID, timestamp)
+	})
+}
+
+func (r *PriceRepository) GetNearestPrice(ctx context.Context, currencyID uint, timestamp time.Time) (*models.Price, error) {
+	return read(ctx, r.replicas, r.db, func(db *gorm.DB) (*models.Price, error) {
+		return r.on(db).getNearestPrice(ctx, currencyID, timestamp)
+	})
+}
+
+func (r *PriceRepository) GetSurroundingPrices(ctx context.Context, currencyID uint, timestamp time.Time) (*models.Price, *models.Price, error) {
+	prices, err := read(ctx, r.replicas, r.db, func(db *gorm.DB) ([2]*models.Price, error) {
+		before, after, err := r.on(db).getSurroundingPrices(ctx, currencyID, timestamp)
+		return [2]*models.Price{before, after}, err
+	})
+	return prices[0], prices[1], err
+}
+
+func (r *PriceRepository) GetLatestPrice(ctx context.Context, currencyID uint) (*models.Price, error) {
+	return read(ctx, r.replicas, r.db, func(db *gorm.DB) (*models.Price, error) {
+		return r.on(db).getLatestPrice(ctx, currencyID)
+	})
+}
+
+func (r *PriceRepository) GetLatestPrices(ctx context.Context, currencyIDs []uint) ([]models.Price, error) {
+	if len(currencyIDs) == 0 {
+		return nil, nil
+	}
+	return read(ctx, r.replicas, r.db, func(db *gorm.DB) ([]models.Price, error) {
+		return find[models.Price](db.Raw(`
+			SELECT DISTINCT ON (currency_id) * FROM prices
+			WHERE currency_id IN ? AND deleted_at IS NULL
+			ORDER BY currency_id, timestamp DESC`, currencyIDs))
+	})
+}
+
+func (r *PriceRepository) GetPriceHistory(ctx context.Context, currencyID uint, from, to time.Time) ([]models.Price, error) {
+	return read(ctx, r.replicas, r.db, func(db *gorm.DB) ([]models.Price, error) {
+		return r.on(db).getPriceHistory(ctx, currencyID, from, to)
+	})
+}
+
+func (r *PriceRepository) GetCandles(ctx context.Context, currencyID uint, from, to time.Time, interval time.Duration) ([]models.Candle, error) {
+	return read(ctx, r.replicas, r.db, func(db *gorm.DB) ([]models.Candle, error) {
+		return r.on(db).getCandles(ctx, currencyID, from, to, interval)
+	})
+}
+
+// Create inserts price and notifies listeners in the same transaction.
+func (r *PriceRepository) Create(ctx context.Context, price *models.Price) error {
+	return translate(r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(price).Error; err != nil {
+			return err
+		}
+		return notify(tx, models.NewPriceCreatedEvent(price))
+	}))
+}
+
+// batchSize keeps each INSERT well below the 65535 bind parameter limit.
+const batchSize = 1000
+
+// CreateBatch inserts prices with multi-row INSERT ... ON CONFLICT on
+// (currency_id, timestamp) inside one transaction. With ConflictUpdate an
+// existing row is overwritten (and undeleted) only when it differs, so
+// re-running the same batch reports every row as skipped. Duplicates within
+// prices collapse to the last occurrence. Every currency with a changed row
+// gets one prices.changed event.
+func (r *PriceRepository) CreateBatch(ctx context.Context, prices []models.Price, onConflict models.ConflictAction) (*models.BatchResult, error) {
+	var conflict string
+	switch onConflict {
+	case models.ConflictSkip:
+		conflict = "ON CONFLICT (currency_id, timestamp) DO NOTHING"
+	case models.ConflictUpdate:
+		conflict = `ON CONFLICT (currency_id, timestamp) DO UPDATE SET
+			price = EXCLUDED.price,
+			updated_at = EXCLUDED.updated_at,
+			deleted_at = NULL
+		WHERE prices.price IS DISTINCT FROM EXCLUDED.price OR prices.deleted_at IS NOT NULL`
+	default:
+		return nil, fmt.Errorf("unsupported conflict action %q", onConflict)
+	}
+
+	unique := dedupePrices(prices)
+	result := &models.BatchResult{Skipped: len(prices) - len(unique)}
+	now := time.Now()
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var changed []uint
+		seen := make(map[uint]bool)
+		for start := 0; start < len(unique); start += batchSize {
+			chunk := unique[start:min(start+batchSize, len(unique))]
+
+			values := make([]string, len(chunk))
+			args := make([]interface{}, 0, len(chunk)*5)
+			for i, price := range chunk {
+				values[i] = "(?, ?, ?, ?, ?)"
+				args = append(args, price.CurrencyID, price.Price, price.Timestamp, now, now)
+			}
+
+			// xmax is zero only for freshly inserted tuples.
+			query := "INSERT INTO prices (currency_id, price, timestamp, created_at, updated_at) VALUES " +
+				strings.Join(values, ", ") + " " + conflict + " RETURNING currency_id, (xmax = 0) AS inserted"
+
+			var rows []struct {
+				CurrencyID uint
+				Inserted   bool
+			}
+			if err := tx.Raw(query, args...).Scan(&rows).Error; err != nil {
+				return err
+			}
+			for _, row := range rows {
+				if !seen[row.CurrencyID] {
+					seen[row.CurrencyID] = true
+					changed = append(changed, row.CurrencyID)
+				}
+				if row.Inserted {
+					result.Inserted++
+				} else {
+					result.Updated++
+				}
+			}
+			result.Skipped += len(chunk) - len(rows)
+		}
+
+		for _, currencyID := range changed {
+			if err := notify(tx, models.NewPricesChangedEvent(currencyID)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
-func (r *PriceRepository) GetByCurrencyAndTime(ctx context.Context, currencyID uint, timestamp time.Time) (interface{}, error) {
-	var price models.Price
-	err := r.db.WithContext(ctx).
-		Where("currency_id = ? AND timestamp = ?", currencyID, timestamp).
-		First(&price).Error
+// DeleteByCurrency removes the rollups with the prices; they are derived from
+// them and would otherwise keep answering lookups.
+func (r *PriceRepository) DeleteByCurrency(ctx context.Context, currencyID uint) (int64, error) {
+	var deleted int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Unscoped().Where("currency_id = ?", currencyID).Delete(&models.Price{})
+		if result.Error != nil {
+			return result.Error
+		}
+		deleted = result.RowsAffected
+
+		for _, table := range rollupTables {
+			if err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE currency_id = ?", table), currencyID).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, nil
+		return 0, err
+	}
+	return deleted, nil
+}
+
+func dedupePrices(prices []models.Price) []models.Price {
+	type key struct {
+		currencyID uint
+		timestamp  int64
+	}
+
+	index := make(map[key]int, len(prices))
+	unique := make([]models.Price, 0, len(prices))
+	for _, price := range prices {
+		k := key{price.CurrencyID, price.Timestamp.UnixNano()}
+		if i, ok := index[k]; ok {
+			unique[i] = price
+			continue
 		}
+		index[k] = len(unique)
+		unique = append(unique, price)
+	}
+	return unique
+}
+
+func (r *PriceRepository) getByCurrencyAndTime(ctx context.Context, currencyID uint, timestamp time.Time) (*models.Price, error) {
+	return first[models.Price](r.db.WithContext(ctx).
+		Where("currency_id = ? AND timestamp = ?", currencyID, timestamp))
+}
+
+// getNearestPrice prefers the last price at or before timestamp and falls
+// back to the first one after it.
+func (r *PriceRepository) getNearestPrice(ctx context.Context, currencyID uint, timestamp time.Time) (*models.Price, error) {
+	before, after, err := r.getSurroundingPrices(ctx, currencyID, timestamp)
+	if err != nil {
 		return nil, err
 	}
-	return &price, nil
+	if before != nil {
+		return before, nil
+	}
+	return after, nil
 }
 
-func (r *PriceRepository) GetNearestPrice(ctx context.Context, currencyID uint, timestamp time.Time) (interface{}, error) {
-	var price models.Price
+// getSurroundingPrices reads from the finest resolution that still holds data
+// for timestamp, so lookups older than raw retention fall back to rollups.
+func (r *PriceRepository) getSurroundingPrices(ctx context.Context, currencyID uint, timestamp time.Time) (*models.Price, *models.Price, error) {
+	resolution, err := r.resolveResolution(ctx, currencyID, timestamp, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resolution != models.ResolutionRaw {
+		return r.getSurroundingRollupPrices(ctx, currencyID, timestamp, resolution)
+	}
 
-	err := r.db.WithContext(ctx).
+	before, err := first[models.Price](r.db.WithContext(ctx).
 		Where("currency_id = ? AND timestamp <= ?", currencyID, timestamp).
-		Order("timestamp DESC").
-		First(&price).Error
+		Order("timestamp DESC"))
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, nil, err
+	}
+	after, err := first[models.Price](r.db.WithContext(ctx).
+		Where("currency_id = ? AND timestamp >= ?", currencyID, timestamp).
+		Order("timestamp ASC"))
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, nil, err
+	}
+	if before == nil && after == nil {
+		return nil, nil, repository.ErrNotFound
+	}
+	return before, after, nil
+}
 
+func (r *PriceRepository) getLatestPrice(ctx context.Context, currencyID uint) (*models.Price, error) {
+	return first[models.Price](r.db.WithContext(ctx).
+		Where("currency_id = ?", currencyID).
+		Order("timestamp DESC"))
+}
+
+// getPriceHistory returns raw prices when they still cover from, otherwise
+// the close of each bucket of the finest rollup that does.
+func (r *PriceRepository) getPriceHistory(ctx context.Context, currencyID uint, from, to time.Time) ([]models.Price, error) {
+	resolution, err := r.resolveResolution(ctx, currencyID, from, 0)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			err = r.db.WithContext(ctx).
-				Where("currency_id = ? AND timestamp >= ?", currencyID, timestamp).
-				Order("timestamp ASC").
-				First(&price).Error
-
-			if err != nil {
-				if errors.Is(err, gorm.ErrRecordNotFound) {
-					return nil, nil
-				}
-				return nil, err
-			}
-		} else {
-			return nil, err
-		}
+		return nil, err
+	}
+	if resolution != models.ResolutionRaw {
+		return r.getRollupHistory(ctx, currencyID, from, to, resolution)
 	}
 
-	return &price, nil
+	return find[models.Price](r.db.WithContext(ctx).
+		Where("currency_id = ? AND timestamp BETWEEN ? AND ?", currencyID, from, to).
+		Order("timestamp ASC"))
 }
 
-func (r *PriceRepository) GetLatestPrice(ctx context.Context, currencyID uint) (interface{}, error) {
-	var price models.Price
-	err := r.db.WithContext(ctx).
-		Where("currency_id = ?", currencyID).
-		Order("timestamp DESC").
-		First(&price).Error
+// getCandles aggregates OHLC candles in SQL with date_bin. Bins are aligned to
+// models.CandleOrigin and cover [from, to). Ranges that raw prices no longer
+// cover are aggregated from the finest rollup whose buckets each fall into a
+// single candle.
+func (r *PriceRepository) getCandles(ctx context.Context, currencyID uint, from, to time.Time, interval time.Duration) ([]models.Candle, error) {
+	resolution, err := r.resolveResolution(ctx, currencyID, from, interval)
+	if err != nil {
+		return nil, err
+	}
+
+	var query string
+	if resolution == models.ResolutionRaw {
+		query = `
+		SELECT date_bin(@interval::interval, timestamp, @origin) AS bucket,
+			(array_agg(price ORDER BY timestamp ASC))[1] AS open,
+			max(price) AS high,
+			min(price) AS low,
+			(array_agg(price ORDER BY timestamp DESC))[1] AS close,
+			count(*) AS sample_count
+		FROM prices
+		WHERE currency_id = @id AND deleted_at IS NULL AND timestamp >= @from AND timestamp < @to
+		GROUP BY 1
+		ORDER BY 1`
+	} else {
+		query = fmt.Sprintf(`
+		SELECT date_bin(@interval::interval, bucket, @origin) AS bucket,
+			(array_agg(open ORDER BY bucket ASC))[1] AS open,
+			max(high) AS high,
+			min(low) AS low,
+			(array_agg(close ORDER BY bucket DESC))[1] AS close,
+			sum(sample_count) AS sample_count
+		FROM %s
+		WHERE currency_id = @id AND bucket >= @from AND bucket < @to
+		GROUP BY 1
+		ORDER BY 1`, rollupTables[resolution])
+	}
+
+	var candles []models.Candle
+	err = r.db.WithContext(ctx).Raw(query,
+		sql.Named("interval", fmt.Sprintf("%d seconds", int64(interval.Seconds()))),
+		sql.Named("origin", models.CandleOrigin),
+		sql.Named("id", currencyID),
+		sql.Named("from", from),
+		sql.Named("to", to),
+	).Scan(&candles).Error
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, nil
-		}
 		return nil, err
 	}
-	return &price, nil
+	return candles, nil
 }
 
-func (r *PriceRepository) GetPriceHistory(ctx context.Context, currencyID uint, from, to time.Time) ([]interface{}, error) {
-	var prices []models.Price
-	err := r.db.WithContext(ctx).
-		Where("currency_id = ? AND timestamp BETWEEN ? AND ?", currencyID, from, to).
-		Order("timestamp ASC").
-		Find(&prices).Error
+// resolveResolution picks the finest resolution whose data starts at or before
+// from. When none reaches that far back, the one with the oldest data wins,
+// preferring finer resolutions on ties. A non-zero candle excludes rollups
+// that cannot be regrouped into candles of that length, see fitsCandle.
+func (r *PriceRepository) resolveResolution(ctx context.Context, currencyID uint, from time.Time, candle time.Duration) (models.Resolution, error) {
+	var coverage []resolutionCoverage
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT 'raw' AS resolution, (SELECT min(timestamp) FROM prices WHERE currency_id = @id AND deleted_at IS NULL) AS starts_at
+		UNION ALL SELECT '1m', (SELECT min(first_at) FROM price_rollups_1m WHERE currency_id = @id)
+		UNION ALL SELECT '1h', (SELECT min(first_at) FROM price_rollups_1h WHERE currency_id = @id)
+		UNION ALL SELECT '1d', (SELECT min(first_at) FROM price_rollups_1d WHERE currency_id = @id)`,
+		sql.Named("id", currencyID)).Scan(&coverage).Error
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve price resolution: %w", err)
+	}
+	return pickResolution(coverage, from, candle), nil
+}
+
+// resolutionCoverage tells since when a resolution holds data for a currency.
+type resolutionCoverage struct {
+	CurrencyID uint
+	Resolution models.Resolution
+	StartsAt   *time.Time
+}
+
+// pickResolution expects coverage ordered from the finest resolution.
+func pickResolution(coverage []resolutionCoverage, from time.Time, candle time.Duration) models.Resolution {
+	resolution := models.ResolutionRaw
+	var oldest *time.Time
+	for _, c := range coverage {
+		if c.StartsAt == nil || (candle > 0 && !fitsCandle(c.Resolution, candle)) {
+			continue
+		}
+		if !c.StartsAt.After(from) {
+			return c.Resolution
+		}
+		if oldest == nil || c.StartsAt.Before(*oldest) {
+			oldest = c.StartsAt
+			resolution = c.Resolution
+		}
+	}
+	return resolution
+}
+
+// fitsCandle tells whether every bucket of resolution lies within one candle
+// of length candle. Rollup buckets start at UTC multiples of their duration,
+// so that duration must divide both candle and the offset of
+// models.CandleOrigin; raw prices always fit.
+func fitsCandle(resolution models.Resolution, candle time.Duration) bool {
+	step := resolution.Duration()
+	if step == 0 {
+		return true
+	}
+	return candle%step == 0 && models.CandleOrigin.Sub(time.Unix(0, 0))%step == 0
+}
+
+type rollupPrice struct {
+	CurrencyID uint
+	Price      decimal.Decimal
+	Timestamp  time.Time
+}
+
+func (p rollupPrice) toModel() models.Price {
+	return models.Price{
+		CurrencyID: p.CurrencyID,
+		Price:      p.Price,
+		Timestamp:  p.Timestamp,
+	}
+}
+
+// Rollup prices carry the time of the underlying sample: the close is the
+// price at last_at, the open the price at first_at.
+func (r *PriceRepository) getSurroundingRollupPrices(ctx context.Context, currencyID uint, timestamp time.Time, resolution models.Resolution) (*models.Price, *models.Price, error) {
+	table := rollupTables[resolution]
+
+	var before []rollupPrice
+	err := r.db.WithContext(ctx).Raw(fmt.Sprintf(`
+		SELECT currency_id, close AS price, last_at AS timestamp FROM %s
+		WHERE currency_id = ? AND bucket <= ? AND last_at <= ?
+		ORDER BY bucket DESC LIMIT 1`, table), currencyID, timestamp, timestamp).Scan(&before).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var after []rollupPrice
+	err = r.db.WithContext(ctx).Raw(fmt.Sprintf(`
+		SELECT currency_id, open AS price, first_at AS timestamp FROM %s
+		WHERE currency_id = ? AND bucket >= ? AND first_at >= ?
+		ORDER BY bucket ASC LIMIT 1`, table), currencyID, timestamp.Add(-resolution.Duration()), timestamp).Scan(&after).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(before) == 0 && len(after) == 0 {
+		return nil, nil, repository.ErrNotFound
+	}
+	var previous, next *models.Price
+	if len(before) > 0 {
+		price := before[0].toModel()
+		previous = &price
+	}
+	if len(after) > 0 {
+		price := after[0].toModel()
+		next = &price
+	}
+	return previous, next, nil
+}
+
+func (r *PriceRepository) getRollupHistory(ctx context.Context, currencyID uint, from, to time.Time, resolution models.Resolution) ([]models.Price, error) {
+	var rows []rollupPrice
+	err := r.db.WithContext(ctx).Raw(fmt.Sprintf(`
+		SELECT currency_id, close AS price, last_at AS timestamp FROM %s
+		WHERE currency_id = ? AND bucket BETWEEN ? AND ? AND last_at BETWEEN ? AND ?
+		ORDER BY bucket ASC`, rollupTables[resolution]), currencyID, from.Add(-resolution.Duration()), to, from, to).Scan(&rows).Error
 	if err != nil {
 		return nil, err
 	}
 
-	result := make([]interface{}, len(prices))
-	for i, price := range prices {
-		result[i] = &price
+	prices := make([]models.Price, len(rows))
+	for i, row := range rows {
+		prices[i] = row.toModel()
 	}
-	return result, nil
+	return prices, nil
 }
@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"crypto-price-tracker-app/internal/domain/models"
+)
+
+func TestPickResolutionForCandles(t *testing.T) {
+	base := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	rawStart := base.AddDate(0, 0, -7)
+	minuteStart := base.AddDate(0, 0, -14)
+	rollupStart := base.AddDate(-1, 0, 0)
+	// Only hourly and daily rollups reach back to from.
+	coverage := []resolutionCoverage{
+		{Resolution: models.ResolutionRaw, StartsAt: &rawStart},
+		{Resolution: models.ResolutionMinute, StartsAt: &minuteStart},
+		{Resolution: models.ResolutionHour, StartsAt: &rollupStart},
+		{Resolution: models.ResolutionDay, StartsAt: &rollupStart},
+	}
+	from := base.AddDate(0, -1, 0)
+
+	tests := []struct {
+		candle time.Duration
+		want   models.Resolution
+	}{
+		{0, models.ResolutionHour},
+		{30 * time.Second, models.ResolutionRaw},
+		{5 * time.Minute, models.ResolutionMinute},
+		{90 * time.Minute, models.ResolutionMinute},
+		{4 * time.Hour, models.ResolutionHour},
+		{36 * time.Hour, models.ResolutionHour},
+		{7 * 24 * time.Hour, models.ResolutionHour},
+	}
+	for _, tt := range tests {
+		if got := pickResolution(coverage, from, tt.candle); got != tt.want {
+			t.Errorf("pickResolution(candle %s) = %s, want %s", tt.candle, got, tt.want)
+		}
+	}
+}
+
+func TestFitsCandle(t *testing.T) {
+	tests := []struct {
+		resolution models.Resolution
+		candle     time.Duration
+		want       bool
+	}{
+		{models.ResolutionRaw, 90 * time.Second, true},
+		{models.ResolutionMinute, 90 * time.Second, false},
+		{models.ResolutionMinute, 90 * time.Minute, true},
+		{models.ResolutionHour, 90 * time.Minute, false},
+		{models.ResolutionHour, 4 * time.Hour, true},
+		{models.ResolutionHour, 36 * time.Hour, true},
+		{models.ResolutionDay, 36 * time.Hour, false},
+		{models.ResolutionDay, 7 * 24 * time.Hour, true},
+	}
+	for _, tt := range tests {
+		if got := fitsCandle(tt.resolution, tt.candle); got != tt.want {
+			t.Errorf("fitsCandle(%s, %s) = %v, want %v", tt.resolution, tt.candle, got, tt.want)
+		}
+	}
+}
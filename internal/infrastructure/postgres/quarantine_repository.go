@@ -0,0 +1,48 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"crypto-price-tracker-app/internal/domain/models"
+	"crypto-price-tracker-app/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+type QuarantineRepository struct {
+	db *gorm.DB
+}
+
+func NewQuarantineRepository(db *gorm.DB) repository.QuarantineRepository {
+	return &QuarantineRepository{db: db}
+}
+
+func (r *QuarantineRepository) Create(ctx context.Context, sample *models.QuarantinedPrice) error {
+	return r.db.WithContext(ctx).Create(sample).Error
+}
+
+func (r *QuarantineRepository) GetByID(ctx context.Context, id uint) (*models.QuarantinedPrice, error) {
+	return first[models.QuarantinedPrice](r.db.WithContext(ctx).Preload("Currency").Where("id = ?", id))
+}
+
+func (r *QuarantineRepository) List(ctx context.Context, status string) ([]models.QuarantinedPrice, error) {
+	query := r.db.WithContext(ctx).Preload("Currency").Order("created_at DESC")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	return find[models.QuarantinedPrice](query)
+}
+
+func (r *QuarantineRepository) UpdateStatus(ctx context.Context, id uint, status string, reviewedAt time.Time) error {
+	result := r.db.WithContext(ctx).Model(&models.QuarantinedPrice{}).
+		Where("id = ? AND status = ?", id, models.QuarantineStatusPending).
+		Updates(map[string]interface{}{"status": status, "reviewed_at": reviewedAt})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repository.ErrConflict
+	}
+	return nil
+}
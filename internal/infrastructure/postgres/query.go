@@ -0,0 +1,41 @@
+package postgres
+
+import (
+	"errors"
+
+	"crypto-price-tracker-app/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+// first runs the query for a single row and maps GORM's not-found error to
+// repository.ErrNotFound.
+func first[T any](query *gorm.DB) (*T, error) {
+	var entity T
+	if err := query.First(&entity).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, err
+	}
+	return &entity, nil
+}
+
+// translate maps GORM's translated errors to the repository sentinels.
+func translate(err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return repository.ErrNotFound
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return repository.ErrDuplicate
+	}
+	return err
+}
+
+func find[T any](query *gorm.DB) ([]T, error) {
+	var entities []T
+	if err := query.Find(&entities).Error; err != nil {
+		return nil, err
+	}
+	return entities, nil
+}
@@ -0,0 +1,139 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"crypto-price-tracker-app/internal/domain/repository"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Replication lag in seconds; zero when the replica has replayed everything it
+// received, so an idle primary does not make replicas look stale.
+const replicaLagQuery = `
+SELECT CASE
+    WHEN NOT pg_is_in_recovery() THEN 0
+    WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+    ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+END`
+
+type replica struct {
+	name    string
+	db      *gorm.DB
+	healthy atomic.Bool
+}
+
+// ReplicaSet routes reads to healthy replicas in turn and falls back to the
+// primary when none is healthy or a replica query fails. A nil *ReplicaSet
+// sends everything to the primary.
+type ReplicaSet struct {
+	replicas []*replica
+	maxLag   time.Duration
+	next     atomic.Uint64
+	logger   *zap.Logger
+}
+
+// NewReplicaSet starts with every replica healthy; Monitor takes lagging or
+// unreachable ones out of rotation. maxLag of zero disables the lag check.
+func NewReplicaSet(replicas []*gorm.DB, maxLag time.Duration, logger *zap.Logger) *ReplicaSet {
+	set := &ReplicaSet{maxLag: maxLag, logger: logger}
+	for i, db := range replicas {
+		r := &replica{name: fmt.Sprintf("replica-%d", i+1), db: db}
+		r.healthy.Store(true)
+		set.replicas = append(set.replicas, r)
+	}
+	return set
+}
+
+// Close closes every replica connection.
+func (s *ReplicaSet) Close() error {
+	if s == nil {
+		return nil
+	}
+	var errs []error
+	for _, r := range s.replicas {
+		sqlDB, err := r.db.DB()
+		if err == nil {
+			err = sqlDB.Close()
+		}
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// Monitor re-checks every replica each interval until ctx is done.
+func (s *ReplicaSet) Monitor(ctx context.Context, interval time.Duration) {
+	if s == nil || len(s.replicas) == 0 {
+		return
+	}
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.check(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *ReplicaSet) check(ctx context.Context) {
+	for _, r := range s.replicas {
+		var lagSeconds float64
+		err := r.db.WithContext(ctx).Raw(replicaLagQuery).Row().Scan(&lagSeconds)
+		lag := time.Duration(lagSeconds * float64(time.Second))
+
+		healthy := err == nil && (s.maxLag <= 0 || lag <= s.maxLag)
+		if r.healthy.Swap(healthy) != healthy {
+			if healthy {
+				s.logger.Info("Replica back in rotation", zap.String("replica", r.name), zap.Duration("lag", lag))
+			} else {
+				s.logger.Warn("Replica taken out of rotation", zap.String("replica", r.name), zap.Duration("lag", lag), zap.Error(err))
+			}
+		}
+	}
+}
+
+func (s *ReplicaSet) pick() *replica {
+	if s == nil {
+		return nil
+	}
+	for range s.replicas {
+		r := s.replicas[s.next.Add(1)%uint64(len(s.replicas))]
+		if r.healthy.Load() {
+			return r
+		}
+	}
+	return nil
+}
+
+// read runs query on a healthy replica, or on primary when there is none. A
+// replica failure other than not-found marks it unhealthy until the next
+// check and the query is retried on primary.
+func read[T any](ctx context.Context, replicas *ReplicaSet, primary *gorm.DB, query func(db *gorm.DB) (T, error)) (T, error) {
+	r := replicas.pick()
+	if r == nil {
+		return query(primary.WithContext(ctx))
+	}
+
+	result, err := query(r.db.WithContext(ctx))
+	if err == nil || errors.Is(err, repository.ErrNotFound) || errors.Is(err, sql.ErrNoRows) || ctx.Err() != nil {
+		return result, err
+	}
+
+	r.healthy.Store(false)
+	replicas.logger.Warn("Replica query failed, falling back to primary", zap.String("replica", r.name), zap.Error(err))
+	return query(primary.WithContext(ctx))
+}
@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func openNamed(t *testing.T, name string) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Exec("CREATE TABLE server (name TEXT)").Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Exec("INSERT INTO server VALUES (?)", name).Error; err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func serverName(db *gorm.DB) (string, error) {
+	var name string
+	err := db.Raw("SELECT name FROM server").Row().Scan(&name)
+	return name, err
+}
+
+func TestRead_Routing(t *testing.T) {
+	ctx := context.Background()
+	primary := openNamed(t, "primary")
+
+	name, err := read(ctx, nil, primary, serverName)
+	if err != nil || name != "primary" {
+		t.Fatalf("nil replica set: got %q, %v", name, err)
+	}
+
+	replicas := NewReplicaSet([]*gorm.DB{openNamed(t, "replica")}, 0, zap.NewNop())
+	name, err = read(ctx, replicas, primary, serverName)
+	if err != nil || name != "replica" {
+		t.Fatalf("healthy replica: got %q, %v", name, err)
+	}
+
+	replicas.replicas[0].healthy.Store(false)
+	name, err = read(ctx, replicas, primary, serverName)
+	if err != nil || name != "primary" {
+		t.Fatalf("unhealthy replica: got %q, %v", name, err)
+	}
+}
+
+func TestRead_FallsBackOnReplicaError(t *testing.T) {
+	ctx := context.Background()
+	primary := openNamed(t, "primary")
+	broken := openNamed(t, "replica")
+	sqlDB, _ := broken.DB()
+	sqlDB.Close()
+
+	replicas := NewReplicaSet([]*gorm.DB{broken}, 0, zap.NewNop())
+	name, err := read(ctx, replicas, primary, serverName)
+	if err != nil || name != "primary" {
+		t.Fatalf("got %q, %v", name, err)
+	}
+	if replicas.replicas[0].healthy.Load() {
+		t.Error("failed replica should be taken out of rotation")
+	}
+}
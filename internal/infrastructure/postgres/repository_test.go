@@ -0,0 +1,50 @@
+package postgres_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"crypto-price-tracker-app/internal/domain/repository/repositorytest"
+	"crypto-price-tracker-app/internal/infrastructure/migrate"
+	"crypto-price-tracker-app/internal/infrastructure/postgres"
+	"crypto-price-tracker-app/migrations"
+
+	"github.com/stretchr/testify/require"
+	driver "gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// TestRepositoryContract needs a disposable database, for example
+// TEST_DATABASE_DSN="host=localhost user=postgres password=password dbname=crypto_tracker_test sslmode=disable".
+// Every table the repositories touch is truncated between subtests.
+func TestRepositoryContract(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+
+	db, err := gorm.Open(driver.Open(dsn), &gorm.Config{
+		Logger:         logger.Default.LogMode(logger.Silent),
+		TranslateError: true,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { postgres.CloseConnection(db) })
+
+	migrator, err := migrate.New(db, migrations.FS)
+	require.NoError(t, err)
+	_, err = migrator.Up(context.Background())
+	require.NoError(t, err)
+
+	repositorytest.Run(t, func(t *testing.T) repositorytest.Repositories {
+		require.NoError(t, db.Exec(`TRUNCATE prices, quarantined_prices, currency_transitions, audit_events, price_rollups_1m, price_rollups_1h, price_rollups_1d, currencies RESTART IDENTITY CASCADE`).Error)
+		return repositorytest.Repositories{
+			Currencies: postgres.NewCurrencyRepository(db, nil),
+			Prices:     postgres.NewPriceRepository(db, nil),
+			Quarantine: postgres.NewQuarantineRepository(db),
+			Audit:      postgres.NewAuditRepository(db),
+			UnitOfWork: postgres.NewUnitOfWork(db),
+		}
+	})
+}
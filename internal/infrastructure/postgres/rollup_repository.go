@@ -0,0 +1,132 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"crypto-price-tracker-app/internal/domain/models"
+	"crypto-price-tracker-app/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+var rollupTables = map[models.Resolution]string{
+	models.ResolutionMinute: "price_rollups_1m",
+	models.ResolutionHour:   "price_rollups_1h",
+	models.ResolutionDay:    "price_rollups_1d",
+}
+
+var rollupUnits = map[models.Resolution]string{
+	models.ResolutionMinute: "minute",
+	models.ResolutionHour:   "hour",
+	models.ResolutionDay:    "day",
+}
+
+const rollupUpsert = `
+ON CONFLICT (currency_id, bucket) DO UPDATE SET
+    open = EXCLUDED.open,
+    high = EXCLUDED.high,
+    low = EXCLUDED.low,
+    close = EXCLUDED.close,
+    avg = EXCLUDED.avg,
+    sample_count = EXCLUDED.sample_count,
+    first_at = EXCLUDED.first_at,
+    last_at = EXCLUDED.last_at,
+    updated_at = EXCLUDED.updated_at`
+
+type RollupRepository struct {
+	db *gorm.DB
+}
+
+func NewRollupRepository(db *gorm.DB) repository.RollupRepository {
+	return &RollupRepository{db: db}
+}
+
+// Refresh recomputes the buckets of resolution starting lookback before its
+// newest bucket, so late prices inside that window are picked up. The minute
+// rollup is built from raw prices, coarser ones from the next finer rollup.
+func (r *RollupRepository) Refresh(ctx context.Context, resolution models.Resolution, lookback time.Duration) (int64, error) {
+	table, ok := rollupTables[resolution]
+	if !ok {
+		return 0, fmt.Errorf("unsupported rollup resolution %q", resolution)
+	}
+
+	var newest sql.NullTime
+	if err := r.db.WithContext(ctx).Raw(fmt.Sprintf("SELECT max(bucket) FROM %s", table)).Row().Scan(&newest); err != nil {
+		return 0, fmt.Errorf("failed to read newest %s bucket: %w", resolution, err)
+	}
+
+	from := time.Unix(0, 0).UTC()
+	if newest.Valid {
+		from = newest.Time.Add(-lookback).UTC().Truncate(resolution.Duration())
+	}
+
+	var query string
+	unit := rollupUnits[resolution]
+	if resolution == models.ResolutionMinute {
+		query = fmt.Sprintf(`
+INSERT INTO %s (currency_id, bucket, open, high, low, close, avg, sample_count, first_at, last_at, updated_at)
+SELECT currency_id,
+    date_trunc('%s', timestamp, 'UTC'),
+    (array_agg(price ORDER BY timestamp ASC))[1],
+    max(price),
+    min(price),
+    (array_agg(price ORDER BY timestamp DESC))[1],
+    avg(price),
+    count(*),
+    min(timestamp),
+    max(timestamp),
+    now()
+FROM prices
+WHERE deleted_at IS NULL AND timestamp >= ?
+GROUP BY 1, 2`, table, unit) + rollupUpsert
+	} else {
+		source := rollupTables[finerResolution(resolution)]
+		query = fmt.Sprintf(`
+INSERT INTO %s (currency_id, bucket, open, high, low, close, avg, sample_count, first_at, last_at, updated_at)
+SELECT currency_id,
+    date_trunc('%s', bucket, 'UTC'),
+    (array_agg(open ORDER BY bucket ASC))[1],
+    max(high),
+    min(low),
+    (array_agg(close ORDER BY bucket DESC))[1],
+    sum(avg * sample_count) / sum(sample_count),
+    sum(sample_count),
+    min(first_at),
+    max(last_at),
+    now()
+FROM %s
+WHERE bucket >= ?
+GROUP BY 1, 2`, table, unit, source) + rollupUpsert
+	}
+
+	result := r.db.WithContext(ctx).Exec(query, from)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to refresh %s rollup: %w", resolution, result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+func (r *RollupRepository) Prune(ctx context.Context, resolution models.Resolution, before time.Time) (int64, error) {
+	table, ok := rollupTables[resolution]
+	if !ok {
+		return 0, fmt.Errorf("unsupported rollup resolution %q", resolution)
+	}
+
+	result := r.db.WithContext(ctx).Exec(fmt.Sprintf("DELETE FROM %s WHERE bucket < ?", table), before)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to prune %s rollup: %w", resolution, result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+func finerResolution(resolution models.Resolution) models.Resolution {
+	for i := 1; i < len(models.RollupResolutions); i++ {
+		if models.RollupResolutions[i] == resolution {
+			return models.RollupResolutions[i-1]
+		}
+	}
+	return models.ResolutionRaw
+}
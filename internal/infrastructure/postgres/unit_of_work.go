@@ -0,0 +1,30 @@
+package postgres
+
+import (
+	"context"
+
+	"crypto-price-tracker-app/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+type UnitOfWork struct {
+	db *gorm.DB
+}
+
+func NewUnitOfWork(db *gorm.DB) repository.UnitOfWork {
+	return &UnitOfWork{db: db}
+}
+
+// WithTx binds fresh repositories to the transaction. Repository methods that
+// open a transaction of their own get a savepoint inside it instead.
+func (u *UnitOfWork) WithTx(ctx context.Context, fn func(repos repository.Repositories) error) error {
+	return u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(repository.Repositories{
+			Currencies: NewCurrencyRepository(tx, nil),
+			Prices:     NewPriceRepository(tx, nil),
+			Quarantine: NewQuarantineRepository(tx),
+			Audit:      NewAuditRepository(tx),
+		})
+	})
+}
@@ -0,0 +1,87 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"crypto-price-tracker-app/internal/domain/models"
+	"crypto-price-tracker-app/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+// deleteChunk stays below SQLite's default limit of 32766 bind parameters.
+const deleteChunk = 10000
+
+type PriceArchiveRepository struct {
+	db *gorm.DB
+}
+
+func NewPriceArchiveRepository(db *gorm.DB) repository.PriceArchiveRepository {
+	return &PriceArchiveRepository{db: db}
+}
+
+// ListArchivable groups by the month prefix of the stored UTC timestamp;
+// SQLite returns it as text, so it is parsed here.
+func (r *PriceArchiveRepository) ListArchivable(ctx context.Context, before time.Time) ([]models.ArchiveGroup, error) {
+	var rows []struct {
+		CurrencyID uint
+		Symbol     string
+		Month      string
+		Rows       int64
+	}
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT p.currency_id, c.symbol, strftime('%Y-%m', p.timestamp) AS month, count(*) AS "rows"
+		FROM prices p
+		JOIN currencies c ON c.id = p.currency_id
+		WHERE p.deleted_at IS NULL AND p.timestamp < ?
+		GROUP BY 1, 2, 3
+		ORDER BY 1, 3`, before.UTC()).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]models.ArchiveGroup, len(rows))
+	for i, row := range rows {
+		month, err := time.Parse(models.ArchiveMonthLayout, row.Month)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected month %q: %w", row.Month, err)
+		}
+		groups[i] = models.ArchiveGroup{CurrencyID: row.CurrencyID, Symbol: row.Symbol, Month: month, Rows: row.Rows}
+	}
+	return groups, nil
+}
+
+func (r *PriceArchiveRepository) GetMonth(ctx context.Context, currencyID uint, month time.Time) ([]models.Price, error) {
+	var prices []models.Price
+	err := r.db.WithContext(ctx).
+		Where("currency_id = ? AND timestamp >= ? AND timestamp < ?", currencyID, month.UTC(), month.AddDate(0, 1, 0).UTC()).
+		Order("timestamp ASC").
+		Find(&prices).Error
+	if err != nil {
+		return nil, err
+	}
+	return prices, nil
+}
+
+func (r *PriceArchiveRepository) DeleteArchived(ctx context.Context, currencyID uint, month time.Time, ids []uint) (int64, error) {
+	var deleted int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for start := 0; start < len(ids); start += deleteChunk {
+			result := tx.Unscoped().
+				Where("currency_id = ? AND timestamp >= ? AND timestamp < ?", currencyID, month.UTC(), month.AddDate(0, 1, 0).UTC()).
+				Where("id IN ?", ids[start:min(start+deleteChunk, len(ids))]).
+				Delete(&models.Price{})
+			if result.Error != nil {
+				return result.Error
+			}
+			deleted += result.RowsAffected
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return deleted, nil
+}
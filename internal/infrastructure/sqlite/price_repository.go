@@ -0,0 +1,221 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"crypto-price-tracker-app/internal/domain/models"
+	"crypto-price-tracker-app/internal/domain/repository"
+	"crypto-price-tracker-app/internal/infrastructure/postgres"
+
+	"gorm.io/gorm"
+)
+
+// PriceRepository reads raw prices only: SQLite has no partitions or rollups.
+// Timestamps are stored as text, so every time is written and compared in UTC
+// to keep their ordering and equality intact.
+type PriceRepository struct {
+	db *gorm.DB
+}
+
+func NewPriceRepository(db *gorm.DB) repository.PriceRepository {
+	return &PriceRepository{db: db}
+}
+
+func (r *PriceRepository) Create(ctx context.Context, price *models.Price) error {
+	price.Timestamp = price.Timestamp.UTC()
+	return translate(r.db.WithContext(ctx).Create(price).Error)
+}
+
+// CreateBatch compares every price with the stored row inside one
+// transaction, since SQLite cannot tell inserted from updated rows in
+// RETURNING.
+func (r *PriceRepository) CreateBatch(ctx context.Context, prices []models.Price, onConflict models.ConflictAction) (*models.BatchResult, error) {
+	if onConflict != models.ConflictSkip && onConflict != models.ConflictUpdate {
+		return nil, fmt.Errorf("unsupported conflict action %q", onConflict)
+	}
+
+	type key struct {
+		currencyID uint
+		timestamp  int64
+	}
+	index := make(map[key]int, len(prices))
+	unique := make([]models.Price, 0, len(prices))
+	for _, price := range prices {
+		k := key{price.CurrencyID, price.Timestamp.UnixNano()}
+		if i, ok := index[k]; ok {
+			unique[i] = price
+			continue
+		}
+		index[k] = len(unique)
+		unique = append(unique, price)
+	}
+
+	result := &models.BatchResult{Skipped: len(prices) - len(unique)}
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now().UTC()
+		for _, price := range unique {
+			price.Timestamp = price.Timestamp.UTC()
+
+			var existing []models.Price
+			if err := tx.Unscoped().Where("currency_id = ? AND timestamp = ?", price.CurrencyID, price.Timestamp).Limit(1).Find(&existing).Error; err != nil {
+				return err
+			}
+
+			switch {
+			case len(existing) == 0:
+				price.ID = 0
+				if err := tx.Create(&price).Error; err != nil {
+					return err
+				}
+				result.Inserted++
+			case onConflict == models.ConflictUpdate && (!existing[0].Price.Equal(price.Price) || existing[0].DeletedAt.Valid):
+				err := tx.Unscoped().Model(&models.Price{}).Where("id = ?", existing[0].ID).
+					Updates(map[string]interface{}{"price": price.Price, "updated_at": now, "deleted_at": nil}).Error
+				if err != nil {
+					return err
+				}
+				result.Updated++
+			default:
+				result.Skipped++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *PriceRepository) GetByCurrencyAndTime(ctx context.Context, currencyID uint, timestamp time.Time) (*models.Price, error) {
+	return first(r.db.WithContext(ctx).
+		Where("currency_id = ? AND timestamp = ?", currencyID, timestamp.UTC()))
+}
+
+func (r *PriceRepository) GetNearestPrice(ctx context.Context, currencyID uint, timestamp time.Time) (*models.Price, error) {
+	price, err := first(r.db.WithContext(ctx).
+		Where("currency_id = ? AND timestamp <= ?", currencyID, timestamp.UTC()).
+		Order("timestamp DESC"))
+	if !errors.Is(err, repository.ErrNotFound) {
+		return price, err
+	}
+
+	return first(r.db.WithContext(ctx).
+		Where("currency_id = ? AND timestamp >= ?", currencyID, timestamp.UTC()).
+		Order("timestamp ASC"))
+}
+
+func (r *PriceRepository) GetSurroundingPrices(ctx context.Context, currencyID uint, timestamp time.Time) (*models.Price, *models.Price, error) {
+	before, err := first(r.db.WithContext(ctx).
+		Where("currency_id = ? AND timestamp <= ?", currencyID, timestamp.UTC()).
+		Order("timestamp DESC"))
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, nil, err
+	}
+	after, err := first(r.db.WithContext(ctx).
+		Where("currency_id = ? AND timestamp >= ?", currencyID, timestamp.UTC()).
+		Order("timestamp ASC"))
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, nil, err
+	}
+	if before == nil && after == nil {
+		return nil, nil, repository.ErrNotFound
+	}
+	return before, after, nil
+}
+
+// GetSurroundingPricesBatch shares the PostgreSQL query; SQLite infers the
+// types of the VALUES rows from the compared columns.
+func (r *PriceRepository) GetSurroundingPricesBatch(ctx context.Context, keys []models.PriceKey) ([]models.SurroundingPrices, error) {
+	return postgres.SurroundingPricesBatch(r.db.WithContext(ctx), keys, "(?, ?, ?)")
+}
+
+func (r *PriceRepository) GetLatestPrice(ctx context.Context, currencyID uint) (*models.Price, error) {
+	return first(r.db.WithContext(ctx).
+		Where("currency_id = ?", currencyID).
+		Order("timestamp DESC"))
+}
+
+// GetLatestPrices picks each currency's row with the greatest timestamp;
+// SQLite has no DISTINCT ON.
+func (r *PriceRepository) GetLatestPrices(ctx context.Context, currencyIDs []uint) ([]models.Price, error) {
+	if len(currencyIDs) == 0 {
+		return nil, nil
+	}
+	var prices []models.Price
+	err := r.db.WithContext(ctx).
+		Where("currency_id IN ?", currencyIDs).
+		Where("timestamp = (SELECT MAX(p.timestamp) FROM prices p WHERE p.currency_id = prices.currency_id AND p.deleted_at IS NULL)").
+		Order("currency_id").
+		Find(&prices).Error
+	if err != nil {
+		return nil, err
+	}
+	return prices, nil
+}
+
+func (r *PriceRepository) GetPriceHistory(ctx context.Context, currencyID uint, from, to time.Time) ([]models.Price, error) {
+	var prices []models.Price
+	err := r.db.WithContext(ctx).
+		Where("currency_id = ? AND timestamp BETWEEN ? AND ?", currencyID, from.UTC(), to.UTC()).
+		Order("timestamp ASC").
+		Find(&prices).Error
+	if err != nil {
+		return nil, err
+	}
+	return prices, nil
+}
+
+// GetCandles aggregates in Go: prices are stored as text to stay exact, and
+// SQLite would compare them as strings or lossy floats.
+func (r *PriceRepository) GetCandles(ctx context.Context, currencyID uint, from, to time.Time, interval time.Duration) ([]models.Candle, error) {
+	var prices []models.Price
+	err := r.db.WithContext(ctx).
+		Where("currency_id = ? AND timestamp >= ? AND timestamp < ?", currencyID, from.UTC(), to.UTC()).
+		Order("timestamp ASC").
+		Find(&prices).Error
+	if err != nil {
+		return nil, err
+	}
+	return models.BuildCandles(prices, interval), nil
+}
+
+func first(query *gorm.DB) (*models.Price, error) {
+	var price models.Price
+	if err := query.First(&price).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &price, nil
+}
+
+func translate(err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return repository.ErrNotFound
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return repository.ErrDuplicate
+	}
+	return err
+}
+
+func (r *PriceRepository) DeleteByCurrency(ctx context.Context, currencyID uint) (int64, error) {
+	result := r.db.WithContext(ctx).Unscoped().Where("currency_id = ?", currencyID).Delete(&models.Price{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+func (r *PriceRepository) GetPriceHistoryPage(ctx context.Context, currencyID uint, query models.PriceHistoryQuery) ([]models.Price, error) {
+	var prices []models.Price
+	err := postgres.PriceHistoryPage(r.db.WithContext(ctx), currencyID, query,
+		"(unixepoch(timestamp) - ?) / ?", models.CandleOrigin.Unix(), int64(query.Step.Seconds())).
+		Find(&prices).Error
+	if err != nil {
+		return nil, err
+	}
+	return prices, nil
+}
@@ -0,0 +1,45 @@
+package sqlite
+
+import (
+	"context"
+
+	"crypto-price-tracker-app/internal/domain/repository"
+	"crypto-price-tracker-app/internal/infrastructure/postgres"
+
+	"gorm.io/gorm"
+)
+
+// The currency, quarantine and audit repositories only issue portable GORM queries,
+// so the Postgres implementations are reused as they are.
+
+func NewCurrencyRepository(db *gorm.DB) repository.CurrencyRepository {
+	return postgres.NewCurrencyRepository(db, nil)
+}
+
+func NewQuarantineRepository(db *gorm.DB) repository.QuarantineRepository {
+	return postgres.NewQuarantineRepository(db)
+}
+
+func NewAuditRepository(db *gorm.DB) repository.AuditRepository {
+	return postgres.NewAuditRepository(db)
+}
+
+// UnitOfWork binds the SQLite price repository next to the shared ones.
+type UnitOfWork struct {
+	db *gorm.DB
+}
+
+func NewUnitOfWork(db *gorm.DB) repository.UnitOfWork {
+	return &UnitOfWork{db: db}
+}
+
+func (u *UnitOfWork) WithTx(ctx context.Context, fn func(repos repository.Repositories) error) error {
+	return u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(repository.Repositories{
+			Currencies: NewCurrencyRepository(tx),
+			Prices:     NewPriceRepository(tx),
+			Quarantine: NewQuarantineRepository(tx),
+			Audit:      NewAuditRepository(tx),
+		})
+	})
+}
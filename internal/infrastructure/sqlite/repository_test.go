@@ -0,0 +1,112 @@
+package sqlite_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"crypto-price-tracker-app/internal/domain/models"
+	"crypto-price-tracker-app/internal/domain/repository/repositorytest"
+	"crypto-price-tracker-app/internal/infrastructure/migrate"
+	"crypto-price-tracker-app/internal/infrastructure/sqlite"
+	"crypto-price-tracker-app/migrations"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func openMigrated(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := sqlite.NewConnection(filepath.Join(t.TempDir(), "test.db"), nil)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	})
+
+	migrator, err := migrate.New(db, migrations.FS)
+	require.NoError(t, err)
+	_, err = migrator.Up(context.Background())
+	require.NoError(t, err)
+	return db
+}
+
+func TestRepositoryContract(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) repositorytest.Repositories {
+		db := openMigrated(t)
+		return repositorytest.Repositories{
+			Currencies: sqlite.NewCurrencyRepository(db),
+			Prices:     sqlite.NewPriceRepository(db),
+			Quarantine: sqlite.NewQuarantineRepository(db),
+			Audit:      sqlite.NewAuditRepository(db),
+			UnitOfWork: sqlite.NewUnitOfWork(db),
+		}
+	})
+}
+
+func TestMigrationsRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	db, err := sqlite.NewConnection(filepath.Join(t.TempDir(), "test.db"), nil)
+	require.NoError(t, err)
+
+	migrator, err := migrate.New(db, migrations.FS)
+	require.NoError(t, err)
+
+	_, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	require.NoError(t, migrator.Check(ctx))
+
+	reverted, err := migrator.Down(ctx, int(migrator.Latest()))
+	require.NoError(t, err)
+	require.Len(t, reverted, int(migrator.Latest()))
+
+	_, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	require.NoError(t, migrator.Check(ctx))
+}
+
+func TestPriceArchiveRepository(t *testing.T) {
+	ctx := context.Background()
+	db := openMigrated(t)
+	currencies := sqlite.NewCurrencyRepository(db)
+	prices := sqlite.NewPriceRepository(db)
+	archive := sqlite.NewPriceArchiveRepository(db)
+
+	btc := &models.Currency{Symbol: "BTC", ApiID: "bitcoin", Interval: 60}
+	require.NoError(t, currencies.Create(ctx, btc))
+
+	february := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	march := february.AddDate(0, 1, 0)
+	for _, at := range []time.Time{february, march.Add(-time.Second), march, march.Add(time.Hour)} {
+		require.NoError(t, prices.Create(ctx, &models.Price{CurrencyID: btc.ID, Price: decimal.NewFromInt(1), Timestamp: at}))
+	}
+
+	groups, err := archive.ListArchivable(ctx, march.AddDate(0, 1, 0))
+	require.NoError(t, err)
+	require.Len(t, groups, 2)
+	assert.Equal(t, models.ArchiveGroup{CurrencyID: btc.ID, Symbol: "BTC", Month: february, Rows: 2}, groups[0])
+	assert.Equal(t, march, groups[1].Month)
+
+	groups, err = archive.ListArchivable(ctx, march)
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+
+	month, err := archive.GetMonth(ctx, btc.ID, february)
+	require.NoError(t, err)
+	require.Len(t, month, 2)
+	assert.True(t, month[1].Timestamp.Equal(march.Add(-time.Second)))
+
+	deleted, err := archive.DeleteArchived(ctx, btc.ID, february, []uint{month[0].ID, month[1].ID})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+
+	month, err = archive.GetMonth(ctx, btc.ID, february)
+	require.NoError(t, err)
+	assert.Empty(t, month)
+	rest, err := archive.GetMonth(ctx, btc.ID, march)
+	require.NoError(t, err)
+	assert.Len(t, rest, 2)
+}
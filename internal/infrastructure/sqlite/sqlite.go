@@ -0,0 +1,39 @@
+package sqlite
+
+import (
+	"fmt"
+	stdlog "log"
+	"os"
+	"path/filepath"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// NewConnection opens the database file at path, creating its directory if
+// needed. WAL and a busy timeout let the API and the worker share one file.
+// A nil log discards GORM output.
+func NewConnection(path string, log logger.Interface) (*gorm.DB, error) {
+	if log == nil {
+		log = logger.Discard
+	}
+
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create database directory: %w", err)
+		}
+	}
+
+	dsn := path + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{
+		Logger:         log,
+		TranslateError: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	stdlog.Printf("Successfully opened SQLite database %s", path)
+	return db, nil
+}
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_currencies_active;
+DROP INDEX IF EXISTS idx_currencies_symbol;
+DROP TABLE IF EXISTS currencies;
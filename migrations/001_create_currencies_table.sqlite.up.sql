@@ -0,0 +1,13 @@
+-- Создание таблицы криптовалют (SQLite)
+-- updated_at обновляет GORM, триггер не нужен
+CREATE TABLE IF NOT EXISTS currencies (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    symbol VARCHAR(50) UNIQUE NOT NULL,
+    interval INTEGER NOT NULL DEFAULT 60,
+    is_active BOOLEAN NOT NULL DEFAULT 1,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_currencies_symbol ON currencies(symbol);
+CREATE INDEX IF NOT EXISTS idx_currencies_active ON currencies(is_active) WHERE is_active = 1;
@@ -0,0 +1,14 @@
+-- Создание таблицы цен (SQLite)
+-- Цена хранится текстом: у NUMERIC в SQLite точность double
+CREATE TABLE IF NOT EXISTS prices (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    currency_id INTEGER NOT NULL REFERENCES currencies(id) ON DELETE CASCADE,
+    price TEXT NOT NULL,
+    timestamp DATETIME NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_prices_currency_timestamp ON prices(currency_id, timestamp);
+CREATE INDEX IF NOT EXISTS idx_prices_timestamp ON prices(timestamp);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_prices_currency_timestamp_unique ON prices(currency_id, timestamp);
+CREATE INDEX IF NOT EXISTS idx_prices_currency_created_at ON prices(currency_id, created_at DESC);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_currencies_api_id;
+ALTER TABLE currencies DROP COLUMN api_id;
@@ -0,0 +1,3 @@
+ALTER TABLE currencies ADD COLUMN api_id VARCHAR(50) NOT NULL DEFAULT '';
+UPDATE currencies SET api_id = symbol WHERE api_id = '';
+CREATE INDEX idx_currencies_api_id ON currencies(api_id);
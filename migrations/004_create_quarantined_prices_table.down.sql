@@ -0,0 +1,6 @@
+-- Удаление индексов
+DROP INDEX IF EXISTS idx_quarantined_prices_currency_id;
+DROP INDEX IF EXISTS idx_quarantined_prices_status;
+
+-- Удаление таблицы
+DROP TABLE IF EXISTS quarantined_prices;
@@ -0,0 +1,17 @@
+-- Создание таблицы отклонённых цен (SQLite)
+CREATE TABLE IF NOT EXISTS quarantined_prices (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    currency_id INTEGER NOT NULL REFERENCES currencies(id) ON DELETE CASCADE,
+    price TEXT NOT NULL,
+    timestamp DATETIME NOT NULL,
+    reference_price TEXT,
+    rule VARCHAR(50) NOT NULL,
+    reason TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    reviewed_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_quarantined_prices_status ON quarantined_prices(status);
+CREATE INDEX IF NOT EXISTS idx_quarantined_prices_currency_id ON quarantined_prices(currency_id);
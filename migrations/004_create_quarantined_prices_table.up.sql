@@ -0,0 +1,20 @@
+-- Создание таблицы отклонённых цен
+CREATE TABLE IF NOT EXISTS quarantined_prices (
+    id SERIAL PRIMARY KEY,
+    currency_id INTEGER NOT NULL REFERENCES currencies(id) ON DELETE CASCADE,
+    price DECIMAL(20, 8) NOT NULL,
+    timestamp TIMESTAMP WITH TIME ZONE NOT NULL,
+    reference_price DECIMAL(20, 8),
+    rule VARCHAR(50) NOT NULL,
+    reason TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Создание индекса для выборки по статусу
+CREATE INDEX IF NOT EXISTS idx_quarantined_prices_status ON quarantined_prices(status);
+
+-- Создание индекса для поиска по криптовалюте
+CREATE INDEX IF NOT EXISTS idx_quarantined_prices_currency_id ON quarantined_prices(currency_id);
@@ -0,0 +1 @@
+ALTER TABLE currencies DROP COLUMN IF EXISTS stale_after;
@@ -0,0 +1 @@
+ALTER TABLE currencies ADD COLUMN stale_after INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,5 @@
+ALTER TABLE quarantined_prices ALTER COLUMN reference_price TYPE DECIMAL(20, 8);
+ALTER TABLE quarantined_prices ALTER COLUMN price TYPE DECIMAL(20, 8);
+ALTER TABLE prices ALTER COLUMN price TYPE DECIMAL(20, 8);
+
+ALTER TABLE currencies DROP COLUMN IF EXISTS precision;
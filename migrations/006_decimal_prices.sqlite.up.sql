@@ -0,0 +1,3 @@
+-- Точность цен задаётся для каждой валюты.
+-- Цены в SQLite уже хранятся текстом без потери точности.
+ALTER TABLE currencies ADD COLUMN precision INTEGER NOT NULL DEFAULT 8;
@@ -0,0 +1,7 @@
+-- Точность цен задаётся для каждой валюты
+ALTER TABLE currencies ADD COLUMN precision INTEGER NOT NULL DEFAULT 8;
+
+-- Расширение точности хранения цен для токенов дешевле цента
+ALTER TABLE prices ALTER COLUMN price TYPE NUMERIC(38, 18);
+ALTER TABLE quarantined_prices ALTER COLUMN price TYPE NUMERIC(38, 18);
+ALTER TABLE quarantined_prices ALTER COLUMN reference_price TYPE NUMERIC(38, 18);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_prices_deleted_at;
+ALTER TABLE prices DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE prices DROP COLUMN IF EXISTS updated_at;
+
+DROP INDEX IF EXISTS idx_currencies_deleted_at;
+ALTER TABLE currencies DROP COLUMN IF EXISTS deleted_at;
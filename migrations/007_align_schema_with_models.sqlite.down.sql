@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_prices_deleted_at;
+ALTER TABLE prices DROP COLUMN deleted_at;
+ALTER TABLE prices DROP COLUMN updated_at;
+
+DROP INDEX IF EXISTS idx_currencies_deleted_at;
+ALTER TABLE currencies DROP COLUMN deleted_at;
@@ -0,0 +1,9 @@
+-- Колонки мягкого удаления, которые ожидают GORM-модели.
+-- SQLite не допускает CURRENT_TIMESTAMP по умолчанию в ADD COLUMN.
+ALTER TABLE currencies ADD COLUMN deleted_at DATETIME;
+CREATE INDEX IF NOT EXISTS idx_currencies_deleted_at ON currencies(deleted_at);
+
+ALTER TABLE prices ADD COLUMN updated_at DATETIME;
+UPDATE prices SET updated_at = created_at;
+ALTER TABLE prices ADD COLUMN deleted_at DATETIME;
+CREATE INDEX IF NOT EXISTS idx_prices_deleted_at ON prices(deleted_at);
@@ -0,0 +1,7 @@
+-- Колонки мягкого удаления, которые ожидают GORM-модели
+ALTER TABLE currencies ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+CREATE INDEX IF NOT EXISTS idx_currencies_deleted_at ON currencies(deleted_at);
+
+ALTER TABLE prices ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE prices ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+CREATE INDEX IF NOT EXISTS idx_prices_deleted_at ON prices(deleted_at);
@@ -0,0 +1,32 @@
+-- Возврат к обычной таблице цен
+ALTER TABLE prices RENAME TO prices_partitioned;
+ALTER INDEX IF EXISTS idx_prices_currency_timestamp_unique RENAME TO idx_prices_partitioned_currency_timestamp_unique;
+ALTER INDEX IF EXISTS idx_prices_timestamp RENAME TO idx_prices_partitioned_timestamp;
+ALTER INDEX IF EXISTS idx_prices_currency_created_at RENAME TO idx_prices_partitioned_currency_created_at;
+ALTER INDEX IF EXISTS idx_prices_deleted_at RENAME TO idx_prices_partitioned_deleted_at;
+ALTER TABLE prices_partitioned RENAME CONSTRAINT prices_pkey TO prices_partitioned_pkey;
+ALTER SEQUENCE prices_id_seq OWNED BY NONE;
+
+CREATE TABLE prices (
+    id INTEGER PRIMARY KEY DEFAULT nextval('prices_id_seq'),
+    currency_id INTEGER NOT NULL REFERENCES currencies(id) ON DELETE CASCADE,
+    price NUMERIC(38, 18) NOT NULL,
+    timestamp TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+INSERT INTO prices (id, currency_id, price, timestamp, created_at, updated_at, deleted_at)
+SELECT id, currency_id, price, timestamp, created_at, updated_at, deleted_at
+FROM prices_partitioned;
+
+DROP TABLE prices_partitioned;
+DROP FUNCTION IF EXISTS create_prices_partition(DATE);
+ALTER SEQUENCE prices_id_seq OWNED BY prices.id;
+
+CREATE INDEX IF NOT EXISTS idx_prices_currency_timestamp ON prices(currency_id, timestamp);
+CREATE INDEX IF NOT EXISTS idx_prices_timestamp ON prices(timestamp);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_prices_currency_timestamp_unique ON prices(currency_id, timestamp);
+CREATE INDEX IF NOT EXISTS idx_prices_currency_created_at ON prices(currency_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_prices_deleted_at ON prices(deleted_at);
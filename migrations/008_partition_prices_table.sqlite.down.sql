@@ -0,0 +1,2 @@
+-- SQLite не поддерживает секционирование, таблица цен остаётся обычной
+SELECT 1;
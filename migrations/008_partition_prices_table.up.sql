@@ -0,0 +1,85 @@
+-- Перевод таблицы цен на помесячное секционирование по timestamp
+ALTER TABLE prices RENAME TO prices_legacy;
+ALTER TABLE prices_legacy RENAME CONSTRAINT prices_pkey TO prices_legacy_pkey;
+ALTER INDEX IF EXISTS idx_prices_currency_timestamp RENAME TO idx_prices_legacy_currency_timestamp;
+ALTER INDEX IF EXISTS idx_prices_timestamp RENAME TO idx_prices_legacy_timestamp;
+ALTER INDEX IF EXISTS idx_prices_currency_timestamp_unique RENAME TO idx_prices_legacy_currency_timestamp_unique;
+ALTER INDEX IF EXISTS idx_prices_currency_created_at RENAME TO idx_prices_legacy_currency_created_at;
+ALTER INDEX IF EXISTS idx_prices_deleted_at RENAME TO idx_prices_legacy_deleted_at;
+ALTER SEQUENCE prices_id_seq OWNED BY NONE;
+
+CREATE TABLE prices (
+    id INTEGER NOT NULL DEFAULT nextval('prices_id_seq'),
+    currency_id INTEGER NOT NULL REFERENCES currencies(id) ON DELETE CASCADE,
+    price NUMERIC(38, 18) NOT NULL,
+    timestamp TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (id, timestamp)
+) PARTITION BY RANGE (timestamp);
+
+-- Уникальный индекс включает ключ секционирования и заменяет индекс (currency_id, timestamp)
+CREATE UNIQUE INDEX IF NOT EXISTS idx_prices_currency_timestamp_unique ON prices(currency_id, timestamp);
+CREATE INDEX IF NOT EXISTS idx_prices_timestamp ON prices(timestamp);
+CREATE INDEX IF NOT EXISTS idx_prices_currency_created_at ON prices(currency_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_prices_deleted_at ON prices(deleted_at);
+
+-- Секция по умолчанию принимает строки, для которых месячная секция ещё не создана
+CREATE TABLE IF NOT EXISTS prices_default PARTITION OF prices DEFAULT;
+
+-- Создание секции prices_YYYY_MM для месяца (границы в UTC).
+-- Строки, успевшие попасть в секцию по умолчанию, переносятся в новую секцию.
+CREATE OR REPLACE FUNCTION create_prices_partition(month_start DATE) RETURNS TEXT AS $$
+DECLARE
+    partition_name TEXT := 'prices_' || to_char(month_start, 'YYYY_MM');
+    lower_bound TIMESTAMP WITH TIME ZONE := (to_char(month_start, 'YYYY-MM') || '-01 00:00:00+00')::timestamptz;
+    upper_bound TIMESTAMP WITH TIME ZONE := (to_char(month_start, 'YYYY-MM') || '-01 00:00:00+00')::timestamptz + INTERVAL '1 month';
+    has_default_rows BOOLEAN;
+BEGIN
+    IF to_regclass(partition_name) IS NOT NULL THEN
+        RETURN partition_name;
+    END IF;
+
+    SELECT EXISTS (SELECT 1 FROM prices_default WHERE timestamp >= lower_bound AND timestamp < upper_bound)
+    INTO has_default_rows;
+
+    IF has_default_rows THEN
+        ALTER TABLE prices DETACH PARTITION prices_default;
+    END IF;
+
+    EXECUTE format('CREATE TABLE %I PARTITION OF prices FOR VALUES FROM (%L) TO (%L)',
+        partition_name, lower_bound, upper_bound);
+
+    IF has_default_rows THEN
+        EXECUTE format('WITH moved AS (DELETE FROM prices_default WHERE timestamp >= %L AND timestamp < %L RETURNING *) INSERT INTO %I SELECT * FROM moved',
+            lower_bound, upper_bound, partition_name);
+        ALTER TABLE prices ATTACH PARTITION prices_default DEFAULT;
+    END IF;
+
+    RETURN partition_name;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Секции для существующих данных и ближайших месяцев
+DO $$
+DECLARE
+    month_start DATE;
+BEGIN
+    SELECT date_trunc('month', COALESCE(MIN(timestamp), now()) AT TIME ZONE 'UTC')::date
+    INTO month_start
+    FROM prices_legacy;
+
+    WHILE month_start <= (date_trunc('month', now() AT TIME ZONE 'UTC') + INTERVAL '2 months')::date LOOP
+        PERFORM create_prices_partition(month_start);
+        month_start := (month_start + INTERVAL '1 month')::date;
+    END LOOP;
+END;
+$$;
+
+INSERT INTO prices (id, currency_id, price, timestamp, created_at, updated_at, deleted_at)
+SELECT id, currency_id, price, timestamp, created_at, updated_at, deleted_at
+FROM prices_legacy;
+
+DROP TABLE prices_legacy;
+ALTER SEQUENCE prices_id_seq OWNED BY prices.id;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS price_rollups_1d;
+DROP TABLE IF EXISTS price_rollups_1h;
+DROP TABLE IF EXISTS price_rollups_1m;
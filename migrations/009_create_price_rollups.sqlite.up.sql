@@ -0,0 +1,50 @@
+-- Агрегаты цен по минутам, часам и дням (SQLite).
+-- Воркер не обновляет их для SQLite, таблицы нужны для единой схемы.
+CREATE TABLE IF NOT EXISTS price_rollups_1m (
+    currency_id INTEGER NOT NULL REFERENCES currencies(id) ON DELETE CASCADE,
+    bucket DATETIME NOT NULL,
+    open TEXT NOT NULL,
+    high TEXT NOT NULL,
+    low TEXT NOT NULL,
+    close TEXT NOT NULL,
+    avg TEXT NOT NULL,
+    sample_count BIGINT NOT NULL,
+    first_at DATETIME NOT NULL,
+    last_at DATETIME NOT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (currency_id, bucket)
+);
+
+CREATE TABLE IF NOT EXISTS price_rollups_1h (
+    currency_id INTEGER NOT NULL REFERENCES currencies(id) ON DELETE CASCADE,
+    bucket DATETIME NOT NULL,
+    open TEXT NOT NULL,
+    high TEXT NOT NULL,
+    low TEXT NOT NULL,
+    close TEXT NOT NULL,
+    avg TEXT NOT NULL,
+    sample_count BIGINT NOT NULL,
+    first_at DATETIME NOT NULL,
+    last_at DATETIME NOT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (currency_id, bucket)
+);
+
+CREATE TABLE IF NOT EXISTS price_rollups_1d (
+    currency_id INTEGER NOT NULL REFERENCES currencies(id) ON DELETE CASCADE,
+    bucket DATETIME NOT NULL,
+    open TEXT NOT NULL,
+    high TEXT NOT NULL,
+    low TEXT NOT NULL,
+    close TEXT NOT NULL,
+    avg TEXT NOT NULL,
+    sample_count BIGINT NOT NULL,
+    first_at DATETIME NOT NULL,
+    last_at DATETIME NOT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (currency_id, bucket)
+);
+
+CREATE INDEX IF NOT EXISTS idx_price_rollups_1m_bucket ON price_rollups_1m(bucket);
+CREATE INDEX IF NOT EXISTS idx_price_rollups_1h_bucket ON price_rollups_1h(bucket);
+CREATE INDEX IF NOT EXISTS idx_price_rollups_1d_bucket ON price_rollups_1d(bucket);
@@ -0,0 +1,27 @@
+-- Агрегаты цен по минутам, часам и дням.
+-- first_at/last_at - время первой и последней исходной цены в интервале.
+CREATE TABLE IF NOT EXISTS price_rollups_1m (
+    currency_id INTEGER NOT NULL REFERENCES currencies(id) ON DELETE CASCADE,
+    bucket TIMESTAMP WITH TIME ZONE NOT NULL,
+    open NUMERIC(38, 18) NOT NULL,
+    high NUMERIC(38, 18) NOT NULL,
+    low NUMERIC(38, 18) NOT NULL,
+    close NUMERIC(38, 18) NOT NULL,
+    avg NUMERIC(38, 18) NOT NULL,
+    sample_count BIGINT NOT NULL,
+    first_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (currency_id, bucket)
+);
+
+CREATE TABLE IF NOT EXISTS price_rollups_1h (LIKE price_rollups_1m INCLUDING ALL);
+ALTER TABLE price_rollups_1h ADD FOREIGN KEY (currency_id) REFERENCES currencies(id) ON DELETE CASCADE;
+
+CREATE TABLE IF NOT EXISTS price_rollups_1d (LIKE price_rollups_1m INCLUDING ALL);
+ALTER TABLE price_rollups_1d ADD FOREIGN KEY (currency_id) REFERENCES currencies(id) ON DELETE CASCADE;
+
+-- Индексы для поиска ближайшей цены по времени исходных значений
+CREATE INDEX IF NOT EXISTS idx_price_rollups_1m_bucket ON price_rollups_1m(bucket);
+CREATE INDEX IF NOT EXISTS idx_price_rollups_1h_bucket ON price_rollups_1h(bucket);
+CREATE INDEX IF NOT EXISTS idx_price_rollups_1d_bucket ON price_rollups_1d(bucket);
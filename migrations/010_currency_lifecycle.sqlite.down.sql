@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_currency_transitions_currency_id;
+DROP TABLE IF EXISTS currency_transitions;
+
+ALTER TABLE currencies ADD COLUMN is_active BOOLEAN NOT NULL DEFAULT 1;
+UPDATE currencies SET is_active = (status = 'active');
+CREATE INDEX IF NOT EXISTS idx_currencies_active ON currencies(is_active) WHERE is_active = 1;
+
+DROP INDEX IF EXISTS idx_currencies_status;
+ALTER TABLE currencies DROP COLUMN status;
@@ -0,0 +1,24 @@
+-- Состояние жизненного цикла валюты вместо флага is_active (SQLite)
+ALTER TABLE currencies ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'active'
+    CHECK (status IN ('active', 'paused', 'archived', 'deleted'));
+UPDATE currencies SET status = CASE
+    WHEN deleted_at IS NOT NULL THEN 'deleted'
+    WHEN is_active THEN 'active'
+    ELSE 'paused'
+END;
+
+DROP INDEX IF EXISTS idx_currencies_active;
+ALTER TABLE currencies DROP COLUMN is_active;
+CREATE INDEX IF NOT EXISTS idx_currencies_status ON currencies(status);
+
+CREATE TABLE IF NOT EXISTS currency_transitions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    currency_id INTEGER NOT NULL REFERENCES currencies(id) ON DELETE CASCADE,
+    from_status VARCHAR(20) NOT NULL,
+    to_status VARCHAR(20) NOT NULL,
+    actor VARCHAR(100) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_currency_transitions_currency_id ON currency_transitions(currency_id, created_at);
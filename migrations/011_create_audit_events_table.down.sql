@@ -0,0 +1,7 @@
+-- Удаление индексов
+DROP INDEX IF EXISTS idx_audit_events_actor;
+DROP INDEX IF EXISTS idx_audit_events_target;
+DROP INDEX IF EXISTS idx_audit_events_created_at;
+
+-- Удаление таблицы
+DROP TABLE IF EXISTS audit_events;
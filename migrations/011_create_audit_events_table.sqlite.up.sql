@@ -0,0 +1,17 @@
+-- Журнал изменений, сделанных через API (SQLite)
+CREATE TABLE IF NOT EXISTS audit_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    actor VARCHAR(100) NOT NULL,
+    action VARCHAR(50) NOT NULL,
+    target_type VARCHAR(50) NOT NULL,
+    target_id VARCHAR(100) NOT NULL,
+    before TEXT,
+    after TEXT,
+    request_id VARCHAR(100) NOT NULL DEFAULT '',
+    client_ip VARCHAR(45) NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_created_at ON audit_events(created_at);
+CREATE INDEX IF NOT EXISTS idx_audit_events_target ON audit_events(target_type, target_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_audit_events_actor ON audit_events(actor, created_at);